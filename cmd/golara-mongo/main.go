@@ -88,17 +88,26 @@ import (
 
 	"github.com/taeyelor/golara/framework"
 	"github.com/taeyelor/golara/framework/database"
-	httpMW "github.com/taeyelor/golara/framework/http"
 	"github.com/taeyelor/golara/framework/routing"
 )
 
+// Build metadata, set with:
+// go build -ldflags "-X main.version=1.0.0 -X main.commit=$(git rev-parse HEAD) -X main.buildTime=$(date -u +%%Y-%%m-%%dT%%H:%%M:%%SZ)"
+var (
+	version   = "dev"
+	commit    = ""
+	buildTime = ""
+)
+
 func main() {
 	app := framework.NewApplication()
+	app.SetVersion(version, commit, buildTime)
+	app.EnableVersionEndpoint("/version")
 
-	// Global middleware
-	app.Use(httpMW.LoggingMiddleware)
-	app.Use(httpMW.RecoveryMiddleware)
-	app.Use(httpMW.CORSMiddleware([]string{"*"}))
+	// Logging, recovery and CORS middleware are enabled in .env
+
+	// Files in public/ are served under /assets, matching the asset view helper
+	app.Static("/assets", "public")
 
 	// Connect to MongoDB
 	mongoURI := app.Config.GetString("database.connections.mongodb.uri", "mongodb://localhost:27017")
@@ -154,6 +163,11 @@ APP_ENV=local
 APP_DEBUG=true
 APP_PORT=:8080
 
+HTTP_LOGGING_ENABLED=true
+HTTP_RECOVERY_ENABLED=true
+HTTP_CORS_ENABLED=true
+HTTP_CORS_ORIGINS=*
+
 DB_CONNECTION=mongodb
 MONGODB_URI=mongodb://localhost:27017
 MONGODB_DATABASE=%s
@@ -474,7 +488,7 @@ func (m *%s) BeforeInsert() {
 func (m *%s) BeforeUpdate() {
 	m.UpdatedAt = time.Now()
 }
-`, name, name, name, name, name, strings.ToLower(name)+"s", name, name, name, name, name, name, name, name, name, name, name, strings.ToLower(name)+"s", name, name, strings.ToLower(name)+"s", name, strings.ToLower(name)+"s", name, name)
+`, name, name, name, name, name, strings.ToLower(name)+"s", name, name, name, name, name, name, name, name, name, name, name, strings.ToLower(name)+"s", name, strings.ToLower(name)+"s", name, name)
 
 	filename := fmt.Sprintf("models/%s.go", strings.ToLower(name))
 	writeFile(filename, content)
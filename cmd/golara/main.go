@@ -87,17 +87,26 @@ import (
 	"log"
 
 	"github.com/taeyelor/golara/framework"
-	httpMW "github.com/taeyelor/golara/framework/http"
 	"github.com/taeyelor/golara/framework/routing"
 )
 
+// Build metadata, set with:
+// go build -ldflags "-X main.version=1.0.0 -X main.commit=$(git rev-parse HEAD) -X main.buildTime=$(date -u +%%Y-%%m-%%dT%%H:%%M:%%SZ)"
+var (
+	version   = "dev"
+	commit    = ""
+	buildTime = ""
+)
+
 func main() {
 	app := framework.NewApplication()
+	app.SetVersion(version, commit, buildTime)
+	app.EnableVersionEndpoint("/version")
 
-	// Global middleware
-	app.Use(httpMW.LoggingMiddleware)
-	app.Use(httpMW.RecoveryMiddleware)
-	app.Use(httpMW.CORSMiddleware([]string{"*"}))
+	// Logging, recovery and CORS middleware are enabled in .env
+
+	// Files in public/ are served under /assets, matching the asset view helper
+	app.Static("/assets", "public")
 
 	// Routes
 	app.GET("/", func(c *routing.Context) {
@@ -128,6 +137,11 @@ APP_ENV=local
 APP_DEBUG=true
 APP_PORT=:8080
 
+HTTP_LOGGING_ENABLED=true
+HTTP_RECOVERY_ENABLED=true
+HTTP_CORS_ENABLED=true
+HTTP_CORS_ORIGINS=*
+
 DB_CONNECTION=mysql
 DB_HOST=127.0.0.1
 DB_PORT=3306
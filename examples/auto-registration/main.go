@@ -27,8 +27,8 @@ func main() {
 
 	// Example route to show database service
 	app.GET("/db-status", func(w http.ResponseWriter, r *http.Request) {
-		db := app.Resolve("db")
-		if db == nil {
+		db, ok := app.TryResolve("db")
+		if !ok {
 			http.Error(w, "Database not connected", http.StatusServiceUnavailable)
 			return
 		}
@@ -59,8 +59,8 @@ func main() {
 
 	// Example route to show RabbitMQ service
 	app.GET("/rabbitmq-status", func(w http.ResponseWriter, r *http.Request) {
-		rabbit := app.Resolve("rabbitmq")
-		if rabbit == nil {
+		rabbit, ok := app.TryResolve("rabbitmq")
+		if !ok {
 			http.Error(w, "RabbitMQ not available", http.StatusServiceUnavailable)
 			return
 		}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 
@@ -36,7 +37,9 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to connect to MongoDB:", err)
 	}
-	defer db.Disconnect()
+	app.OnShutdown(func(ctx context.Context) error {
+		return db.Disconnect()
+	})
 
 	// Register database in service container
 	app.Singleton("database", func() interface{} {
@@ -53,7 +56,8 @@ func main() {
 
 	// API group with common prefix
 	api := app.Group("/api/v1")
-	api.GET("/health", healthCheckHandler)
+	app.AddHealthCheck("mongo", app.ServiceHealthCheck("db"))
+	api.GET("/health", app.HealthHandler())
 	api.GET("/stats", statsHandler)
 
 	// Start server
@@ -123,33 +127,29 @@ func createUserHandler(c *routing.Context) {
 func listUsersHandler(c *routing.Context) {
 	db := getDB()
 
-	page := c.QueryDefault("page", "1")
-	limit := c.QueryDefault("limit", "10")
+	page := c.QueryInt64("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt64("limit", 10)
+	if limit < 1 {
+		limit = 10
+	}
 
 	var users []User
-	err := db.NewQueryBuilder().
+	pagination, err := db.NewQueryBuilder().
 		Collection("users").
 		OrderBy("created_at", "DESC").
-		Limit(10).
-		Get(&users)
+		Paginate(page, limit, &users)
 
 	if err != nil {
 		c.JSON(500, map[string]string{"error": "Failed to fetch users"})
 		return
 	}
 
-	// Count total users
-	total, _ := db.NewQueryBuilder().
-		Collection("users").
-		Count()
-
 	c.JSON(200, map[string]interface{}{
-		"users": users,
-		"pagination": map[string]interface{}{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
+		"users":      users,
+		"pagination": pagination,
 	})
 }
 
@@ -217,25 +217,6 @@ func deleteUserHandler(c *routing.Context) {
 	c.JSON(200, map[string]string{"message": "User deleted successfully"})
 }
 
-func healthCheckHandler(c *routing.Context) {
-	db := getDB()
-
-	// Check MongoDB connection
-	if err := db.Ping(); err != nil {
-		c.JSON(503, map[string]string{
-			"status":   "error",
-			"database": "disconnected",
-		})
-		return
-	}
-
-	c.JSON(200, map[string]string{
-		"status":   "ok",
-		"service":  "golara-api",
-		"database": "connected",
-	})
-}
-
 func statsHandler(c *routing.Context) {
 	db := getDB()
 
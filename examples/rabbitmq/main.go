@@ -3,9 +3,7 @@ package main
 import (
 	"context"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
+	"net/http"
 	"time"
 
 	"github.com/taeyelor/golara/framework"
@@ -36,29 +34,20 @@ func main() {
 	// Setup routes for API endpoints
 	setupRoutes(app)
 
-	// Start background job processors
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Start email processor
-	go startEmailProcessor(ctx, app)
-
-	// Start notification processor
-	go startNotificationProcessor(ctx, app) // Start web server
-	go func() {
-		log.Println("Starting web server on :8080")
-		if err := app.Run(":8080"); err != nil {
-			log.Printf("Server error: %v", err)
-		}
-	}()
-
-	// Wait for interrupt signal
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	<-c
+	// Background job processors stop when the application shuts down, and
+	// shutdown waits for them before closing the RabbitMQ connection
+	app.Go(func(ctx context.Context) {
+		startEmailProcessor(ctx, app)
+	})
+	app.Go(func(ctx context.Context) {
+		startNotificationProcessor(ctx, app)
+	})
 
-	log.Println("Shutting down...")
-	cancel()
+	// Start web server; SIGINT/SIGTERM stops the server and cancels ctx
+	log.Println("Starting web server on :8080")
+	if err := app.Run(":8080"); err != nil && err != http.ErrServerClosed {
+		log.Printf("Server error: %v", err)
+	}
 }
 
 func setupRoutes(app *framework.Application) {
@@ -143,22 +132,8 @@ func setupRoutes(app *framework.Application) {
 		})
 	})
 
-	// Health check
-	api.GET("/health", func(c *routing.Context) {
-		// Use the built-in health check helper
-		health := rabbitmq.QueueHealthCheck(app)
-
-		if health["status"] == "error" {
-			c.JSON(503, health)
-			return
-		}
-
-		c.JSON(200, map[string]interface{}{
-			"status":   "ok",
-			"rabbitmq": "connected",
-			"stats":    health["stats"],
-		})
-	})
+	// Health check (RegisterRabbitMQFromEnv adds the "rabbitmq" check)
+	api.GET("/health", app.HealthHandler())
 }
 
 func startEmailProcessor(ctx context.Context, app *framework.Application) {
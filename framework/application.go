@@ -2,10 +2,15 @@ package framework
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -13,6 +18,7 @@ import (
 	"github.com/taeyelor/golara/framework/container"
 	"github.com/taeyelor/golara/framework/database"
 	"github.com/taeyelor/golara/framework/routing"
+	"github.com/taeyelor/golara/framework/view"
 )
 
 // Application is the main application structure
@@ -21,82 +27,346 @@ type Application struct {
 	Container *container.Container
 	Config    *config.Config
 	server    *http.Server
+	serverMux sync.Mutex
+	serving   bool
+	version   VersionInfo
+
+	// cors is the CORS middleware built from configuration, nil when disabled
+	cors atomic.Pointer[func(http.Handler) http.Handler]
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	shutdownHooks []func(ctx context.Context) error
+	shutdownMux   sync.Mutex
+	shutdownDone  chan struct{}
+	shutdownOnce  sync.Once
+	tasks         sync.WaitGroup
+
+	configReload    bool
+	reloadCallbacks []func(*config.Config)
+	reloadMux       sync.Mutex
+
+	healthChecks map[string]HealthCheck
+	healthMux    sync.Mutex
 }
 
 // NewApplication creates a new application instance
 func NewApplication() *Application {
 	app := &Application{
-		Router:    routing.NewRouter(),
-		Container: container.NewContainer(),
-		Config:    config.NewConfig(),
+		Router:       routing.NewRouter(),
+		Container:    container.NewContainer(),
+		Config:       config.NewConfig(),
+		shutdownDone: make(chan struct{}),
 	}
+	app.ctx, app.cancel = context.WithCancel(context.Background())
 
 	// Register core services
 	app.registerCoreServices()
 
+	// Encrypt secure cookies with app.key, accepting rotated-out previous keys
+	app.Router.SetCookieKeys(app.cookieKeys()...)
+
+	// Give each request a service scope of the application container
+	app.Router.SetContainer(app.Container)
+
+	// Register middleware switched on in configuration
+	app.registerConfiguredMiddleware()
+
+	// Run OnConfigReload callbacks after every configuration reload
+	app.Config.OnReload(app.configReloaded)
+
 	return app
 }
 
 // registerCoreServices registers the core framework services
 func (app *Application) registerCoreServices() {
 	// Register core framework services
-	app.Container.Singleton("config", func() interface{} {
+	app.Container.Global("config", func() interface{} {
 		return app.Config
 	})
 
-	app.Container.Singleton("router", func() interface{} {
+	app.Container.Global("router", func() interface{} {
 		return app.Router
 	})
 
 	// Auto-register database service (MongoDB ODM)
-	app.Container.Singleton("db", func() interface{} {
+	app.Container.Global("db", func() interface{} {
 		// Get database config
 		uri := app.Config.Get("database.connections.mongodb.uri", "mongodb://localhost:27017").(string)
 		dbName := app.Config.Get("database.connections.mongodb.database", "golara").(string)
 
-		db, err := database.Connect(uri, dbName)
+		optionsConfig, _ := app.Config.Get("database.connections.mongodb.options").(map[string]interface{})
+		opts, err := database.ClientOptionsFromConfig(optionsConfig)
 		if err != nil {
 			log.Printf("Failed to connect to database: %v", err)
 			return nil
 		}
+
+		db, err := database.ConnectWithOptions(uri, dbName, opts)
+		if err != nil {
+			log.Printf("Failed to connect to database: %v", err)
+			return nil
+		}
+		app.OnShutdown(func(ctx context.Context) error {
+			return db.Client.Disconnect(ctx)
+		})
+
+		// Bound every query by the configured timeout
+		if timeout, err := time.ParseDuration(app.Config.GetString("database.connections.mongodb.options.timeout")); err == nil {
+			db.SetDefaultTimeout(timeout)
+		}
 		return db
 	})
 
+	// Make core services injectable by type (see Container.Call)
+	container.Alias[*config.Config](app.Container, "config")
+	container.Alias[*routing.Router](app.Container, "router")
+	container.Alias[*database.DB](app.Container, "db")
+
 	// Auto-register RabbitMQ service if enabled
 	if app.Config.Get("rabbitmq.enabled", false).(bool) {
 		// Register RabbitMQ factory function that will be lazy-loaded
-		app.Container.Singleton("rabbitmq", app.createRabbitMQFactory())
+		app.Container.Global("rabbitmq", app.createRabbitMQFactory())
+	}
+}
+
+// cookieKeys returns app.key followed by the comma-separated app.previous_keys
+func (app *Application) cookieKeys() []string {
+	keys := []string{app.Config.GetString("app.key")}
+	for _, key := range strings.Split(app.Config.GetString("app.previous_keys"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
 	}
+	return keys
 }
 
 // Run starts the application server
 func (app *Application) Run(addr string) error {
-	if addr == "" {
-		addr = app.Config.Get("app.port", ":8080").(string)
+	return app.serve(addr, func(server *http.Server) error {
+		log.Printf("Server starting on %s", server.Addr)
+		return server.ListenAndServe()
+	})
+}
+
+// RunTLS starts the application server over HTTPS using the certificate and
+// matching private key in the given PEM files
+func (app *Application) RunTLS(addr, certFile, keyFile string) error {
+	return app.serve(addr, func(server *http.Server) error {
+		log.Printf("Server starting on %s (TLS)", server.Addr)
+		return server.ListenAndServeTLS(certFile, keyFile)
+	})
+}
+
+// serve creates the HTTP server, installs signal handling and runs listen
+// until the server stops
+func (app *Application) serve(addr string, listen func(server *http.Server) error) error {
+	server := app.Server()
+	if addr != "" {
+		server.Addr = addr
+	} else if server.Addr == "" {
+		server.Addr = app.Config.Get("app.port", ":8080").(string)
 	}
 
-	app.server = &http.Server{
-		Addr:    addr,
-		Handler: app.Router,
+	app.serverMux.Lock()
+	app.serving = true
+	app.serverMux.Unlock()
+	defer func() {
+		app.serverMux.Lock()
+		app.serving = false
+		app.serverMux.Unlock()
+	}()
+
+	serveDone := make(chan struct{})
+	defer close(serveDone)
+
+	// Reload configuration on SIGHUP when enabled
+	if app.configReloadEnabled() {
+		hupChan := make(chan os.Signal, 1)
+		signal.Notify(hupChan, syscall.SIGHUP)
+		go func() {
+			defer signal.Stop(hupChan)
+			for {
+				select {
+				case <-hupChan:
+				case <-serveDone:
+					return
+				case <-app.ctx.Done():
+					return
+				}
+
+				log.Println("SIGHUP received, reloading configuration...")
+				if err := app.ReloadConfig(); err != nil {
+					log.Printf("Config reload error: %v", err)
+				}
+			}
+		}()
 	}
 
 	// Graceful shutdown
 	go func() {
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-		<-sigChan
+		defer signal.Stop(sigChan)
+		select {
+		case <-sigChan:
+		case <-app.ctx.Done():
+			// Shutdown was called directly
+			return
+		case <-serveDone:
+			return
+		}
 
 		log.Println("Shutting down server...")
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		if err := app.server.Shutdown(ctx); err != nil {
+		if err := app.Shutdown(ctx); err != nil {
 			log.Printf("Server shutdown error: %v", err)
 		}
 	}()
 
-	log.Printf("Server starting on %s", addr)
-	return app.server.ListenAndServe()
+	err := listen(server)
+	if err == http.ErrServerClosed {
+		// Wait for background tasks and OnShutdown hooks to finish
+		<-app.shutdownDone
+	}
+	return err
+}
+
+// Server returns the HTTP server used by Run and RunTLS, creating it on
+// first use with the timeouts from the server.* configuration. Settings
+// changed on it before Run, such as TLSConfig or MaxHeaderBytes, are kept.
+func (app *Application) Server() *http.Server {
+	app.serverMux.Lock()
+	defer app.serverMux.Unlock()
+
+	if app.server == nil {
+		app.server = &http.Server{Handler: app.Router}
+		app.serverTimeouts().apply(app.server)
+	}
+	return app.server
+}
+
+// serverTimeouts are the timeouts set on the HTTP server
+type serverTimeouts struct {
+	read, readHeader, write, idle time.Duration
+}
+
+// serverTimeouts reads the server.* timeouts from configuration
+func (app *Application) serverTimeouts() serverTimeouts {
+	return serverTimeouts{
+		read:       app.Config.GetDuration("server.read_timeout", 30*time.Second),
+		readHeader: app.Config.GetDuration("server.read_header_timeout", 10*time.Second),
+		write:      app.Config.GetDuration("server.write_timeout", 30*time.Second),
+		idle:       app.Config.GetDuration("server.idle_timeout", 120*time.Second),
+	}
+}
+
+// apply sets the timeouts on server
+func (t serverTimeouts) apply(server *http.Server) {
+	server.ReadTimeout = t.read
+	server.ReadHeaderTimeout = t.readHeader
+	server.WriteTimeout = t.write
+	server.IdleTimeout = t.idle
+}
+
+// applyServerTimeouts re-applies the server.* timeouts after a reload. The
+// fields of a running http.Server cannot be changed safely, so while it is
+// serving a changed timeout is only logged.
+func (app *Application) applyServerTimeouts() {
+	app.serverMux.Lock()
+	defer app.serverMux.Unlock()
+
+	if app.server == nil {
+		return
+	}
+	timeouts := app.serverTimeouts()
+	if !app.serving {
+		timeouts.apply(app.server)
+		return
+	}
+
+	current := serverTimeouts{app.server.ReadTimeout, app.server.ReadHeaderTimeout, app.server.WriteTimeout, app.server.IdleTimeout}
+	if timeouts != current {
+		log.Println("Changed server.* timeouts take effect after a restart")
+	}
+}
+
+// Context returns the application's root context. It is cancelled when the
+// application shuts down, so background goroutines such as queue consumers
+// should use it to know when to stop.
+func (app *Application) Context() context.Context {
+	return app.ctx
+}
+
+// Go runs task in a background goroutine with the application context.
+// Shutdown cancels the context and waits for the task to return before
+// running the OnShutdown hooks, so tasks such as queue consumers finish
+// their in-flight work while their connections are still open.
+func (app *Application) Go(task func(ctx context.Context)) {
+	app.tasks.Add(1)
+	go func() {
+		defer app.tasks.Done()
+		task(app.ctx)
+	}()
+}
+
+// Shutdown gracefully stops the HTTP server, waiting for in-flight requests
+// until ctx expires, cancels the application context, waits for tasks
+// started with Go and then runs the OnShutdown hooks. Run and RunTLS return
+// once the first Shutdown has finished.
+func (app *Application) Shutdown(ctx context.Context) error {
+	defer app.shutdownOnce.Do(func() { close(app.shutdownDone) })
+
+	app.serverMux.Lock()
+	server := app.server
+	app.serverMux.Unlock()
+
+	var errs []error
+	if server != nil {
+		if err := server.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	app.cancel()
+
+	tasksDone := make(chan struct{})
+	go func() {
+		app.tasks.Wait()
+		close(tasksDone)
+	}()
+	select {
+	case <-tasksDone:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("background tasks still running: %w", ctx.Err()))
+	}
+
+	app.shutdownMux.Lock()
+	hooks := app.shutdownHooks
+	app.shutdownHooks = nil
+	app.shutdownMux.Unlock()
+
+	// Run hooks in reverse order, so services shut down before the
+	// services they were built on
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OnShutdown registers a cleanup function, such as closing a database or
+// message broker connection, run once by Shutdown after the HTTP server has
+// stopped. Hooks run in reverse registration order and share Shutdown's
+// context, which is limited to 10 seconds on SIGINT/SIGTERM.
+func (app *Application) OnShutdown(hook func(ctx context.Context) error) {
+	app.shutdownMux.Lock()
+	defer app.shutdownMux.Unlock()
+
+	app.shutdownHooks = append(app.shutdownHooks, hook)
 }
 
 // Bind registers a service in the container
@@ -104,9 +374,11 @@ func (app *Application) Bind(name string, resolver func() interface{}) {
 	app.Container.Bind(name, resolver)
 }
 
-// Singleton registers a singleton service in the container
+// Singleton registers a service constructed once and shared by the whole
+// application, including request scopes (see container.Container.Global).
+// Use app.Container.Singleton for one instance per request scope.
 func (app *Application) Singleton(name string, resolver func() interface{}) {
-	app.Container.Singleton(name, resolver)
+	app.Container.Global(name, resolver)
 }
 
 // Resolve resolves a service from the container
@@ -114,34 +386,57 @@ func (app *Application) Resolve(name string) interface{} {
 	return app.Container.Resolve(name)
 }
 
-// Group creates a route group with common middleware and prefix
-func (app *Application) Group(prefix string, middleware ...func(http.Handler) http.Handler) *routing.Group {
+// TryResolve resolves an optional service, reporting false when it is not
+// registered or could not be created
+func (app *Application) TryResolve(name string) (interface{}, bool) {
+	return app.Container.TryResolve(name)
+}
+
+// SetViewEngine wires a view engine into the router and registers it as "view"
+func (app *Application) SetViewEngine(engine *view.Engine) {
+	app.Router.SetViewEngine(engine)
+	app.Container.Instance("view", engine)
+}
+
+// Group creates a route group with common middleware and prefix. Middleware
+// may be http style or routing.ContextMiddleware.
+func (app *Application) Group(prefix string, middleware ...interface{}) *routing.Group {
 	return app.Router.Group(prefix, middleware...)
 }
 
 // GET registers a GET route
-func (app *Application) GET(path string, handler interface{}) {
-	app.Router.GET(path, handler)
+func (app *Application) GET(path string, handler interface{}) *routing.Route {
+	return app.Router.GET(path, handler)
 }
 
 // POST registers a POST route
-func (app *Application) POST(path string, handler interface{}) {
-	app.Router.POST(path, handler)
+func (app *Application) POST(path string, handler interface{}) *routing.Route {
+	return app.Router.POST(path, handler)
 }
 
 // PUT registers a PUT route
-func (app *Application) PUT(path string, handler interface{}) {
-	app.Router.PUT(path, handler)
+func (app *Application) PUT(path string, handler interface{}) *routing.Route {
+	return app.Router.PUT(path, handler)
 }
 
 // DELETE registers a DELETE route
-func (app *Application) DELETE(path string, handler interface{}) {
-	app.Router.DELETE(path, handler)
+func (app *Application) DELETE(path string, handler interface{}) *routing.Route {
+	return app.Router.DELETE(path, handler)
 }
 
 // PATCH registers a PATCH route
-func (app *Application) PATCH(path string, handler interface{}) {
-	app.Router.PATCH(path, handler)
+func (app *Application) PATCH(path string, handler interface{}) *routing.Route {
+	return app.Router.PATCH(path, handler)
+}
+
+// Fallback registers a handler for unmatched GET and HEAD requests
+func (app *Application) Fallback(handler interface{}) *routing.Route {
+	return app.Router.Fallback(handler)
+}
+
+// Static serves the files under dir below urlPrefix (see Router.Static)
+func (app *Application) Static(urlPrefix, dir string, config ...routing.StaticConfig) *routing.Route {
+	return app.Router.Static(urlPrefix, dir, config...)
 }
 
 // Use registers global middleware
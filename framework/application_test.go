@@ -0,0 +1,95 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// freeAddr returns a loopback address with a free port
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// waitListening waits until the server at addr accepts connections
+func waitListening(t *testing.T, addr string) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if conn, err := net.Dial("tcp", addr); err == nil {
+			conn.Close()
+			return
+		}
+	}
+	t.Fatalf("server at %s did not start", addr)
+}
+
+func TestRunReturnsAfterShutdownFinishes(t *testing.T) {
+	app := NewApplication()
+
+	var mutex sync.Mutex
+	var events []string
+	record := func(event string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		events = append(events, event)
+	}
+
+	app.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond) // finish in-flight work
+		record("task")
+	})
+	app.OnShutdown(func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		record("hook")
+		return nil
+	})
+
+	addr := freeAddr(t)
+	runErr := make(chan error, 1)
+	go func() { runErr <- app.Run(addr) }()
+	waitListening(t, addr)
+
+	go app.Shutdown(context.Background())
+
+	select {
+	case err := <-runErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			t.Fatalf("Run returned %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after Shutdown")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(events) != 2 || events[0] != "task" || events[1] != "hook" {
+		t.Errorf("events when Run returned = %v, want [task hook]", events)
+	}
+}
+
+func TestShutdownReportsTasksStillRunning(t *testing.T) {
+	app := NewApplication()
+	release := make(chan struct{})
+	defer close(release)
+	app.Go(func(ctx context.Context) { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := app.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v, want a deadline error", err)
+	}
+	if app.Context().Err() == nil {
+		t.Error("application context not cancelled")
+	}
+}
@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taeyelor/golara/framework/config"
+)
+
+var (
+	// ErrInvalidToken is returned for malformed tokens, unsupported
+	// algorithms and bad signatures
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrTokenExpired is returned for tokens past their exp claim or
+	// before their nbf claim
+	ErrTokenExpired = errors.New("token expired or not yet valid")
+)
+
+// jwtHeader is the only header IssueToken produces and ParseToken accepts
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims holds a token's claims. Numeric claims decode as float64.
+type Claims map[string]interface{}
+
+// Subject returns the "sub" claim
+func (c Claims) Subject() string {
+	subject, _ := c["sub"].(string)
+	return subject
+}
+
+// ExpiresAt returns the "exp" claim, or the zero time if it is not set
+func (c Claims) ExpiresAt() time.Time {
+	return c.time("exp")
+}
+
+// time reads a NumericDate claim
+func (c Claims) time(name string) time.Time {
+	seconds, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(int64(seconds), 0)
+}
+
+// JWT issues and verifies HS256-signed JSON Web Tokens
+type JWT struct {
+	secret []byte
+	ttl    time.Duration
+	leeway time.Duration
+}
+
+// NewJWT creates a JWT signer. Tokens expire ttl after they are issued; a
+// ttl of zero issues tokens without an expiry. Secrets prefixed with
+// "base64:" are decoded first, like app.key.
+func NewJWT(secret string, ttl time.Duration) *JWT {
+	if encoded, ok := strings.CutPrefix(secret, "base64:"); ok {
+		if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+			secret = string(decoded)
+		}
+	}
+	return &JWT{secret: []byte(secret), ttl: ttl}
+}
+
+// NewJWTFromConfig creates a JWT signer using app.key as the secret,
+// auth.jwt_ttl (default 1h) as the token lifetime and auth.jwt_leeway
+// (default 0) as the leeway
+func NewJWTFromConfig(cfg *config.Config) (*JWT, error) {
+	secret := cfg.GetString("app.key")
+	if secret == "" {
+		return nil, fmt.Errorf("app.key must be set to sign tokens")
+	}
+	jwt := NewJWT(secret, cfg.GetDuration("auth.jwt_ttl", time.Hour))
+	return jwt.WithLeeway(cfg.GetDuration("auth.jwt_leeway", 0)), nil
+}
+
+// WithLeeway tolerates clock skew between the issuer and this server: tokens
+// stay valid for leeway after their exp claim and are accepted leeway before
+// their nbf claim. It returns j.
+func (j *JWT) WithLeeway(leeway time.Duration) *JWT {
+	j.leeway = leeway
+	return j
+}
+
+// IssueToken signs a token carrying claims plus "iat" and, when the signer
+// has a ttl, "exp". The claims map is not modified.
+func (j *JWT) IssueToken(claims map[string]interface{}) (string, error) {
+	now := time.Now()
+	payload := make(map[string]interface{}, len(claims)+2)
+	for name, value := range claims {
+		payload[name] = value
+	}
+	payload["iat"] = now.Unix()
+	if j.ttl > 0 {
+		payload["exp"] = now.Add(j.ttl).Unix()
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode claims: %w", err)
+	}
+
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(body)
+	return unsigned + "." + j.sign(unsigned), nil
+}
+
+// ParseToken verifies a token's signature and its exp and nbf claims,
+// allowing for the leeway, and returns its claims
+func (j *JWT) ParseToken(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != "HS256" {
+		return nil, ErrInvalidToken
+	}
+
+	expected := j.sign(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return nil, ErrInvalidToken
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	now := time.Now()
+	if exp := claims.time("exp"); !exp.IsZero() && !now.Before(exp.Add(j.leeway)) {
+		return nil, ErrTokenExpired
+	}
+	if nbf := claims.time("nbf"); !nbf.IsZero() && now.Before(nbf.Add(-j.leeway)) {
+		return nil, ErrTokenExpired
+	}
+
+	return claims, nil
+}
+
+// Validate parses a token for httpMW.BearerAuth, returning its Claims as
+// the principal:
+//
+//	api := app.Group("/api", httpMW.BearerAuth(jwt.Validate))
+func (j *JWT) Validate(token string) (interface{}, bool) {
+	claims, err := j.ParseToken(token)
+	if err != nil {
+		return nil, false
+	}
+	return claims, true
+}
+
+// sign returns the base64url HMAC-SHA256 signature of data
+func (j *JWT) sign(data string) string {
+	mac := hmac.New(sha256.New, j.secret)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
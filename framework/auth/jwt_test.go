@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taeyelor/golara/framework/config"
+)
+
+// signedToken builds a token with the given header and claims, signed by j
+// whatever its alg header says
+func signedToken(t *testing.T, j *JWT, header string, claims map[string]interface{}) string {
+	t.Helper()
+	body, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString(body)
+	return unsigned + "." + j.sign(unsigned)
+}
+
+func TestIssueAndParseToken(t *testing.T) {
+	jwt := NewJWT("secret", time.Hour)
+	input := map[string]interface{}{"sub": "42", "role": "admin"}
+
+	token, err := jwt.IssueToken(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := jwt.ParseToken(token)
+	if err != nil {
+		t.Fatalf("ParseToken = %v", err)
+	}
+
+	if claims.Subject() != "42" || claims["role"] != "admin" {
+		t.Errorf("claims = %v", claims)
+	}
+	if exp := time.Until(claims.ExpiresAt()); exp <= 59*time.Minute || exp > time.Hour {
+		t.Errorf("token expires in %v, want 1h", exp)
+	}
+	if _, ok := claims["iat"]; !ok {
+		t.Error("iat claim not set")
+	}
+	if len(input) != 2 {
+		t.Errorf("IssueToken modified its claims: %v", input)
+	}
+}
+
+func TestParseTokenWithoutTTL(t *testing.T) {
+	jwt := NewJWT("secret", 0)
+	token, _ := jwt.IssueToken(map[string]interface{}{"sub": "42"})
+
+	claims, err := jwt.ParseToken(token)
+	if err != nil {
+		t.Fatalf("ParseToken = %v", err)
+	}
+	if !claims.ExpiresAt().IsZero() {
+		t.Errorf("token without a ttl expires at %v", claims.ExpiresAt())
+	}
+}
+
+func TestBase64Secret(t *testing.T) {
+	encoded := "base64:" + base64.StdEncoding.EncodeToString([]byte("secret"))
+	token, _ := NewJWT(encoded, time.Hour).IssueToken(nil)
+
+	if _, err := NewJWT("secret", time.Hour).ParseToken(token); err != nil {
+		t.Errorf("token signed with the decoded secret rejected: %v", err)
+	}
+}
+
+func TestParseTokenRejectsTampering(t *testing.T) {
+	jwt := NewJWT("secret", time.Hour)
+	token, _ := jwt.IssueToken(map[string]interface{}{"sub": "42", "role": "user"})
+	parts := strings.Split(token, ".")
+
+	forged, _ := json.Marshal(map[string]interface{}{"sub": "42", "role": "admin"})
+	flipped := []byte(parts[2])
+	if flipped[0] == 'A' {
+		flipped[0] = 'B'
+	} else {
+		flipped[0] = 'A'
+	}
+	otherSecret, _ := NewJWT("other", time.Hour).IssueToken(map[string]interface{}{"sub": "42"})
+
+	tests := map[string]string{
+		"payload":      parts[0] + "." + base64.RawURLEncoding.EncodeToString(forged) + "." + parts[2],
+		"signature":    parts[0] + "." + parts[1] + "." + string(flipped),
+		"no signature": parts[0] + "." + parts[1] + ".",
+		"other secret": otherSecret,
+	}
+	for name, token := range tests {
+		if _, err := jwt.ParseToken(token); err != ErrInvalidToken {
+			t.Errorf("%s: ParseToken = %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
+
+func TestParseTokenRejectsOtherAlgorithms(t *testing.T) {
+	jwt := NewJWT("secret", time.Hour)
+	claims := map[string]interface{}{"sub": "42"}
+
+	unsigned := signedToken(t, jwt, `{"alg":"none","typ":"JWT"}`, claims)
+	tests := map[string]string{
+		"none unsigned": unsigned[:strings.LastIndex(unsigned, ".")+1],
+		"none signed":   unsigned,
+		"RS256":         signedToken(t, jwt, `{"alg":"RS256","typ":"JWT"}`, claims),
+		"lowercase":     signedToken(t, jwt, `{"alg":"hs256","typ":"JWT"}`, claims),
+		"missing alg":   signedToken(t, jwt, `{"typ":"JWT"}`, claims),
+	}
+	for name, token := range tests {
+		if _, err := jwt.ParseToken(token); err != ErrInvalidToken {
+			t.Errorf("%s: ParseToken = %v, want ErrInvalidToken", name, err)
+		}
+	}
+
+	if _, err := jwt.ParseToken(signedToken(t, jwt, `{"alg":"HS256"}`, claims)); err != nil {
+		t.Errorf("HS256 token without typ rejected: %v", err)
+	}
+}
+
+func TestParseTokenChecksTimes(t *testing.T) {
+	jwt := NewJWT("secret", 0)
+	now := time.Now().Unix()
+	header := `{"alg":"HS256","typ":"JWT"}`
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		err    error
+	}{
+		{"expired", map[string]interface{}{"exp": now - 10}, ErrTokenExpired},
+		{"expires now", map[string]interface{}{"exp": now}, ErrTokenExpired},
+		{"not expired", map[string]interface{}{"exp": now + 10}, nil},
+		{"not yet valid", map[string]interface{}{"nbf": now + 10}, ErrTokenExpired},
+		{"valid from now", map[string]interface{}{"nbf": now}, nil},
+	}
+	for _, tt := range tests {
+		if _, err := jwt.ParseToken(signedToken(t, jwt, header, tt.claims)); err != tt.err {
+			t.Errorf("%s: ParseToken = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestParseTokenLeeway(t *testing.T) {
+	jwt := NewJWT("secret", 0).WithLeeway(30 * time.Second)
+	now := time.Now().Unix()
+	header := `{"alg":"HS256","typ":"JWT"}`
+
+	// Claims are whole seconds, so stay a second clear of the boundary
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		err    error
+	}{
+		{"expired within leeway", map[string]interface{}{"exp": now - 29}, nil},
+		{"expired past leeway", map[string]interface{}{"exp": now - 31}, ErrTokenExpired},
+		{"not yet valid within leeway", map[string]interface{}{"nbf": now + 29}, nil},
+		{"not yet valid past leeway", map[string]interface{}{"nbf": now + 31}, ErrTokenExpired},
+	}
+	for _, tt := range tests {
+		if _, err := jwt.ParseToken(signedToken(t, jwt, header, tt.claims)); err != tt.err {
+			t.Errorf("%s: ParseToken = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestParseTokenRejectsMalformedTokens(t *testing.T) {
+	jwt := NewJWT("secret", time.Hour)
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	signed := func(header, payload string) string {
+		return header + "." + payload + "." + jwt.sign(header+"."+payload)
+	}
+
+	tests := map[string]string{
+		"empty":              "",
+		"one segment":        "abc",
+		"two segments":       header + ".e30",
+		"four segments":      signed(header, "e30") + ".extra",
+		"header not base64":  signed("!!!", "e30"),
+		"header not JSON":    signed(base64.RawURLEncoding.EncodeToString([]byte("HS256")), "e30"),
+		"payload not base64": signed(header, "!!!"),
+		"payload padded":     signed(header, "e30="),
+		"payload not JSON":   signed(header, base64.RawURLEncoding.EncodeToString([]byte("claims"))),
+		"payload not object": signed(header, base64.RawURLEncoding.EncodeToString([]byte("[1]"))),
+	}
+	for name, token := range tests {
+		if _, err := jwt.ParseToken(token); err != ErrInvalidToken {
+			t.Errorf("%s: ParseToken = %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
+
+func TestValidateReturnsClaims(t *testing.T) {
+	jwt := NewJWT("secret", time.Hour)
+	token, _ := jwt.IssueToken(map[string]interface{}{"sub": "42"})
+
+	principal, ok := jwt.Validate(token)
+	if claims, isClaims := principal.(Claims); !ok || !isClaims || claims.Subject() != "42" {
+		t.Errorf("Validate = %v, %v", principal, ok)
+	}
+	if principal, ok := jwt.Validate(token + "x"); ok || principal != nil {
+		t.Errorf("Validate accepted a bad token: %v", principal)
+	}
+}
+
+func TestNewJWTFromConfig(t *testing.T) {
+	cfg := config.NewConfig()
+	if _, err := NewJWTFromConfig(cfg); err == nil {
+		t.Error("NewJWTFromConfig succeeded without app.key")
+	}
+
+	cfg.Set("app.key", "secret")
+	cfg.Set("auth.jwt_ttl", "2h")
+	cfg.Set("auth.jwt_leeway", "1m")
+	jwt, err := NewJWTFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jwt.ttl != 2*time.Hour || jwt.leeway != time.Minute {
+		t.Errorf("ttl = %v, leeway = %v; want 2h and 1m", jwt.ttl, jwt.leeway)
+	}
+}
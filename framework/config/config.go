@@ -3,16 +3,23 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"log"
+	"math"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Config provides configuration management
 type Config struct {
-	data  map[string]interface{}
-	mutex sync.RWMutex
+	data      map[string]interface{}
+	providers []ConfigProvider
+	onChange  []func(key string)
+	onReload  []func()
+	watchStop chan struct{}
+	mutex     sync.RWMutex
 }
 
 // NewConfig creates a new config instance
@@ -24,6 +31,11 @@ func NewConfig() *Config {
 	// Load default configuration
 	config.loadDefaults()
 
+	// Load .env from the working directory; real environment variables win
+	if err := LoadDotEnv(".env"); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to load .env: %v", err)
+	}
+
 	// Load environment variables
 	config.loadFromEnv()
 
@@ -38,6 +50,11 @@ func (c *Config) loadDefaults() {
 		"app.debug":                             true,
 		"app.port":                              ":8080",
 		"app.key":                               "",
+		"app.previous_keys":                     "",
+		"server.read_timeout":                   "30s",
+		"server.read_header_timeout":            "10s",
+		"server.write_timeout":                  "30s",
+		"server.idle_timeout":                   "120s",
 		"database.default":                      "mongodb",
 		"database.connections.mongodb.uri":      "mongodb://localhost:27017",
 		"database.connections.mongodb.database": "golara",
@@ -63,41 +80,16 @@ func (c *Config) loadDefaults() {
 
 // loadFromEnv loads configuration from environment variables
 func (c *Config) loadFromEnv() {
-	envMappings := map[string]string{
-		// App configuration
-		"APP_NAME":  "app.name",
-		"APP_ENV":   "app.env",
-		"APP_DEBUG": "app.debug",
-		"APP_PORT":  "app.port",
-		"APP_KEY":   "app.key",
-
-		// Database configuration
-		"DB_CONNECTION":    "database.default",
-		"MONGODB_URI":      "database.connections.mongodb.uri",
-		"DB_DATABASE":      "database.connections.mongodb.database",
-		"MONGODB_DATABASE": "database.connections.mongodb.database",
-
-		// RabbitMQ configuration
-		"RABBITMQ_ENABLED":               "rabbitmq.enabled",
-		"RABBITMQ_URL":                   "rabbitmq.url",
-		"RABBITMQ_RECONNECT_DELAY":       "rabbitmq.reconnect_delay",
-		"RABBITMQ_RECONNECT_ATTEMPTS":    "rabbitmq.reconnect_attempts",
-		"RABBITMQ_ENABLE_HEARTBEAT":      "rabbitmq.enable_heartbeat",
-		"RABBITMQ_HEARTBEAT_INTERVAL":    "rabbitmq.heartbeat_interval",
-		"RABBITMQ_CHANNEL_POOL_SIZE":     "rabbitmq.channel_pool_size",
-		"RABBITMQ_AUTO_DECLARE_QUEUES":   "rabbitmq.auto_declare_queues",
-		"RABBITMQ_AUTO_DECLARE_EXCHANGE": "rabbitmq.auto_declare_exchange",
-	}
-
-	for envKey, configKey := range envMappings {
-		if value := os.Getenv(envKey); value != "" {
-			c.Set(configKey, c.parseEnvValue(value))
-		}
-	}
+	data, _ := EnvProvider{}.Load()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.mergeData(data)
 }
 
 // parseEnvValue parses environment variable value to appropriate type
-func (c *Config) parseEnvValue(value string) interface{} {
+func parseEnvValue(value string) interface{} {
 	// Try to parse as boolean
 	if strings.ToLower(value) == "true" {
 		return true
@@ -150,7 +142,8 @@ func (c *Config) GetString(key string, defaultValue ...string) string {
 	return fmt.Sprintf("%v", value)
 }
 
-// GetInt gets an integer configuration value
+// GetInt gets an integer configuration value. Whole floats, as JSON numbers
+// are decoded, and numeric strings are converted.
 func (c *Config) GetInt(key string, defaultValue ...int) int {
 	value := c.Get(key)
 	if value == nil {
@@ -170,6 +163,10 @@ func (c *Config) GetInt(key string, defaultValue ...int) int {
 		}
 	}
 
+	if number, ok := toFloat(value); ok && number == math.Trunc(number) {
+		return int(number)
+	}
+
 	if len(defaultValue) > 0 {
 		return defaultValue[0]
 	}
@@ -202,6 +199,132 @@ func (c *Config) GetBool(key string, defaultValue ...bool) bool {
 	return false
 }
 
+// GetFloat gets a float configuration value
+func (c *Config) GetFloat(key string, defaultValue ...float64) float64 {
+	value := c.Get(key)
+	if value == nil {
+		if len(defaultValue) > 0 {
+			return defaultValue[0]
+		}
+		return 0
+	}
+
+	if number, ok := toFloat(value); ok {
+		return number
+	}
+	if str, ok := value.(string); ok {
+		if floatVal, err := strconv.ParseFloat(str, 64); err == nil {
+			return floatVal
+		}
+	}
+
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+
+	return 0
+}
+
+// GetDuration gets a duration configuration value such as "5s" or "1m30s".
+// Numbers of any kind, including the float64 JSON decodes, are treated as
+// seconds.
+func (c *Config) GetDuration(key string, defaultValue ...time.Duration) time.Duration {
+	value := c.Get(key)
+	if value == nil {
+		if len(defaultValue) > 0 {
+			return defaultValue[0]
+		}
+		return 0
+	}
+
+	switch v := value.(type) {
+	case time.Duration:
+		return v
+	case string:
+		if duration, err := time.ParseDuration(v); err == nil {
+			return duration
+		}
+	default:
+		if seconds, ok := toFloat(v); ok {
+			return time.Duration(seconds * float64(time.Second))
+		}
+	}
+
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+
+	return 0
+}
+
+// toFloat converts a value of any numeric kind to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// GetStringSlice gets a list configuration value. Comma-separated strings
+// (e.g. from environment variables) are split and trimmed; lists loaded
+// from JSON files are converted element by element.
+func (c *Config) GetStringSlice(key string, defaultValue ...[]string) []string {
+	value := c.Get(key)
+	if value == nil {
+		if len(defaultValue) > 0 {
+			return defaultValue[0]
+		}
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			result = append(result, fmt.Sprintf("%v", item))
+		}
+		return result
+	case string:
+		result := []string{}
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+		return result
+	}
+
+	return []string{fmt.Sprintf("%v", value)}
+}
+
 // Set sets a configuration value
 func (c *Config) Set(key string, value interface{}) {
 	c.mutex.Lock()
@@ -232,8 +355,13 @@ func (c *Config) getNestedValue(key string) interface{} {
 
 // setNestedValue sets a nested configuration value
 func (c *Config) setNestedValue(key string, value interface{}) {
+	setNested(c.data, key, value)
+}
+
+// setNested sets a value in a nested map using a dotted key
+func setNested(data map[string]interface{}, key string, value interface{}) {
 	keys := strings.Split(key, ".")
-	current := c.data
+	current := data
 
 	for i, k := range keys {
 		if i == len(keys)-1 {
@@ -257,21 +385,60 @@ func (c *Config) setNestedValue(key string, value interface{}) {
 
 // LoadFromFile loads configuration from a JSON file
 func (c *Config) LoadFromFile(filename string) error {
-	file, err := os.Open(filename)
+	provider := &FileProvider{Filename: filename}
+	data, err := provider.Load()
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	var data map[string]interface{}
-	if err := json.NewDecoder(file).Decode(&data); err != nil {
-		return err
+	c.update(func() {
+		c.mergeData(data)
+		c.rememberFile(provider)
+	})
+	return nil
+}
+
+// rememberFile records a loaded file so Reload can load it again
+func (c *Config) rememberFile(provider *FileProvider) {
+	for _, existing := range c.providers {
+		if file, ok := existing.(*FileProvider); ok && file.Filename == provider.Filename {
+			return
+		}
 	}
+	c.providers = append(c.providers, provider)
+}
 
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+// Reload rebuilds the configuration from defaults, the files and providers
+// added with LoadFromFile and AddProvider (in the order they were added) and
+// environment variables, then invokes the OnReload callbacks. Values set
+// programmatically with Set are discarded. On error the current
+// configuration is kept.
+func (c *Config) Reload() error {
+	c.mutex.RLock()
+	providers := append([]ConfigProvider(nil), c.providers...)
+	c.mutex.RUnlock()
+
+	fresh := &Config{data: make(map[string]interface{})}
+	fresh.loadDefaults()
+	for _, provider := range providers {
+		data, err := provider.Load()
+		if err != nil {
+			return fmt.Errorf("failed to reload config: %w", err)
+		}
+		fresh.mergeData(data)
+	}
+	fresh.loadFromEnv()
 
-	c.mergeData(data)
+	c.update(func() {
+		c.data = fresh.data
+	})
+
+	c.mutex.RLock()
+	callbacks := append([]func(){}, c.onReload...)
+	c.mutex.RUnlock()
+	for _, callback := range callbacks {
+		callback()
+	}
 	return nil
 }
 
@@ -305,6 +472,30 @@ func (c *Config) mergeMap(existing, new map[string]interface{}) {
 	}
 }
 
+// Unmarshal decodes the configuration subtree at key (or everything when key
+// is empty) into out using its json tags. Fields missing from the
+// configuration keep their current values.
+func (c *Config) Unmarshal(key string, out interface{}) error {
+	var value interface{}
+	if key == "" {
+		value = c.All()
+	} else {
+		value = c.Get(key)
+	}
+	if value == nil {
+		return fmt.Errorf("config key '%s' not found", key)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode config '%s': %w", key, err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode config '%s': %w", key, err)
+	}
+	return nil
+}
+
 // All returns all configuration data
 func (c *Config) All() map[string]interface{} {
 	c.mutex.RLock()
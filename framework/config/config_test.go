@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNumericGettersAcceptJSONNumbers(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	content := `{"cache": {"ttl": 90, "grace": 1.5, "size": 512, "ratio": 0.25, "half": 2.5}}`
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewConfig()
+	if err := c.LoadFromFile(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.GetDuration("cache.ttl"); got != 90*time.Second {
+		t.Errorf("GetDuration(cache.ttl) = %v, want 1m30s", got)
+	}
+	if got := c.GetDuration("cache.grace"); got != 1500*time.Millisecond {
+		t.Errorf("GetDuration(cache.grace) = %v, want 1.5s", got)
+	}
+	if got := c.GetInt("cache.size"); got != 512 {
+		t.Errorf("GetInt(cache.size) = %d, want 512", got)
+	}
+	if got := c.GetInt("cache.half", 7); got != 7 {
+		t.Errorf("GetInt(cache.half) = %d, want the default for a fractional value", got)
+	}
+	if got := c.GetFloat("cache.ratio"); got != 0.25 {
+		t.Errorf("GetFloat(cache.ratio) = %v, want 0.25", got)
+	}
+}
+
+func TestNumericGettersAcceptOtherKinds(t *testing.T) {
+	c := NewConfig()
+	values := map[string]interface{}{
+		"a": int64(3),
+		"b": uint32(4),
+		"c": json.Number("5"),
+		"d": float32(6),
+		"e": "7",
+		"f": time.Minute,
+		"g": "2m",
+	}
+	for key, value := range values {
+		c.Set("n."+key, value)
+	}
+
+	for key, want := range map[string]int{"a": 3, "b": 4, "c": 5, "d": 6, "e": 7} {
+		if got := c.GetInt("n." + key); got != want {
+			t.Errorf("GetInt(n.%s) = %d, want %d", key, got, want)
+		}
+	}
+	for key, want := range map[string]time.Duration{"a": 3 * time.Second, "c": 5 * time.Second, "f": time.Minute, "g": 2 * time.Minute} {
+		if got := c.GetDuration("n." + key); got != want {
+			t.Errorf("GetDuration(n.%s) = %v, want %v", key, got, want)
+		}
+	}
+	if got := c.GetDuration("n.missing", time.Second); got != time.Second {
+		t.Errorf("GetDuration default = %v", got)
+	}
+}
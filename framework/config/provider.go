@@ -0,0 +1,136 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+)
+
+// ConfigProvider supplies configuration from a source such as a file,
+// the environment or a remote store like Consul or etcd
+type ConfigProvider interface {
+	// Load returns the provider's configuration as a nested map
+	Load() (map[string]interface{}, error)
+
+	// Watch arranges for onChange to be called whenever the source changes.
+	// It must not block; providers that cannot detect changes return nil.
+	Watch(onChange func()) error
+}
+
+// envMappings maps environment variables to configuration keys
+var envMappings = map[string]string{
+	// App configuration
+	"APP_NAME":          "app.name",
+	"APP_ENV":           "app.env",
+	"APP_DEBUG":         "app.debug",
+	"APP_PORT":          "app.port",
+	"APP_KEY":           "app.key",
+	"APP_PREVIOUS_KEYS": "app.previous_keys",
+
+	// HTTP server configuration
+	"SERVER_READ_TIMEOUT":        "server.read_timeout",
+	"SERVER_READ_HEADER_TIMEOUT": "server.read_header_timeout",
+	"SERVER_WRITE_TIMEOUT":       "server.write_timeout",
+	"SERVER_IDLE_TIMEOUT":        "server.idle_timeout",
+
+	// HTTP middleware configuration
+	"HTTP_LOGGING_ENABLED":  "http.logging.enabled",
+	"HTTP_LOGGING_FORMAT":   "http.logging.format",
+	"HTTP_RECOVERY_ENABLED": "http.recovery.enabled",
+	"HTTP_GZIP_ENABLED":     "http.gzip.enabled",
+	"HTTP_GZIP_LEVEL":       "http.gzip.level",
+	"HTTP_CORS_ENABLED":     "http.cors.enabled",
+	"HTTP_CORS_ORIGINS":     "http.cors.origins",
+	"HTTP_CORS_CREDENTIALS": "http.cors.credentials",
+	"HTTP_CORS_MAX_AGE":     "http.cors.max_age",
+
+	// Auth configuration
+	"AUTH_JWT_TTL":    "auth.jwt_ttl",
+	"AUTH_JWT_LEEWAY": "auth.jwt_leeway",
+
+	// Database configuration
+	"DB_CONNECTION":    "database.default",
+	"MONGODB_URI":      "database.connections.mongodb.uri",
+	"DB_DATABASE":      "database.connections.mongodb.database",
+	"MONGODB_DATABASE": "database.connections.mongodb.database",
+
+	// RabbitMQ configuration
+	"RABBITMQ_ENABLED":               "rabbitmq.enabled",
+	"RABBITMQ_NAME":                  "rabbitmq.name",
+	"RABBITMQ_URL":                   "rabbitmq.url",
+	"RABBITMQ_RECONNECT_DELAY":       "rabbitmq.reconnect_delay",
+	"RABBITMQ_RECONNECT_ATTEMPTS":    "rabbitmq.reconnect_attempts",
+	"RABBITMQ_ENABLE_HEARTBEAT":      "rabbitmq.enable_heartbeat",
+	"RABBITMQ_HEARTBEAT_INTERVAL":    "rabbitmq.heartbeat_interval",
+	"RABBITMQ_CHANNEL_POOL_SIZE":     "rabbitmq.channel_pool_size",
+	"RABBITMQ_AUTO_DECLARE_QUEUES":   "rabbitmq.auto_declare_queues",
+	"RABBITMQ_AUTO_DECLARE_EXCHANGE": "rabbitmq.auto_declare_exchange",
+}
+
+// EnvProvider reads the framework's known environment variables
+type EnvProvider struct{}
+
+// Load returns the values of the environment variables that are set
+func (EnvProvider) Load() (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	for envKey, configKey := range envMappings {
+		if value := os.Getenv(envKey); value != "" {
+			setNested(data, configKey, parseEnvValue(value))
+		}
+	}
+	return data, nil
+}
+
+// Watch is a no-op; the process environment does not change at runtime
+func (EnvProvider) Watch(onChange func()) error {
+	return nil
+}
+
+// FileProvider reads configuration from a JSON file
+type FileProvider struct {
+	Filename string
+}
+
+// Load reads and decodes the file
+func (p *FileProvider) Load() (map[string]interface{}, error) {
+	file, err := os.Open(p.Filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var data map[string]interface{}
+	if err := json.NewDecoder(file).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", p.Filename, err)
+	}
+	return data, nil
+}
+
+// Watch is a no-op; use Config.WatchFile to reload a file when it changes
+func (p *FileProvider) Watch(onChange func()) error {
+	return nil
+}
+
+// AddProvider loads a provider and merges its data over defaults and files,
+// while environment variables keep the highest precedence. When the provider
+// reports a change the whole configuration is reloaded.
+func (c *Config) AddProvider(provider ConfigProvider) error {
+	data, err := provider.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config provider: %w", err)
+	}
+	env, _ := EnvProvider{}.Load()
+
+	c.update(func() {
+		c.mergeData(data)
+		c.mergeData(env)
+		c.providers = append(c.providers, provider)
+	})
+
+	return provider.Watch(func() {
+		if err := c.Reload(); err != nil {
+			log.Printf("Config reload error: %v", err)
+		}
+	})
+}
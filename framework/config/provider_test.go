@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+type failingProvider struct{}
+
+func (failingProvider) Load() (map[string]interface{}, error) { return nil, errors.New("unreachable") }
+func (failingProvider) Watch(func()) error                    { return nil }
+
+func TestProvidersMergeInOrder(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	writeJSON(t, filename, `{"cache": {"driver": "file", "ttl": "1m"}, "mail": {"host": "localhost"}}`)
+
+	c := NewConfig()
+	if err := c.AddProvider(&FileProvider{Filename: filename}); err != nil {
+		t.Fatal(err)
+	}
+	remote := &staticProvider{data: map[string]interface{}{
+		"cache": map[string]interface{}{"driver": "redis"},
+	}}
+	if err := c.AddProvider(remote); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.GetString("cache.driver"); got != "redis" {
+		t.Errorf("cache.driver = %q, want the later provider's value", got)
+	}
+	if got := c.GetString("cache.ttl"); got != "1m" {
+		t.Errorf("cache.ttl = %q, want the file's value kept", got)
+	}
+	if got := c.GetString("mail.host"); got != "localhost" {
+		t.Errorf("mail.host = %q", got)
+	}
+}
+
+func TestAddProviderLoadError(t *testing.T) {
+	c := NewConfig()
+	if err := c.AddProvider(failingProvider{}); err == nil {
+		t.Fatal("AddProvider ignored a load error")
+	}
+	if err := c.AddProvider(&FileProvider{Filename: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Error("AddProvider ignored a missing file")
+	}
+}
+
+func TestEnvProviderLoadsKnownVariables(t *testing.T) {
+	t.Setenv("APP_ENV", "staging")
+
+	data, err := EnvProvider{}.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewConfig()
+	c.mergeData(data)
+	if got := c.GetString("app.env"); got != "staging" {
+		t.Errorf("app.env = %q, want staging", got)
+	}
+}
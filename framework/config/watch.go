@@ -0,0 +1,164 @@
+package config
+
+import (
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// WatchInterval is how often WatchFile checks watched files for changes
+var WatchInterval = 2 * time.Second
+
+// OnChange registers a callback invoked with the dotted key of every value
+// that changes when a file is reloaded, a provider reports a change or
+// Reload is called. Values changed with Set are not reported.
+func (c *Config) OnChange(callback func(key string)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.onChange = append(c.onChange, callback)
+}
+
+// OnReload registers a callback invoked after every full reload: a Reload
+// call, a watched file changing or a provider reporting a change
+func (c *Config) OnReload(callback func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.onReload = append(c.onReload, callback)
+}
+
+// WatchFile loads a JSON config file and reloads the configuration (see
+// Reload) whenever the file's modification time or size changes, so
+// environment variables keep precedence and keys removed from the file go
+// away. Files are polled every WatchInterval; StopWatching stops all
+// watchers.
+func (c *Config) WatchFile(filename string) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	provider := &FileProvider{Filename: filename}
+	data, err := provider.Load()
+	if err != nil {
+		return err
+	}
+	env, _ := EnvProvider{}.Load()
+
+	c.update(func() {
+		c.mergeData(data)
+		c.mergeData(env)
+		c.rememberFile(provider)
+	})
+
+	c.mutex.Lock()
+	if c.watchStop == nil {
+		c.watchStop = make(chan struct{})
+	}
+	stop := c.watchStop
+	c.mutex.Unlock()
+
+	go c.watchFile(filename, info, stop)
+	return nil
+}
+
+// StopWatching stops all file watchers started with WatchFile
+func (c *Config) StopWatching() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.watchStop != nil {
+		close(c.watchStop)
+		c.watchStop = nil
+	}
+}
+
+// watchFile polls a file and reloads the configuration when it changes
+func (c *Config) watchFile(filename string, last os.FileInfo, stop chan struct{}) {
+	ticker := time.NewTicker(WatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(filename)
+		if err != nil {
+			// The file may be mid-replacement; try again on the next tick
+			continue
+		}
+		if info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+			continue
+		}
+		last = info
+
+		if err := c.Reload(); err != nil {
+			log.Printf("Config reload error: %v", err)
+		}
+	}
+}
+
+// update applies a change to the configuration under the write lock and
+// then notifies OnChange callbacks of every key whose value changed
+func (c *Config) update(change func()) {
+	c.mutex.Lock()
+	if len(c.onChange) == 0 {
+		change()
+		c.mutex.Unlock()
+		return
+	}
+
+	before := flatten(c.data)
+	change()
+	after := flatten(c.data)
+	callbacks := append([]func(string){}, c.onChange...)
+	c.mutex.Unlock()
+
+	for _, key := range changedKeys(before, after) {
+		for _, callback := range callbacks {
+			callback(key)
+		}
+	}
+}
+
+// flatten returns the leaf values of a nested map keyed by dotted path
+func flatten(data map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	flattenInto(result, "", data)
+	return result
+}
+
+func flattenInto(result map[string]interface{}, prefix string, data map[string]interface{}) {
+	for key, value := range data {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenInto(result, key, nested)
+			continue
+		}
+		result[key] = value
+	}
+}
+
+// changedKeys returns the sorted keys that were added, removed or modified
+func changedKeys(before, after map[string]interface{}) []string {
+	var keys []string
+	for key, value := range after {
+		if old, exists := before[key]; !exists || !reflect.DeepEqual(old, value) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range before {
+		if _, exists := after[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
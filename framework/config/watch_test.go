@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeJSON(t *testing.T, filename, content string) {
+	t.Helper()
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchFileReloadsWithEnvPrecedence(t *testing.T) {
+	defer func(interval time.Duration) { WatchInterval = interval }(WatchInterval)
+	WatchInterval = 10 * time.Millisecond
+
+	t.Setenv("APP_NAME", "from-env")
+	filename := filepath.Join(t.TempDir(), "config.json")
+	writeJSON(t, filename, `{"app": {"name": "from-file", "env": "staging"}, "feature": {"beta": true}}`)
+
+	c := NewConfig()
+	reloaded := make(chan struct{}, 1)
+	c.OnReload(func() {
+		select {
+		case reloaded <- struct{}{}:
+		default:
+		}
+	})
+	var changed []string
+	c.OnChange(func(key string) { changed = append(changed, key) })
+
+	if err := c.WatchFile(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer c.StopWatching()
+
+	if got := c.GetString("app.name"); got != "from-env" {
+		t.Errorf("app.name = %q, want the environment value", got)
+	}
+	if got := c.GetString("app.env"); got != "staging" {
+		t.Errorf("app.env = %q, want staging", got)
+	}
+
+	changed = nil
+	writeJSON(t, filename, `{"app": {"name": "edited", "env": "production"}}`)
+
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Fatal("watched file change did not trigger a reload")
+	}
+
+	if got := c.GetString("app.name"); got != "from-env" {
+		t.Errorf("app.name = %q after reload, want the environment value", got)
+	}
+	if got := c.GetString("app.env"); got != "production" {
+		t.Errorf("app.env = %q after reload, want production", got)
+	}
+	if got := c.Get("feature.beta"); got != nil {
+		t.Errorf("feature.beta = %v after it was removed from the file", got)
+	}
+	if len(changed) != 2 || changed[0] != "app.env" || changed[1] != "feature.beta" {
+		t.Errorf("OnChange keys = %v, want [app.env feature.beta]", changed)
+	}
+}
+
+type staticProvider struct {
+	data     map[string]interface{}
+	onChange func()
+}
+
+func (p *staticProvider) Load() (map[string]interface{}, error) {
+	return p.data, nil
+}
+
+func (p *staticProvider) Watch(onChange func()) error {
+	p.onChange = onChange
+	return nil
+}
+
+func TestProviderChangeRunsOnReload(t *testing.T) {
+	t.Setenv("APP_ENV", "from-env")
+
+	c := NewConfig()
+	provider := &staticProvider{data: map[string]interface{}{
+		"app":   map[string]interface{}{"env": "from-provider"},
+		"cache": map[string]interface{}{"ttl": "1m"},
+	}}
+	if err := c.AddProvider(provider); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("app.env"); got != "from-env" {
+		t.Errorf("app.env = %q, want the environment value", got)
+	}
+
+	var reloads int
+	c.OnReload(func() { reloads++ })
+
+	provider.data = map[string]interface{}{"cache": map[string]interface{}{"ttl": "5m"}}
+	provider.onChange()
+
+	if reloads != 1 {
+		t.Errorf("OnReload ran %d times, want 1", reloads)
+	}
+	if got := c.GetDuration("cache.ttl"); got != 5*time.Minute {
+		t.Errorf("cache.ttl = %v, want 5m", got)
+	}
+}
@@ -1,28 +1,51 @@
 package container
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 )
 
+// ErrServiceNotFound is returned when resolving a name that has no binding
+var ErrServiceNotFound = errors.New("service not found in container")
+
 // Container provides dependency injection capabilities
 type Container struct {
-	bindings   map[string]binding
-	singletons map[string]interface{}
-	mutex      sync.RWMutex
+	parent   *Container
+	bindings map[string]*binding
+	types    map[reflect.Type]string
+	mutex    sync.RWMutex
+
+	// cache holds this scope's copies of singletons inherited from parents
+	cache map[string]*binding
+	// constructed lists the singletons built here, for Dispose
+	constructed []interface{}
 }
 
 // binding represents a service binding
 type binding struct {
 	resolver  func() interface{}
 	singleton bool
+
+	// global singletons are shared with scopes instead of built per scope
+	global bool
+	// source is the parent binding a scope's cached copy was made from
+	source *binding
+
+	// mu serializes singleton construction; done is set once the resolver
+	// has returned a non-nil instance, so failures are retried
+	mu       sync.Mutex
+	done     bool
+	instance interface{}
 }
 
 // NewContainer creates a new container instance
 func NewContainer() *Container {
 	return &Container{
-		bindings:   make(map[string]binding),
-		singletons: make(map[string]interface{}),
+		bindings: make(map[string]*binding),
+		types:    make(map[reflect.Type]string),
 	}
 }
 
@@ -31,18 +54,19 @@ func (c *Container) Bind(name string, resolver func() interface{}) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.bindings[name] = binding{
+	c.bindings[name] = &binding{
 		resolver:  resolver,
 		singleton: false,
 	}
 }
 
-// Singleton registers a singleton service resolver
+// Singleton registers a singleton service resolver. Scopes build and cache
+// their own instance (see Scope); use Global for an instance shared by all.
 func (c *Container) Singleton(name string, resolver func() interface{}) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.bindings[name] = binding{
+	c.bindings[name] = &binding{
 		resolver:  resolver,
 		singleton: true,
 	}
@@ -50,73 +74,135 @@ func (c *Container) Singleton(name string, resolver func() interface{}) {
 
 // Resolve resolves a service from the container
 func (c *Container) Resolve(name string) interface{} {
-	c.mutex.RLock()
-
-	// Check if singleton instance exists
-	if instance, exists := c.singletons[name]; exists {
-		c.mutex.RUnlock()
-		return instance
+	instance, err := c.resolve(name)
+	if err != nil {
+		panic(fmt.Sprintf("Service '%s' not found in container", name))
 	}
+	return instance
+}
 
-	// Check if binding exists
-	binding, exists := c.bindings[name]
-	if !exists {
-		c.mutex.RUnlock()
-		panic(fmt.Sprintf("Service '%s' not found in container", name))
+// TryResolve resolves a service without panicking. It reports false when no
+// service is registered under name or its resolver returned nil, e.g. an
+// auto-registered database that could not connect.
+func (c *Container) TryResolve(name string) (interface{}, bool) {
+	instance, err := c.resolve(name)
+	if err != nil || instance == nil {
+		return nil, false
 	}
+	return instance, true
+}
 
-	c.mutex.RUnlock()
+// resolve resolves a service, returning ErrServiceNotFound for unknown names
+func (c *Container) resolve(name string) (interface{}, error) {
+	binding, err := c.lookup(name)
+	if err != nil {
+		return nil, err
+	}
 
-	// Resolve the service
-	instance := binding.resolver()
+	if !binding.singleton {
+		return binding.resolver(), nil
+	}
 
-	// Store singleton instance
-	if binding.singleton {
-		c.mutex.Lock()
-		c.singletons[name] = instance
-		c.mutex.Unlock()
+	// Concurrent callers wait for the first construction instead of
+	// building their own instance. A resolver that panics or returns nil
+	// (e.g. a failed Provide constructor) is not cached and runs again on
+	// the next resolve.
+	binding.mu.Lock()
+	defer binding.mu.Unlock()
+	if !binding.done {
+		instance := binding.resolver()
+		if instance == nil {
+			return nil, nil
+		}
+		binding.instance = instance
+		binding.done = true
+		if !binding.global {
+			c.mutex.Lock()
+			c.constructed = append(c.constructed, instance)
+			c.mutex.Unlock()
+		}
 	}
+	return binding.instance, nil
+}
 
-	return instance
+// reset forgets a singleton's instance
+func (b *binding) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.instance = nil
+	b.done = false
 }
 
-// Has checks if a service is registered
+// Has checks if a service is registered here or in a parent container
 func (c *Container) Has(name string) bool {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	for owner := c; owner != nil; owner = owner.parent {
+		owner.mutex.RLock()
+		_, exists := owner.bindings[name]
+		owner.mutex.RUnlock()
+		if exists {
+			return true
+		}
+	}
+	return false
+}
 
-	_, exists := c.bindings[name]
-	return exists
+// Names returns the names of all registered services, including those of
+// parent containers, sorted alphabetically
+func (c *Container) Names() []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for owner := c; owner != nil; owner = owner.parent {
+		owner.mutex.RLock()
+		for name := range owner.bindings {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		owner.mutex.RUnlock()
+	}
+	sort.Strings(names)
+	return names
 }
 
-// Remove removes a service binding
+// Remove removes a service binding. Bindings of a parent container are
+// not affected.
 func (c *Container) Remove(name string) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
 	delete(c.bindings, name)
-	delete(c.singletons, name)
+	for t, typeName := range c.types {
+		if typeName == name {
+			delete(c.types, t)
+		}
+	}
 }
 
-// Clear removes all bindings
+// Clear removes all bindings registered on this container
 func (c *Container) Clear() {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.bindings = make(map[string]binding)
-	c.singletons = make(map[string]interface{})
+	c.bindings = make(map[string]*binding)
+	c.types = make(map[reflect.Type]string)
 }
 
-// Instance registers an existing instance as a singleton
+// Instance registers an existing instance as a singleton shared with every
+// scope. It can also be injected by its type (see Call).
 func (c *Container) Instance(name string, instance interface{}) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.singletons[name] = instance
-	c.bindings[name] = binding{
+	if instance != nil {
+		c.types[reflect.TypeOf(instance)] = name
+	}
+
+	c.bindings[name] = &binding{
 		resolver: func() interface{} {
 			return instance
 		},
 		singleton: true,
+		global:    true,
 	}
 }
@@ -0,0 +1,100 @@
+package container
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSingletonConstructedOnce(t *testing.T) {
+	c := NewContainer()
+	var calls atomic.Int32
+	c.Singleton("db", func() interface{} {
+		calls.Add(1)
+		return &struct{ n int }{}
+	})
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = c.Resolve("db")
+		}(i)
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("resolver ran %d times, want 1", calls.Load())
+	}
+	for _, result := range results[1:] {
+		if result != results[0] {
+			t.Fatal("goroutines resolved different instances")
+		}
+	}
+}
+
+func TestSingletonPanicIsNotCached(t *testing.T) {
+	c := NewContainer()
+	fail := true
+	c.Singleton("mailer", func() interface{} {
+		if fail {
+			panic("smtp unreachable")
+		}
+		return "mailer"
+	})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected the resolver panic to propagate")
+			}
+		}()
+		c.Resolve("mailer")
+	}()
+
+	fail = false
+	if got := c.Resolve("mailer"); got != "mailer" {
+		t.Errorf("Resolve after a panic = %v, want mailer", got)
+	}
+}
+
+type mailer struct{}
+
+func TestProvideErrorIsNotCached(t *testing.T) {
+	c := NewContainer()
+	var attempts int
+	err := c.Provide(func() (*mailer, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("not ready")
+		}
+		return &mailer{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.TryResolve("*container.mailer"); ok {
+		t.Fatal("failed construction resolved")
+	}
+	m, err := Resolve[*mailer](c, "*container.mailer")
+	if err != nil || m == nil {
+		t.Fatalf("second resolve = %v, %v", m, err)
+	}
+	MustResolve[*mailer](c, "*container.mailer")
+	if attempts != 2 {
+		t.Errorf("constructor ran %d times, want 2", attempts)
+	}
+}
+
+func TestBindBuildsEveryTime(t *testing.T) {
+	c := NewContainer()
+	c.Bind("request", func() interface{} { return new(int) })
+
+	if c.Resolve("request") == c.Resolve("request") {
+		t.Error("Bind returned the same instance twice")
+	}
+}
@@ -0,0 +1,157 @@
+package container
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+)
+
+// errorType is the reflect.Type of the error interface
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// BindType registers name as the service injected for parameters of type t
+func (c *Container) BindType(t reflect.Type, name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.types[t] = name
+}
+
+// Provide registers a constructor as a singleton keyed by its return type.
+// The constructor's parameters are injected by type when the service is
+// first resolved, and it may return an error as its second result:
+//
+//	c.Provide(func(cfg *config.Config) (*Mailer, error) {
+//	    return NewMailer(cfg.GetString("mail.host"))
+//	})
+//
+// The service is registered under the type's name, e.g. "*mail.Mailer".
+// A constructor that fails is logged and resolves to nil; it runs again on
+// the next resolve.
+func (c *Container) Provide(constructor interface{}) error {
+	ft := reflect.TypeOf(constructor)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return fmt.Errorf("Provide requires a function, got %T", constructor)
+	}
+	if ft.NumOut() == 0 || ft.NumOut() > 2 || (ft.NumOut() == 2 && ft.Out(1) != errorType) {
+		return fmt.Errorf("Provide requires a function returning T or (T, error), got %s", ft)
+	}
+
+	t := ft.Out(0)
+	name := t.String()
+	c.Singleton(name, func() interface{} {
+		results, err := c.Call(constructor)
+		if err == nil && len(results) == 2 && results[1] != nil {
+			err = results[1].(error)
+		}
+		if err != nil {
+			log.Printf("Failed to construct %s: %v", name, err)
+			return nil
+		}
+		return results[0]
+	})
+	c.BindType(t, name)
+	return nil
+}
+
+// Call invokes fn with each parameter resolved from the container by type
+// and returns fn's results:
+//
+//	c.Call(func(db *database.DB, cfg *config.Config) {
+//	    // ...
+//	})
+//
+// Types are known through Provide, BindType, Alias and Instance. Interface
+// parameters match the single registered type implementing them. An error is
+// returned, and fn is not called, when a parameter cannot be resolved.
+func (c *Container) Call(fn interface{}) ([]interface{}, error) {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func {
+		return nil, fmt.Errorf("Call requires a function, got %T", fn)
+	}
+	ft := fv.Type()
+	if ft.IsVariadic() {
+		return nil, fmt.Errorf("Call does not support variadic functions")
+	}
+
+	args := make([]reflect.Value, ft.NumIn())
+	for i := range args {
+		instance, err := c.ResolveType(ft.In(i))
+		if err != nil {
+			return nil, err
+		}
+		args[i] = reflect.ValueOf(instance)
+	}
+
+	out := fv.Call(args)
+	results := make([]interface{}, len(out))
+	for i, value := range out {
+		results[i] = value.Interface()
+	}
+	return results, nil
+}
+
+// ResolveType resolves the service registered for type t
+func (c *Container) ResolveType(t reflect.Type) (interface{}, error) {
+	name, err := c.nameForType(t)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := c.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("service '%s' for type %s is unavailable", name, t)
+	}
+	if !reflect.TypeOf(instance).AssignableTo(t) {
+		return nil, fmt.Errorf("service '%s' is %T, not %s", name, instance, t)
+	}
+	return instance, nil
+}
+
+// nameForType finds the service name registered for a type. Interfaces
+// match a single registered implementation.
+func (c *Container) nameForType(t reflect.Type) (string, error) {
+	types := c.injectableTypes()
+	if name, ok := types[t]; ok {
+		return name, nil
+	}
+
+	if t.Kind() == reflect.Interface {
+		var matches []string
+		for registered, name := range types {
+			if registered.Implements(t) {
+				matches = append(matches, name)
+			}
+		}
+		sort.Strings(matches)
+		switch len(matches) {
+		case 1:
+			return matches[0], nil
+		case 0:
+		default:
+			return "", fmt.Errorf("type %s is implemented by several services: %v", t, matches)
+		}
+	}
+
+	return "", fmt.Errorf("%w: no service registered for type %s", ErrServiceNotFound, t)
+}
+
+// injectableTypes returns the types registered on this container and its
+// parents; a type registered closer to this container takes precedence
+func (c *Container) injectableTypes() map[reflect.Type]string {
+	types := make(map[reflect.Type]string)
+	for owner := c; owner != nil; owner = owner.parent {
+		owner.mutex.RLock()
+		for t, name := range owner.types {
+			if _, exists := types[t]; !exists {
+				types[t] = name
+			}
+		}
+		owner.mutex.RUnlock()
+	}
+	return types
+}
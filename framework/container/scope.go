@@ -0,0 +1,113 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Global registers a singleton that is constructed once and shared with
+// every scope, for services that hold process-wide resources such as
+// database connections
+func (c *Container) Global(name string, resolver func() interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.bindings[name] = &binding{
+		resolver:  resolver,
+		singleton: true,
+		global:    true,
+	}
+}
+
+// Scope returns a child container that resolves everything registered on c
+// but keeps its own singleton cache: a Singleton of c resolved through the
+// scope is constructed once per scope. Instances and Global services are
+// shared with c. Services registered on the child itself are not visible
+// to c:
+//
+//	scope := app.Container.Scope()
+//	defer scope.Dispose()
+//	scope.Instance("request_id", requestID)
+//	logger := scope.Resolve("logger") // built for this scope
+func (c *Container) Scope() *Container {
+	scope := NewContainer()
+	scope.parent = c
+	return scope
+}
+
+// Dispose closes the singletons this container constructed that implement
+// io.Closer, newest first, and forgets them so they are built again on the
+// next resolve. Instances and Global services are never closed. Call it when
+// a scope ends.
+func (c *Container) Dispose() error {
+	c.mutex.Lock()
+	constructed := c.constructed
+	c.constructed = nil
+	c.cache = nil
+	var own []*binding
+	for _, b := range c.bindings {
+		if b.singleton && !b.global {
+			own = append(own, b)
+		}
+	}
+	c.mutex.Unlock()
+
+	// Reset outside c.mutex: resolve takes a binding's lock before c.mutex
+	for _, b := range own {
+		b.reset()
+	}
+
+	var errs []error
+	for i := len(constructed) - 1; i >= 0; i-- {
+		if closer, ok := constructed[i].(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lookup finds the binding for name in this container or its parents. An
+// inherited singleton that is not global is replaced by this container's
+// own copy, so it is constructed once per scope.
+func (c *Container) lookup(name string) (*binding, error) {
+	for owner := c; owner != nil; owner = owner.parent {
+		owner.mutex.RLock()
+		b, exists := owner.bindings[name]
+		owner.mutex.RUnlock()
+
+		if !exists {
+			continue
+		}
+		if b.singleton && !b.global && owner != c {
+			return c.cachedBinding(name, b), nil
+		}
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("%w: '%s'", ErrServiceNotFound, name)
+}
+
+// cachedBinding returns this container's copy of an inherited singleton
+func (c *Container) cachedBinding(name string, inherited *binding) *binding {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// A copy made from a binding since replaced on the parent is rebuilt
+	if b, exists := c.cache[name]; exists && b.source == inherited {
+		return b
+	}
+
+	b := &binding{
+		resolver:  inherited.resolver,
+		singleton: true,
+		source:    inherited,
+	}
+	if c.cache == nil {
+		c.cache = make(map[string]*binding)
+	}
+	c.cache[name] = b
+	return b
+}
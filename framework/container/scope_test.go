@@ -0,0 +1,122 @@
+package container
+
+import (
+	"errors"
+	"testing"
+)
+
+type closable struct {
+	name   string
+	closed *[]string
+}
+
+func (c *closable) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return nil
+}
+
+func TestScopeHasOwnSingletonCache(t *testing.T) {
+	root := NewContainer()
+	var built int
+	root.Singleton("cart", func() interface{} {
+		built++
+		return &struct{ id int }{built}
+	})
+
+	first, second := root.Scope(), root.Scope()
+	if first.Resolve("cart") != first.Resolve("cart") {
+		t.Error("a scope resolved its singleton twice")
+	}
+	if first.Resolve("cart") == second.Resolve("cart") {
+		t.Error("two scopes share a singleton")
+	}
+	if root.Resolve("cart") == first.Resolve("cart") {
+		t.Error("a scope shares the parent's singleton")
+	}
+	if built != 3 {
+		t.Errorf("resolver ran %d times, want 3", built)
+	}
+}
+
+func TestScopeSharesGlobalsAndInstances(t *testing.T) {
+	root := NewContainer()
+	root.Global("db", func() interface{} { return new(int) })
+	root.Instance("config", new(string))
+
+	scope := root.Scope()
+	if scope.Resolve("db") != root.Resolve("db") {
+		t.Error("Global service built per scope")
+	}
+	if scope.Resolve("config") != root.Resolve("config") {
+		t.Error("Instance not shared with scope")
+	}
+}
+
+func TestScopeBindingsStayInScope(t *testing.T) {
+	root := NewContainer()
+	scope := root.Scope()
+	scope.Instance("request_id", "abc")
+
+	if root.Has("request_id") {
+		t.Error("scope binding visible on parent")
+	}
+	if got := scope.Resolve("request_id"); got != "abc" {
+		t.Errorf("request_id = %v", got)
+	}
+}
+
+func TestScopeSeesReplacedParentBinding(t *testing.T) {
+	root := NewContainer()
+	root.Singleton("mailer", func() interface{} { return "smtp" })
+	scope := root.Scope()
+	scope.Resolve("mailer")
+
+	root.Singleton("mailer", func() interface{} { return "log" })
+	if got := scope.Resolve("mailer"); got != "log" {
+		t.Errorf("mailer = %v after re-registering, want log", got)
+	}
+}
+
+func TestDisposeClosesScopeSingletons(t *testing.T) {
+	var closed []string
+	root := NewContainer()
+	root.Global("db", func() interface{} { return &closable{"db", &closed} })
+	root.Singleton("tx", func() interface{} { return &closable{"tx", &closed} })
+	root.Singleton("cart", func() interface{} { return &closable{"cart", &closed} })
+
+	scope := root.Scope()
+	scope.Instance("conn", &closable{"conn", &closed})
+	scope.Resolve("db")
+	scope.Resolve("tx")
+	scope.Resolve("cart")
+	scope.Resolve("conn")
+
+	if err := scope.Dispose(); err != nil {
+		t.Fatal(err)
+	}
+	if len(closed) != 2 || closed[0] != "cart" || closed[1] != "tx" {
+		t.Errorf("closed = %v, want [cart tx]", closed)
+	}
+
+	closed = nil
+	scope.Resolve("tx")
+	scope.Dispose()
+	if len(closed) != 1 || closed[0] != "tx" {
+		t.Errorf("after re-resolving, closed = %v, want [tx]", closed)
+	}
+}
+
+type failingCloser struct{}
+
+func (failingCloser) Close() error { return errors.New("close failed") }
+
+func TestDisposeReportsCloseErrors(t *testing.T) {
+	root := NewContainer()
+	root.Singleton("broken", func() interface{} { return failingCloser{} })
+
+	scope := root.Scope()
+	scope.Resolve("broken")
+	if err := scope.Dispose(); err == nil {
+		t.Error("Dispose returned nil for a failing Close")
+	}
+}
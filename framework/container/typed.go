@@ -0,0 +1,41 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Resolve resolves a service and asserts it to T, returning the zero value
+// and an error when the service is missing or has a different type:
+//
+//	db, err := container.Resolve[*database.DB](app.Container, "db")
+func Resolve[T any](c *Container, name string) (T, error) {
+	var zero T
+
+	instance, err := c.resolve(name)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("service '%s' is %T, not %s", name, instance, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return typed, nil
+}
+
+// MustResolve is like Resolve but panics if the service cannot be resolved
+func MustResolve[T any](c *Container, name string) T {
+	typed, err := Resolve[T](c, name)
+	if err != nil {
+		panic(err)
+	}
+	return typed
+}
+
+// Alias makes the service registered under name injectable as type T:
+//
+//	container.Alias[*database.DB](app.Container, "db")
+func Alias[T any](c *Container, name string) {
+	c.BindType(reflect.TypeOf((*T)(nil)).Elem(), name)
+}
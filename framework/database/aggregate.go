@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AggregateBuilder provides a fluent interface for building aggregation pipelines
+type AggregateBuilder struct {
+	db         *DB
+	collection string
+	pipeline   []bson.M
+	ctx        context.Context
+	timeout    time.Duration
+}
+
+// NewAggregation creates a new aggregation pipeline builder
+func (db *DB) NewAggregation() *AggregateBuilder {
+	return &AggregateBuilder{
+		db:       db,
+		pipeline: []bson.M{},
+		ctx:      context.Background(),
+		timeout:  db.DefaultTimeout(),
+	}
+}
+
+// Collection sets the collection name
+func (ab *AggregateBuilder) Collection(collection string) *AggregateBuilder {
+	ab.collection = collection
+	return ab
+}
+
+// Context sets the context for the aggregation
+func (ab *AggregateBuilder) Context(ctx context.Context) *AggregateBuilder {
+	ab.ctx = ctx
+	return ab
+}
+
+// Timeout overrides the DB default operation timeout for this aggregation (0 disables it)
+func (ab *AggregateBuilder) Timeout(timeout time.Duration) *AggregateBuilder {
+	ab.timeout = timeout
+	return ab
+}
+
+// operationContext returns the context for running the pipeline, bounded by the timeout
+func (ab *AggregateBuilder) operationContext() (context.Context, context.CancelFunc) {
+	return withTimeout(ab.ctx, ab.timeout)
+}
+
+// Match adds a $match stage
+func (ab *AggregateBuilder) Match(filter bson.M) *AggregateBuilder {
+	return ab.Stage("$match", filter)
+}
+
+// Group adds a $group stage grouping by id (a field path like "$department",
+// or a document for compound keys) with the given accumulators, e.g.
+// Group("$department", bson.M{"total": bson.M{"$sum": "$salary"}})
+func (ab *AggregateBuilder) Group(id interface{}, accumulators bson.M) *AggregateBuilder {
+	group := bson.M{"_id": id}
+	for field, accumulator := range accumulators {
+		group[field] = accumulator
+	}
+	return ab.Stage("$group", group)
+}
+
+// Sort adds a $sort stage on a field; direction is "asc" or "desc".
+// Chained Sort calls are merged into a single stage.
+func (ab *AggregateBuilder) Sort(field string, direction string) *AggregateBuilder {
+	order := 1
+	if strings.ToLower(direction) == "desc" {
+		order = -1
+	}
+
+	if last := len(ab.pipeline) - 1; last >= 0 {
+		if sort, ok := ab.pipeline[last]["$sort"].(bson.D); ok {
+			ab.pipeline[last]["$sort"] = append(sort, bson.E{Key: field, Value: order})
+			return ab
+		}
+	}
+	return ab.Stage("$sort", bson.D{{Key: field, Value: order}})
+}
+
+// Project adds a $project stage
+func (ab *AggregateBuilder) Project(projection bson.M) *AggregateBuilder {
+	return ab.Stage("$project", projection)
+}
+
+// Lookup adds a $lookup stage joining documents from another collection
+func (ab *AggregateBuilder) Lookup(from, localField, foreignField, as string) *AggregateBuilder {
+	return ab.Stage("$lookup", bson.M{
+		"from":         from,
+		"localField":   localField,
+		"foreignField": foreignField,
+		"as":           as,
+	})
+}
+
+// Unwind adds an $unwind stage for an array field ("$" prefix optional).
+// Documents with a missing or empty array are kept when preserveEmpty is true.
+func (ab *AggregateBuilder) Unwind(field string, preserveEmpty ...bool) *AggregateBuilder {
+	if !strings.HasPrefix(field, "$") {
+		field = "$" + field
+	}
+	if len(preserveEmpty) > 0 && preserveEmpty[0] {
+		return ab.Stage("$unwind", bson.M{"path": field, "preserveNullAndEmptyArrays": true})
+	}
+	return ab.Stage("$unwind", field)
+}
+
+// Skip adds a $skip stage
+func (ab *AggregateBuilder) Skip(skip int64) *AggregateBuilder {
+	return ab.Stage("$skip", skip)
+}
+
+// Limit adds a $limit stage
+func (ab *AggregateBuilder) Limit(limit int64) *AggregateBuilder {
+	return ab.Stage("$limit", limit)
+}
+
+// Stage adds a raw pipeline stage, e.g. Stage("$addFields", bson.M{...})
+func (ab *AggregateBuilder) Stage(operator string, value interface{}) *AggregateBuilder {
+	ab.pipeline = append(ab.pipeline, bson.M{operator: value})
+	return ab
+}
+
+// Pipeline returns the compiled pipeline
+func (ab *AggregateBuilder) Pipeline() []bson.M {
+	return ab.pipeline
+}
+
+// Run executes the pipeline and decodes the results into dest
+func (ab *AggregateBuilder) Run(dest interface{}) error {
+	if ab.collection == "" {
+		return fmt.Errorf("aggregation requires a collection")
+	}
+
+	coll := ab.db.Database.Collection(ab.collection)
+	ctx, cancel := ab.operationContext()
+	defer cancel()
+
+	cursor, err := coll.Aggregate(ctx, ab.pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, dest)
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Unordered makes BulkWrite continue past failed operations and lets the
+// server execute them in any order. Bulk writes are ordered by default.
+func (qb *QueryBuilder) Unordered() *QueryBuilder {
+	qb.unordered = true
+	return qb
+}
+
+// BulkWrite executes mixed insert, update, replace and delete operations in a
+// single request. The result reports inserted, matched, modified, upserted and
+// deleted counts. Build operations with the Bulk* helpers or the driver's
+// write models directly.
+func (qb *QueryBuilder) BulkWrite(models []mongo.WriteModel) (*mongo.BulkWriteResult, error) {
+	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
+
+	opts := options.BulkWrite().SetOrdered(!qb.unordered)
+	return coll.BulkWrite(ctx, models, opts)
+}
+
+// BulkInsert builds an insert operation, setting timestamps and running the
+// BeforeInsert hook on models
+func BulkInsert(document interface{}) mongo.WriteModel {
+	beforeInsert(document)
+	return mongo.NewInsertOneModel().SetDocument(document)
+}
+
+// BulkUpdateOne builds an operation updating the first document matching filter
+func BulkUpdateOne(filter, update bson.M, upsert bool) mongo.WriteModel {
+	touchUpdate(update)
+	if upsert {
+		setCreatedOnInsert(update)
+	}
+	return mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(upsert)
+}
+
+// BulkUpdateMany builds an operation updating every document matching filter
+func BulkUpdateMany(filter, update bson.M) mongo.WriteModel {
+	touchUpdate(update)
+	return mongo.NewUpdateManyModel().SetFilter(filter).SetUpdate(update)
+}
+
+// BulkDeleteOne builds an operation deleting the first document matching filter
+func BulkDeleteOne(filter bson.M) mongo.WriteModel {
+	return mongo.NewDeleteOneModel().SetFilter(filter)
+}
+
+// BulkDeleteMany builds an operation deleting every document matching filter
+func BulkDeleteMany(filter bson.M) mongo.WriteModel {
+	return mongo.NewDeleteManyModel().SetFilter(filter)
+}
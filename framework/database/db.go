@@ -2,7 +2,10 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -11,11 +14,16 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrNotFound is returned when a query that expects a document matches none
+var ErrNotFound = errors.New("document not found")
+
 // DB represents a MongoDB database connection
 type DB struct {
 	Client   *mongo.Client
 	Database *mongo.Database
 	Name     string
+
+	defaultTimeout atomic.Int64 // time.Duration
 }
 
 // Model represents a base model with common fields for MongoDB
@@ -23,6 +31,7 @@ type Model struct {
 	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+	DeletedAt *time.Time         `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 }
 
 // QueryBuilder provides a fluent interface for building MongoDB queries
@@ -35,18 +44,67 @@ type QueryBuilder struct {
 	skip       int64
 	projection bson.M
 	ctx        context.Context
+	timeout    time.Duration
+	orMode     bool
+
+	softDeletes bool
+	trashed     trashedScope
+	unordered   bool
+	model       interface{}
+}
+
+// trashedScope controls which documents a soft-deleting query sees
+type trashedScope int
+
+const (
+	withoutTrashed trashedScope = iota
+	withTrashed
+	onlyTrashed
+)
+
+// PaginationResult holds pagination metadata returned by Paginate
+type PaginationResult struct {
+	Total    int64 `json:"total"`
+	Page     int64 `json:"page"`
+	PerPage  int64 `json:"per_page"`
+	LastPage int64 `json:"last_page"`
+	HasMore  bool  `json:"has_more"`
 }
 
+// defaultConnectTimeout bounds the initial connect and ping when no timeout is configured
+const defaultConnectTimeout = 10 * time.Second
+
 // Connect creates a new MongoDB connection
 func Connect(uri, dbName string) (*DB, error) {
-	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(uri))
+	return ConnectWithOptions(uri, dbName, nil)
+}
+
+// ConnectWithOptions creates a new MongoDB connection with custom client options.
+// The options are applied on top of the URI. The initial connect and ping are
+// bounded by the options' ConnectTimeout (10s by default), so an unreachable
+// server fails instead of hanging.
+func ConnectWithOptions(uri, dbName string, opts *options.ClientOptions) (*DB, error) {
+	clientOpts := []*options.ClientOptions{options.Client().ApplyURI(uri)}
+	timeout := defaultConnectTimeout
+	if opts != nil {
+		clientOpts = append(clientOpts, opts)
+		if opts.ConnectTimeout != nil && *opts.ConnectTimeout > 0 {
+			timeout = *opts.ConnectTimeout
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, clientOpts...)
 	if err != nil {
 		return nil, err
 	}
 
 	// Ping the database to verify connection
-	if err := client.Ping(context.TODO(), nil); err != nil {
-		return nil, err
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to reach MongoDB within %v: %w", timeout, err)
 	}
 
 	database := client.Database(dbName)
@@ -58,6 +116,43 @@ func Connect(uri, dbName string) (*DB, error) {
 	}, nil
 }
 
+// ClientOptionsFromConfig builds client options from a config map such as
+// database.connections.mongodb.options. Supported keys are "maxPoolSize",
+// "minPoolSize" and "timeout" (a duration string like "5s").
+func ClientOptionsFromConfig(config map[string]interface{}) (*options.ClientOptions, error) {
+	opts := options.Client()
+
+	if size, ok := configUint(config["maxPoolSize"]); ok {
+		opts.SetMaxPoolSize(size)
+	}
+	if size, ok := configUint(config["minPoolSize"]); ok {
+		opts.SetMinPoolSize(size)
+	}
+	if value, ok := config["timeout"].(string); ok && value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MongoDB timeout '%s': %w", value, err)
+		}
+		opts.SetConnectTimeout(timeout)
+		opts.SetServerSelectionTimeout(timeout)
+	}
+
+	return opts, nil
+}
+
+// configUint converts a numeric config value to uint64
+func configUint(value interface{}) (uint64, bool) {
+	switch v := value.(type) {
+	case int:
+		return uint64(v), v > 0
+	case int64:
+		return uint64(v), v > 0
+	case float64:
+		return uint64(v), v > 0
+	}
+	return 0, false
+}
+
 // NewQueryBuilder creates a new query builder
 func (db *DB) NewQueryBuilder() *QueryBuilder {
 	return &QueryBuilder{
@@ -66,68 +161,294 @@ func (db *DB) NewQueryBuilder() *QueryBuilder {
 		sort:       bson.D{},
 		projection: bson.M{},
 		ctx:        context.Background(),
+		timeout:    db.DefaultTimeout(),
 	}
 }
 
+// SetDefaultTimeout sets the operation timeout applied to every query and
+// aggregation builder created afterwards (0 disables it). It is safe to call
+// while other goroutines build queries.
+func (db *DB) SetDefaultTimeout(timeout time.Duration) {
+	db.defaultTimeout.Store(int64(timeout))
+}
+
+// DefaultTimeout returns the timeout set with SetDefaultTimeout
+func (db *DB) DefaultTimeout() time.Duration {
+	return time.Duration(db.defaultTimeout.Load())
+}
+
 // Collection sets the collection name
 func (qb *QueryBuilder) Collection(collection string) *QueryBuilder {
 	qb.collection = collection
 	return qb
 }
 
-// Where adds a filter condition
+// Model binds the model being updated, so Update, UpdateOne, UpdateOneUpsert
+// and Upsert call its BeforeUpdate and AfterUpdate hooks. The collection
+// defaults to the model's collection name when none is set.
+func (qb *QueryBuilder) Model(model interface{}) *QueryBuilder {
+	qb.model = model
+	if qb.collection == "" {
+		qb.collection = CollectionNameOf(model)
+	}
+	return qb
+}
+
+// Where adds a filter condition. In Or mode it behaves like OrWhere.
 func (qb *QueryBuilder) Where(field string, operator string, value interface{}) *QueryBuilder {
+	return qb.where(func(filter bson.M) {
+		applyCondition(filter, field, operator, value)
+	})
+}
+
+// where adds the condition written by apply: into the filter itself in And
+// mode, or into a new condition ORed with the filter in Or mode
+func (qb *QueryBuilder) where(apply func(filter bson.M)) *QueryBuilder {
+	if qb.orMode {
+		condition := bson.M{}
+		apply(condition)
+		qb.orFilter(condition)
+		return qb
+	}
+	apply(qb.filter)
+	return qb
+}
+
+// Or switches to Or mode: subsequent conditions (Where, WhereIn, WhereGroup,
+// ...) are ORed with everything before them, e.g. Where("a", "=", 1).Or().Where("b", "=", 2)
+// builds a OR b. Conditions apply left to right, so
+// Where(a).Or().Where(b).And().Where(c) builds (a OR b) AND c.
+func (qb *QueryBuilder) Or() *QueryBuilder {
+	qb.orMode = true
+	return qb
+}
+
+// And switches back to the default And mode, where subsequent conditions are
+// ANDed with the existing filter
+func (qb *QueryBuilder) And() *QueryBuilder {
+	qb.orMode = false
+	return qb
+}
+
+// OrWhere adds a condition combined with the existing filter using $or,
+// i.e. (previous conditions) OR (field operator value). Conditions added
+// afterwards with Where are ANDed with the whole $or.
+func (qb *QueryBuilder) OrWhere(field string, operator string, value interface{}) *QueryBuilder {
+	condition := bson.M{}
+	applyCondition(condition, field, operator, value)
+	qb.orFilter(condition)
+	return qb
+}
+
+// WhereGroup adds a group of conditions, built by fn, combined with the
+// existing filter using $and (or $or in Or mode)
+func (qb *QueryBuilder) WhereGroup(fn func(*QueryBuilder)) *QueryBuilder {
+	if qb.orMode {
+		return qb.OrWhereGroup(fn)
+	}
+
+	group := &QueryBuilder{filter: bson.M{}}
+	fn(group)
+	if len(group.filter) == 0 {
+		return qb
+	}
+
+	if conditions, ok := qb.filter["$and"].(bson.A); ok {
+		qb.filter["$and"] = append(conditions, group.filter)
+	} else {
+		qb.filter["$and"] = bson.A{group.filter}
+	}
+	return qb
+}
+
+// OrWhereGroup adds a group of conditions, built by fn, combined with the
+// existing filter using $or
+func (qb *QueryBuilder) OrWhereGroup(fn func(*QueryBuilder)) *QueryBuilder {
+	group := &QueryBuilder{filter: bson.M{}}
+	fn(group)
+	if len(group.filter) == 0 {
+		return qb
+	}
+
+	qb.orFilter(group.filter)
+	return qb
+}
+
+// orFilter combines the current filter with condition using $or
+func (qb *QueryBuilder) orFilter(condition bson.M) {
+	if len(qb.filter) == 0 {
+		for key, value := range condition {
+			qb.filter[key] = value
+		}
+		return
+	}
+
+	// Extend an existing top-level $or instead of nesting it
+	if conditions, ok := qb.filter["$or"].(bson.A); ok && len(qb.filter) == 1 {
+		qb.filter["$or"] = append(conditions, condition)
+		return
+	}
+
+	qb.filter = bson.M{"$or": bson.A{qb.filter, condition}}
+}
+
+// applyCondition writes a field condition into the given filter
+func applyCondition(filter bson.M, field string, operator string, value interface{}) {
 	switch operator {
 	case "=", "==":
-		qb.filter[field] = value
+		filter[field] = value
 	case "!=", "<>":
-		qb.filter[field] = bson.M{"$ne": value}
+		filter[field] = bson.M{"$ne": value}
 	case ">":
-		qb.filter[field] = bson.M{"$gt": value}
+		filter[field] = bson.M{"$gt": value}
 	case ">=":
-		qb.filter[field] = bson.M{"$gte": value}
+		filter[field] = bson.M{"$gte": value}
 	case "<":
-		qb.filter[field] = bson.M{"$lt": value}
+		filter[field] = bson.M{"$lt": value}
 	case "<=":
-		qb.filter[field] = bson.M{"$lte": value}
+		filter[field] = bson.M{"$lte": value}
 	case "like":
-		qb.filter[field] = bson.M{"$regex": value, "$options": "i"}
+		filter[field] = bson.M{"$regex": value, "$options": "i"}
 	case "in":
 		if arr, ok := value.([]interface{}); ok {
-			qb.filter[field] = bson.M{"$in": arr}
+			filter[field] = bson.M{"$in": arr}
 		}
 	case "nin":
 		if arr, ok := value.([]interface{}); ok {
-			qb.filter[field] = bson.M{"$nin": arr}
+			filter[field] = bson.M{"$nin": arr}
 		}
 	default:
-		qb.filter[field] = value
+		filter[field] = value
 	}
-	return qb
 }
 
 // WhereIn adds an $in filter condition
 func (qb *QueryBuilder) WhereIn(field string, values []interface{}) *QueryBuilder {
-	qb.filter[field] = bson.M{"$in": values}
-	return qb
+	return qb.where(func(filter bson.M) {
+		filter[field] = bson.M{"$in": values}
+	})
 }
 
 // WhereNotIn adds a $nin filter condition
 func (qb *QueryBuilder) WhereNotIn(field string, values []interface{}) *QueryBuilder {
-	qb.filter[field] = bson.M{"$nin": values}
-	return qb
+	return qb.where(func(filter bson.M) {
+		filter[field] = bson.M{"$nin": values}
+	})
 }
 
 // WhereExists checks if a field exists
 func (qb *QueryBuilder) WhereExists(field string) *QueryBuilder {
-	qb.filter[field] = bson.M{"$exists": true}
-	return qb
+	return qb.where(func(filter bson.M) {
+		filter[field] = bson.M{"$exists": true}
+	})
 }
 
 // WhereNotExists checks if a field doesn't exist
 func (qb *QueryBuilder) WhereNotExists(field string) *QueryBuilder {
-	qb.filter[field] = bson.M{"$exists": false}
-	return qb
+	return qb.where(func(filter bson.M) {
+		filter[field] = bson.M{"$exists": false}
+	})
+}
+
+// WhereBetween matches documents whose field is between low and high (both inclusive).
+// In And mode the range is merged into any existing condition on the field.
+func (qb *QueryBuilder) WhereBetween(field string, low, high interface{}) *QueryBuilder {
+	return qb.where(func(filter bson.M) {
+		mergeCondition(filter, field, bson.M{"$gte": low, "$lte": high})
+	})
+}
+
+// mergeCondition adds operator conditions to a field of filter, keeping the
+// operators already set on it. An existing equality value is kept as $eq.
+func mergeCondition(filter bson.M, field string, operators bson.M) {
+	merged := bson.M{}
+	if existing, ok := filter[field]; ok {
+		if ops, isOps := existing.(bson.M); isOps && isOperatorMap(ops) {
+			for op, value := range ops {
+				merged[op] = value
+			}
+		} else {
+			merged["$eq"] = existing
+		}
+	}
+	for op, value := range operators {
+		merged[op] = value
+	}
+	filter[field] = merged
+}
+
+// isOperatorMap checks if every key of a condition is a query operator
+func isOperatorMap(m bson.M) bool {
+	if len(m) == 0 {
+		return false
+	}
+	for key := range m {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+// WhereDate matches documents whose field falls on the same calendar day as date.
+// The day boundaries are computed in the given location (defaults to date's location).
+func (qb *QueryBuilder) WhereDate(field string, date time.Time, loc ...*time.Location) *QueryBuilder {
+	start := startOfDay(date, loc...)
+	return qb.where(func(filter bson.M) {
+		mergeCondition(filter, field, bson.M{"$gte": start, "$lt": start.AddDate(0, 0, 1)})
+	})
+}
+
+// WhereDateBetween matches documents whose field falls between the calendar days
+// of from and to (both inclusive). The day boundaries are computed in the given
+// location (defaults to the locations of from and to).
+func (qb *QueryBuilder) WhereDateBetween(field string, from, to time.Time, loc ...*time.Location) *QueryBuilder {
+	start := startOfDay(from, loc...)
+	end := startOfDay(to, loc...).AddDate(0, 0, 1)
+	return qb.where(func(filter bson.M) {
+		mergeCondition(filter, field, bson.M{"$gte": start, "$lt": end})
+	})
+}
+
+// startOfDay returns midnight of t's calendar day in the given location
+func startOfDay(t time.Time, loc ...*time.Location) time.Time {
+	if len(loc) > 0 && loc[0] != nil {
+		t = t.In(loc[0])
+	}
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// WhereExpr adds an $expr condition, allowing aggregation expressions in queries.
+// In And mode multiple expressions are combined with $and.
+func (qb *QueryBuilder) WhereExpr(expr bson.M) *QueryBuilder {
+	return qb.where(func(filter bson.M) {
+		if existing, ok := filter["$expr"]; ok {
+			filter["$expr"] = bson.M{"$and": bson.A{existing, expr}}
+			return
+		}
+		filter["$expr"] = expr
+	})
+}
+
+// WhereFields compares two document fields, e.g. WhereFields("spent", ">", "budget")
+func (qb *QueryBuilder) WhereFields(fieldA, operator, fieldB string) *QueryBuilder {
+	var op string
+	switch operator {
+	case "!=", "<>":
+		op = "$ne"
+	case ">":
+		op = "$gt"
+	case ">=":
+		op = "$gte"
+	case "<":
+		op = "$lt"
+	case "<=":
+		op = "$lte"
+	default:
+		op = "$eq"
+	}
+	return qb.WhereExpr(bson.M{op: bson.A{"$" + fieldA, "$" + fieldB}})
 }
 
 // OrderBy adds sorting
@@ -165,16 +486,89 @@ func (qb *QueryBuilder) Select(fields ...string) *QueryBuilder {
 	return qb
 }
 
-// Context sets the context for the query
+// Context sets the context for the query. The builder's timeout still
+// applies on top of it; the earliest deadline wins.
 func (qb *QueryBuilder) Context(ctx context.Context) *QueryBuilder {
 	qb.ctx = ctx
 	return qb
 }
 
+// Timeout overrides the DB default operation timeout for this query (0 disables it)
+func (qb *QueryBuilder) Timeout(timeout time.Duration) *QueryBuilder {
+	qb.timeout = timeout
+	return qb
+}
+
+// operationContext returns the context for a single operation, bounded by the timeout
+func (qb *QueryBuilder) operationContext() (context.Context, context.CancelFunc) {
+	return withTimeout(qb.ctx, qb.timeout)
+}
+
+// next advances a cursor with its own timeout, so the timeout bounds each
+// batch fetch rather than the whole iteration
+func (qb *QueryBuilder) next(cursor *mongo.Cursor) bool {
+	ctx, cancel := qb.operationContext()
+	defer cancel()
+	return cursor.Next(ctx)
+}
+
+// closeCursor closes a cursor with the builder's timeout
+func (qb *QueryBuilder) closeCursor(cursor *mongo.Cursor) {
+	ctx, cancel := qb.operationContext()
+	defer cancel()
+	cursor.Close(ctx)
+}
+
+// withTimeout derives a context with the timeout, or returns ctx unchanged when it is 0
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // Get executes the query and returns multiple documents
 func (qb *QueryBuilder) Get(dest interface{}) error {
 	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
 
+	cursor, err := coll.Find(ctx, qb.queryFilter(), qb.findOptions())
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, dest)
+}
+
+// Each executes the query and calls fn for every document without loading the
+// whole result set into memory. The callback decodes the current document
+// itself, e.g. cursor.Decode(&user). Iteration stops at the first error
+// returned by fn or when the query context is done; the cursor is always closed.
+// The builder's timeout applies to the query and to each batch fetched from
+// the cursor, not to the whole iteration, so slow callbacks don't time it out.
+func (qb *QueryBuilder) Each(fn func(cursor *mongo.Cursor) error) error {
+	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	cursor, err := coll.Find(ctx, qb.queryFilter(), qb.findOptions())
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer qb.closeCursor(cursor)
+
+	for qb.next(cursor) {
+		if err := fn(cursor); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// findOptions builds the find options from sorting, limit, skip and projection
+func (qb *QueryBuilder) findOptions() *options.FindOptions {
 	opts := options.Find()
 
 	if len(qb.sort) > 0 {
@@ -190,18 +584,14 @@ func (qb *QueryBuilder) Get(dest interface{}) error {
 		opts.SetProjection(qb.projection)
 	}
 
-	cursor, err := coll.Find(qb.ctx, qb.filter, opts)
-	if err != nil {
-		return err
-	}
-	defer cursor.Close(qb.ctx)
-
-	return cursor.All(qb.ctx, dest)
+	return opts
 }
 
 // First executes the query and returns the first document
 func (qb *QueryBuilder) First(dest interface{}) error {
 	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
 
 	opts := options.FindOne()
 
@@ -212,32 +602,156 @@ func (qb *QueryBuilder) First(dest interface{}) error {
 		opts.SetProjection(qb.projection)
 	}
 
-	result := coll.FindOne(qb.ctx, qb.filter, opts)
+	result := coll.FindOne(ctx, qb.queryFilter(), opts)
 
-	return result.Decode(dest)
+	return translateError(result.Decode(dest))
 }
 
 // Count returns the count of matching documents
 func (qb *QueryBuilder) Count() (int64, error) {
 	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
+
+	return coll.CountDocuments(ctx, qb.queryFilter())
+}
+
+// Exists reports whether at least one document matches the query
+func (qb *QueryBuilder) Exists() (bool, error) {
+	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
+
+	count, err := coll.CountDocuments(ctx, qb.queryFilter(), options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// EstimatedCount returns an approximate count of all documents in the
+// collection from its metadata. It is fast on large collections but ignores
+// the filter and may be inexact after unclean shutdowns. The metadata count
+// cannot exclude soft-deleted documents, so they are always included; use
+// Count with SoftDeletes for a count without them.
+func (qb *QueryBuilder) EstimatedCount() (int64, error) {
+	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
+
+	return coll.EstimatedDocumentCount(ctx)
+}
+
+// Distinct decodes the distinct values of a field across the matching documents
+// into dest, which must be a pointer to a slice
+func (qb *QueryBuilder) Distinct(field string, dest interface{}) error {
+	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
+
+	values, err := coll.Distinct(ctx, field, qb.queryFilter())
+	if err != nil {
+		return err
+	}
+
+	return decodeValues(bson.A(values), dest)
+}
+
+// Pluck decodes the values of a single field across the matching documents into
+// dest, which must be a pointer to a slice. Sorting, limit and skip are applied;
+// documents without the field are skipped. Dotted paths are supported.
+func (qb *QueryBuilder) Pluck(field string, dest interface{}) error {
+	coll := qb.db.Database.Collection(qb.collection)
+	projection := bson.M{field: 1}
+	if field != "_id" {
+		projection["_id"] = 0
+	}
+
+	opts := qb.findOptions().SetProjection(projection)
+
+	ctx, cancel := qb.operationContext()
+	cursor, err := coll.Find(ctx, qb.queryFilter(), opts)
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer qb.closeCursor(cursor)
+
+	path := strings.Split(field, ".")
+	values := bson.A{}
+	for qb.next(cursor) {
+		value, err := cursor.Current.LookupErr(path...)
+		if err != nil {
+			continue
+		}
+		values = append(values, value)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	return decodeValues(values, dest)
+}
 
-	return coll.CountDocuments(qb.ctx, qb.filter)
+// decodeValues decodes a list of BSON values into dest (a pointer to a slice)
+func decodeValues(values bson.A, dest interface{}) error {
+	raw, err := bson.Marshal(bson.D{{Key: "values", Value: values}})
+	if err != nil {
+		return err
+	}
+	return bson.Raw(raw).Lookup("values").Unmarshal(dest)
+}
+
+// Paginate counts the matching documents, then fetches the given page into dest.
+// Pages start at 1; sorting and projection are preserved. The count and the
+// page fetch are each bounded by the builder's timeout.
+func (qb *QueryBuilder) Paginate(page, perPage int64, dest interface{}) (*PaginationResult, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 15
+	}
+
+	total, err := qb.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	qb.skip = (page - 1) * perPage
+	qb.limit = perPage
+	if err := qb.Get(dest); err != nil {
+		return nil, err
+	}
+
+	lastPage := (total + perPage - 1) / perPage
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	return &PaginationResult{
+		Total:    total,
+		Page:     page,
+		PerPage:  perPage,
+		LastPage: lastPage,
+		HasMore:  page < lastPage,
+	}, nil
 }
 
 // Insert inserts a new document
 func (qb *QueryBuilder) Insert(document interface{}) (*primitive.ObjectID, error) {
 	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
 
-	// Set timestamps if it's a model
-	if model, ok := document.(interface{ SetTimestamps() }); ok {
-		model.SetTimestamps()
-	}
+	beforeInsert(document)
 
-	result, err := coll.InsertOne(qb.ctx, document)
+	result, err := coll.InsertOne(ctx, document)
 	if err != nil {
 		return nil, err
 	}
 
+	if hook, ok := document.(AfterInsertHook); ok {
+		hook.AfterInsert()
+	}
+
 	if objectID, ok := result.InsertedID.(primitive.ObjectID); ok {
 		return &objectID, nil
 	}
@@ -248,19 +762,24 @@ func (qb *QueryBuilder) Insert(document interface{}) (*primitive.ObjectID, error
 // InsertMany inserts multiple documents
 func (qb *QueryBuilder) InsertMany(documents []interface{}) ([]primitive.ObjectID, error) {
 	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
 
-	// Set timestamps for models
 	for _, doc := range documents {
-		if model, ok := doc.(interface{ SetTimestamps() }); ok {
-			model.SetTimestamps()
-		}
+		beforeInsert(doc)
 	}
 
-	result, err := coll.InsertMany(qb.ctx, documents)
+	result, err := coll.InsertMany(ctx, documents)
 	if err != nil {
 		return nil, err
 	}
 
+	for _, doc := range documents {
+		if hook, ok := doc.(AfterInsertHook); ok {
+			hook.AfterInsert()
+		}
+	}
+
 	var ids []primitive.ObjectID
 	for _, id := range result.InsertedIDs {
 		if objectID, ok := id.(primitive.ObjectID); ok {
@@ -271,73 +790,300 @@ func (qb *QueryBuilder) InsertMany(documents []interface{}) ([]primitive.ObjectI
 	return ids, nil
 }
 
-// Update updates existing documents
+// Update updates existing documents. The update hooks of a model bound with
+// Model run around the write; changes they make to the model are not part of
+// the update document.
 func (qb *QueryBuilder) Update(update bson.M) (*mongo.UpdateResult, error) {
 	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
+	touchUpdate(update)
+	qb.beforeUpdate()
 
-	// Add updated_at timestamp
-	if update["$set"] == nil {
-		update["$set"] = bson.M{}
+	result, err := coll.UpdateMany(ctx, qb.queryFilter(), update)
+	if err != nil {
+		return nil, err
 	}
-	if setFields, ok := update["$set"].(bson.M); ok {
-		setFields["updated_at"] = time.Now()
+	qb.afterUpdate()
+	return result, nil
+}
+
+// UpdateOne updates a single document, running the bound model's update hooks
+func (qb *QueryBuilder) UpdateOne(update bson.M) (*mongo.UpdateResult, error) {
+	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
+	touchUpdate(update)
+	qb.beforeUpdate()
+
+	result, err := coll.UpdateOne(ctx, qb.queryFilter(), update)
+	if err != nil {
+		return nil, err
 	}
+	qb.afterUpdate()
+	return result, nil
+}
+
+// UpdateOneUpsert updates a single document, inserting it if no document matches
+func (qb *QueryBuilder) UpdateOneUpsert(update bson.M) (*mongo.UpdateResult, error) {
+	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
+	touchUpdate(update)
+	setCreatedOnInsert(update)
+	qb.beforeUpdate()
 
-	return coll.UpdateMany(qb.ctx, qb.filter, update)
+	result, err := coll.UpdateOne(ctx, qb.queryFilter(), update, options.Update().SetUpsert(true))
+	if err != nil {
+		return nil, err
+	}
+	qb.afterUpdate()
+	return result, nil
 }
 
-// UpdateOne updates a single document
-func (qb *QueryBuilder) UpdateOne(update bson.M) (*mongo.UpdateResult, error) {
+// Upsert atomically updates the first matching document, inserting it if none
+// matches, and decodes the resulting document into dest
+func (qb *QueryBuilder) Upsert(update bson.M, dest interface{}) error {
 	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
+	touchUpdate(update)
+	setCreatedOnInsert(update)
+	qb.beforeUpdate()
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+	if len(qb.sort) > 0 {
+		opts.SetSort(qb.sort)
+	}
+	if len(qb.projection) > 0 {
+		opts.SetProjection(qb.projection)
+	}
+
+	if err := coll.FindOneAndUpdate(ctx, qb.queryFilter(), update, opts).Decode(dest); err != nil {
+		return translateError(err)
+	}
+	qb.afterUpdate()
+	return nil
+}
 
-	// Add updated_at timestamp
+// beforeUpdate runs the BeforeUpdate hook of the bound model
+func (qb *QueryBuilder) beforeUpdate() {
+	if hook, ok := qb.model.(BeforeUpdateHook); ok {
+		hook.BeforeUpdate()
+	}
+}
+
+// afterUpdate runs the AfterUpdate hook of the bound model
+func (qb *QueryBuilder) afterUpdate() {
+	if hook, ok := qb.model.(AfterUpdateHook); ok {
+		hook.AfterUpdate()
+	}
+}
+
+// translateError wraps driver errors with the package's own errors, so both
+// errors.Is(err, ErrNotFound) and errors.Is(err, mongo.ErrNoDocuments) hold.
+// All other errors pass through unchanged.
+func translateError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return err
+}
+
+// touchUpdate adds the updated_at timestamp to an update document
+func touchUpdate(update bson.M) {
 	if update["$set"] == nil {
 		update["$set"] = bson.M{}
 	}
 	if setFields, ok := update["$set"].(bson.M); ok {
 		setFields["updated_at"] = time.Now()
 	}
+}
 
-	return coll.UpdateOne(qb.ctx, qb.filter, update)
+// setCreatedOnInsert sets created_at for documents inserted by an upsert,
+// unless the update already sets it
+func setCreatedOnInsert(update bson.M) {
+	if setFields, ok := update["$set"].(bson.M); ok {
+		if _, exists := setFields["created_at"]; exists {
+			return
+		}
+	}
+	if update["$setOnInsert"] == nil {
+		update["$setOnInsert"] = bson.M{}
+	}
+	if insertFields, ok := update["$setOnInsert"].(bson.M); ok {
+		if _, exists := insertFields["created_at"]; !exists {
+			insertFields["created_at"] = time.Now()
+		}
+	}
 }
 
-// ReplaceOne replaces a single document
+// ReplaceOne replaces a single document, calling the model's BeforeUpdate
+// and AfterUpdate hooks
 func (qb *QueryBuilder) ReplaceOne(replacement interface{}) (*mongo.UpdateResult, error) {
 	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
 
 	// Set timestamps if it's a model
 	if model, ok := replacement.(interface{ SetTimestamps() }); ok {
 		model.SetTimestamps()
 	}
+	if hook, ok := replacement.(BeforeUpdateHook); ok {
+		hook.BeforeUpdate()
+	}
 
-	return coll.ReplaceOne(qb.ctx, qb.filter, replacement)
+	result, err := coll.ReplaceOne(ctx, qb.queryFilter(), replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	if hook, ok := replacement.(AfterUpdateHook); ok {
+		hook.AfterUpdate()
+	}
+	return result, nil
 }
 
 // Delete deletes documents
 func (qb *QueryBuilder) Delete() (*mongo.DeleteResult, error) {
 	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
 
-	return coll.DeleteMany(qb.ctx, qb.filter)
+	return coll.DeleteMany(ctx, qb.queryFilter())
 }
 
 // DeleteOne deletes a single document
 func (qb *QueryBuilder) DeleteOne() (*mongo.DeleteResult, error) {
 	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
 
-	return coll.DeleteOne(qb.ctx, qb.filter)
+	return coll.DeleteOne(ctx, qb.queryFilter())
 }
 
-// Aggregate performs aggregation pipeline
+// Aggregate performs aggregation pipeline. On a soft-deleting query a
+// $match stage applying the soft delete scope is run first, so trashed
+// documents are excluded unless WithTrashed is used.
 func (qb *QueryBuilder) Aggregate(pipeline []bson.M, dest interface{}) error {
 	coll := qb.db.Database.Collection(qb.collection)
+	ctx, cancel := qb.operationContext()
+	defer cancel()
 
-	cursor, err := coll.Aggregate(qb.ctx, pipeline)
+	cursor, err := coll.Aggregate(ctx, qb.scopedPipeline(pipeline))
 	if err != nil {
 		return err
 	}
-	defer cursor.Close(qb.ctx)
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, dest)
+}
+
+// Soft deletes
+
+// SoftDeletes scopes the query to documents that aren't soft deleted, i.e.
+// documents without a deleted_at value. Soft deletes are opt-in per query.
+func (qb *QueryBuilder) SoftDeletes() *QueryBuilder {
+	qb.softDeletes = true
+	return qb
+}
+
+// WithTrashed includes soft-deleted documents in a soft-deleting query
+func (qb *QueryBuilder) WithTrashed() *QueryBuilder {
+	qb.softDeletes = true
+	qb.trashed = withTrashed
+	return qb
+}
+
+// OnlyTrashed scopes the query to soft-deleted documents
+func (qb *QueryBuilder) OnlyTrashed() *QueryBuilder {
+	qb.softDeletes = true
+	qb.trashed = onlyTrashed
+	return qb
+}
+
+// SoftDelete marks the matching documents as deleted by setting deleted_at
+func (qb *QueryBuilder) SoftDelete() (*mongo.UpdateResult, error) {
+	qb.softDeletes = true
+	if qb.trashed == onlyTrashed {
+		qb.trashed = withoutTrashed
+	}
+	return qb.Update(bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+}
 
-	return cursor.All(qb.ctx, dest)
+// Restore clears deleted_at on the matching soft-deleted documents
+func (qb *QueryBuilder) Restore() (*mongo.UpdateResult, error) {
+	qb.softDeletes = true
+	qb.trashed = onlyTrashed
+	return qb.Update(bson.M{"$unset": bson.M{"deleted_at": ""}})
+}
+
+// scopedPipeline prepends the soft delete scope to an aggregation pipeline
+func (qb *QueryBuilder) scopedPipeline(pipeline []bson.M) []bson.M {
+	scope, scoped := qb.trashedScope()
+	if !scoped {
+		return pipeline
+	}
+	return append([]bson.M{{"$match": bson.M{"deleted_at": scope}}}, pipeline...)
+}
+
+// trashedScope returns the deleted_at condition of a soft-deleting query,
+// reporting false when trashed documents aren't filtered
+func (qb *QueryBuilder) trashedScope() (interface{}, bool) {
+	if !qb.softDeletes || qb.trashed == withTrashed {
+		return nil, false
+	}
+	if qb.trashed == onlyTrashed {
+		return bson.M{"$ne": nil}, true
+	}
+	return nil, true
+}
+
+// queryFilter returns the filter with the soft delete scope applied
+func (qb *QueryBuilder) queryFilter() bson.M {
+	scope, scoped := qb.trashedScope()
+	if !scoped {
+		return qb.filter
+	}
+
+	if _, exists := qb.filter["deleted_at"]; exists {
+		return bson.M{"$and": bson.A{qb.filter, bson.M{"deleted_at": scope}}}
+	}
+
+	filter := make(bson.M, len(qb.filter)+1)
+	for key, value := range qb.filter {
+		filter[key] = value
+	}
+	filter["deleted_at"] = scope
+	return filter
+}
+
+// Model lifecycle hooks. Models (usually via an embedded Model) implement any
+// of these to run logic such as slug generation or validation around writes:
+// Insert, InsertMany and BulkInsert call the insert hooks; ReplaceOne, and the
+// Update methods of a builder bound with Model, call the update hooks. After
+// hooks only run when the write succeeds.
+type (
+	// BeforeInsertHook is called before a model is inserted
+	BeforeInsertHook interface{ BeforeInsert() }
+	// AfterInsertHook is called after a model is inserted
+	AfterInsertHook interface{ AfterInsert() }
+	// BeforeUpdateHook is called before a model is replaced or updated
+	BeforeUpdateHook interface{ BeforeUpdate() }
+	// AfterUpdateHook is called after a model is replaced or updated
+	AfterUpdateHook interface{ AfterUpdate() }
+)
+
+// beforeInsert sets timestamps and runs the BeforeInsert hook of a model
+func beforeInsert(document interface{}) {
+	if model, ok := document.(interface{ SetTimestamps() }); ok {
+		model.SetTimestamps()
+	}
+	if hook, ok := document.(BeforeInsertHook); ok {
+		hook.BeforeInsert()
+	}
 }
 
 // SetTimestamps sets created_at and updated_at for the model
@@ -349,6 +1095,11 @@ func (m *Model) SetTimestamps() {
 	m.UpdatedAt = now
 }
 
+// Trashed reports whether the model has been soft deleted
+func (m *Model) Trashed() bool {
+	return m.DeletedAt != nil
+}
+
 // BeforeInsert hook called before inserting
 func (m *Model) BeforeInsert() {
 	now := time.Now()
@@ -376,6 +1127,24 @@ func (db *DB) Ping() error {
 	return db.Client.Ping(context.TODO(), nil)
 }
 
+// Transaction runs fn inside a multi-document transaction. The transaction is
+// committed when fn returns nil and aborted otherwise; transient errors are
+// retried by the driver. Pass sc to QueryBuilder.Context so builder calls
+// participate in the transaction. Transactions require a replica set or
+// sharded cluster; standalone servers return an error.
+func (db *DB) Transaction(ctx context.Context, fn func(sc mongo.SessionContext) error) error {
+	session, err := db.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
 // CreateIndex creates an index on the specified collection
 func (db *DB) CreateIndex(collection string, keys bson.M, options *options.IndexOptions) error {
 	coll := db.Database.Collection(collection)
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestTranslateErrorKeepsDriverError(t *testing.T) {
+	err := translateError(fmt.Errorf("find: %w", mongo.ErrNoDocuments))
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("errors.Is(%v, ErrNotFound) = false", err)
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("errors.Is(%v, mongo.ErrNoDocuments) = false", err)
+	}
+
+	other := errors.New("connection refused")
+	if got := translateError(other); got != other {
+		t.Errorf("translateError(%v) = %v, want it unchanged", other, got)
+	}
+	if translateError(nil) != nil {
+		t.Error("translateError(nil) != nil")
+	}
+}
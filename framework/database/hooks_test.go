@@ -0,0 +1,52 @@
+package database
+
+import "testing"
+
+type hookedPost struct {
+	Model `bson:",inline"`
+	calls []string
+}
+
+func (p *hookedPost) BeforeInsert() { p.calls = append(p.calls, "BeforeInsert") }
+func (p *hookedPost) AfterInsert()  { p.calls = append(p.calls, "AfterInsert") }
+func (p *hookedPost) BeforeUpdate() { p.calls = append(p.calls, "BeforeUpdate") }
+func (p *hookedPost) AfterUpdate()  { p.calls = append(p.calls, "AfterUpdate") }
+
+func TestBeforeInsertRunsHookAndTimestamps(t *testing.T) {
+	post := &hookedPost{}
+	beforeInsert(post)
+
+	if len(post.calls) != 1 || post.calls[0] != "BeforeInsert" {
+		t.Errorf("calls = %v, want [BeforeInsert]", post.calls)
+	}
+	if post.CreatedAt.IsZero() || post.UpdatedAt.IsZero() {
+		t.Error("timestamps not set")
+	}
+}
+
+func TestModelBindsUpdateHooks(t *testing.T) {
+	post := &hookedPost{}
+	qb := (&DB{}).NewQueryBuilder().Model(post)
+
+	if qb.collection != "hookedposts" {
+		t.Errorf("collection = %q, want %q", qb.collection, "hookedposts")
+	}
+	qb.beforeUpdate()
+	qb.afterUpdate()
+	if len(post.calls) != 2 || post.calls[0] != "BeforeUpdate" || post.calls[1] != "AfterUpdate" {
+		t.Errorf("calls = %v, want [BeforeUpdate AfterUpdate]", post.calls)
+	}
+}
+
+func TestModelKeepsExplicitCollection(t *testing.T) {
+	qb := (&DB{}).NewQueryBuilder().Collection("articles").Model(&hookedPost{})
+	if qb.collection != "articles" {
+		t.Errorf("collection = %q, want %q", qb.collection, "articles")
+	}
+}
+
+func TestUpdateHooksWithoutModel(t *testing.T) {
+	qb := (&DB{}).NewQueryBuilder()
+	qb.beforeUpdate()
+	qb.afterUpdate()
+}
@@ -0,0 +1,331 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrIndexConflict is returned by SyncIndexes when a declared unique index
+// has the same keys as an existing non-unique one
+var ErrIndexConflict = errors.New("conflicting index exists")
+
+// defaultIndexTimeout bounds SyncIndexes, which builds indexes in the foreground
+const defaultIndexTimeout = time.Minute
+
+// SyncIndexes creates the indexes declared with `index` struct tags on a model.
+// Indexes whose keys already exist on the collection are skipped, so calling
+// it on every start is safe. A unique index whose keys are already covered by
+// a non-unique index cannot be created and is reported as ErrIndexConflict.
+// The collection is taken from the model's CollectionName() method, falling
+// back to the lowercased type name with an "s" suffix. It gives up after a
+// minute; use SyncIndexesContext to choose the deadline.
+//
+// Supported tag values (comma-separated):
+//
+//	index:"asc"                 ascending index
+//	index:"desc"                descending index
+//	index:"unique"              unique ascending index
+//	index:"text"                part of the collection's text index
+//	index:"compound:name_email" part of the compound index "name_email"
+//	index:"compound:name_email,unique"
+//	index:"compound:name_email,2" second key of the compound index
+//
+// MongoDB allows one text index per collection, so all text fields form a
+// single text index.
+func (db *DB) SyncIndexes(model interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultIndexTimeout)
+	defer cancel()
+	return db.SyncIndexesContext(ctx, model)
+}
+
+// SyncIndexesContext is SyncIndexes bounded by ctx
+func (db *DB) SyncIndexesContext(ctx context.Context, model interface{}) error {
+	indexes, err := IndexModels(model)
+	if err != nil {
+		return err
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	name := CollectionNameOf(model)
+	coll := db.Database.Collection(name)
+	existing, err := existingIndexes(ctx, coll)
+	if err != nil {
+		return err
+	}
+
+	missing, conflicts := missingIndexes(indexes, existing, name)
+	if len(missing) > 0 {
+		if _, err := coll.Indexes().CreateMany(ctx, missing); err != nil {
+			return err
+		}
+	}
+	return conflicts
+}
+
+// missingIndexes returns the declared indexes not yet on the collection, and
+// an ErrIndexConflict for each unique index blocked by a non-unique one.
+// existing maps key signatures to whether the index is unique.
+func missingIndexes(indexes []mongo.IndexModel, existing map[string]bool, collection string) ([]mongo.IndexModel, error) {
+	var missing []mongo.IndexModel
+	var conflicts []error
+	for _, index := range indexes {
+		signature := indexKeySignature(index.Keys.(bson.D))
+		unique, exists := existing[signature]
+		if !exists {
+			missing = append(missing, index)
+			continue
+		}
+		if wantsUnique(index) && !unique {
+			conflicts = append(conflicts, fmt.Errorf("%w: '%s' has a non-unique index on %s; drop it to create the unique one", ErrIndexConflict, collection, signature))
+		}
+	}
+	return missing, errors.Join(conflicts...)
+}
+
+// wantsUnique reports whether an index model is declared unique
+func wantsUnique(index mongo.IndexModel) bool {
+	return index.Options != nil && index.Options.Unique != nil && *index.Options.Unique
+}
+
+// AutoMigrate syncs the struct-tag indexes of each model (see SyncIndexes)
+func (db *DB) AutoMigrate(models ...interface{}) error {
+	for _, model := range models {
+		if err := db.SyncIndexes(model); err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", CollectionNameOf(model), err)
+		}
+	}
+	return nil
+}
+
+// existingIndexes maps the key signatures of a collection's indexes to
+// whether they are unique
+func existingIndexes(ctx context.Context, coll *mongo.Collection) (map[string]bool, error) {
+	specs, err := coll.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		var doc bson.D
+		if err := bson.Unmarshal(spec.KeysDocument, &doc); err != nil {
+			return nil, err
+		}
+		keys[indexKeySignature(doc)] = spec.Unique != nil && *spec.Unique
+	}
+	return keys, nil
+}
+
+// indexKeySignature renders index keys in a comparable form, e.g. "email:1,name:-1".
+// Text indexes are stored by the server as _fts/_ftsx keys and all map to "text".
+func indexKeySignature(keys bson.D) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.Value == "text" || key.Key == "_fts" {
+			return "text"
+		}
+		parts = append(parts, fmt.Sprintf("%s:%v", key.Key, normalizeIndexOrder(key.Value)))
+	}
+	return strings.Join(parts, ",")
+}
+
+// normalizeIndexOrder converts numeric index directions to a common type
+func normalizeIndexOrder(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return value
+}
+
+// CollectionNameOf returns the collection name for a model
+func CollectionNameOf(model interface{}) string {
+	if named, ok := model.(interface{ CollectionName() string }); ok {
+		return named.CollectionName()
+	}
+
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return strings.ToLower(t.Name()) + "s"
+}
+
+// IndexModels builds the index models declared with `index` struct tags on a model
+func IndexModels(model interface{}) ([]mongo.IndexModel, error) {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("model must be a struct or pointer to struct")
+	}
+
+	var indexes []mongo.IndexModel
+	var textKeys bson.D
+	compounds := make(map[string]*mongo.IndexModel)
+	compoundKeys := make(map[string][]compoundKey)
+	var compoundOrder []string
+
+	err := walkIndexTags(t, func(key string, tag string) error {
+		var compound string
+		position := 0
+		order := interface{}(1)
+		unique := false
+
+		for _, part := range strings.Split(tag, ",") {
+			part = strings.TrimSpace(part)
+			switch {
+			case part == "asc":
+				order = 1
+			case part == "desc":
+				order = -1
+			case part == "text":
+				order = "text"
+			case part == "unique":
+				unique = true
+			case strings.HasPrefix(part, "compound:"):
+				compound = strings.TrimPrefix(part, "compound:")
+			case isDigits(part):
+				position, _ = strconv.Atoi(part)
+			case part == "":
+			default:
+				return fmt.Errorf("invalid index tag '%s' on field '%s'", part, key)
+			}
+		}
+
+		if compound == "" && order == "text" {
+			textKeys = append(textKeys, bson.E{Key: key, Value: "text"})
+			return nil
+		}
+		if compound == "" {
+			index := mongo.IndexModel{Keys: bson.D{{Key: key, Value: order}}}
+			if unique {
+				index.Options = options.Index().SetUnique(true)
+			}
+			indexes = append(indexes, index)
+			return nil
+		}
+
+		index, exists := compounds[compound]
+		if !exists {
+			index = &mongo.IndexModel{
+				Keys:    bson.D{},
+				Options: options.Index().SetName(compound),
+			}
+			compounds[compound] = index
+			compoundOrder = append(compoundOrder, compound)
+		}
+		compoundKeys[compound] = append(compoundKeys[compound], compoundKey{
+			key:      bson.E{Key: key, Value: order},
+			position: position,
+		})
+		if unique {
+			index.Options.SetUnique(true)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(textKeys) > 0 {
+		indexes = append(indexes, mongo.IndexModel{Keys: textKeys})
+	}
+	for _, name := range compoundOrder {
+		index := compounds[name]
+		index.Keys = sortCompoundKeys(compoundKeys[name])
+		indexes = append(indexes, *index)
+	}
+
+	return indexes, nil
+}
+
+// compoundKey is a key of a compound index with its optional explicit position
+type compoundKey struct {
+	key      bson.E
+	position int
+}
+
+// sortCompoundKeys orders compound index keys: keys with an explicit position
+// come first in position order, the rest follow in declaration order
+func sortCompoundKeys(keys []compoundKey) bson.D {
+	sort.SliceStable(keys, func(i, j int) bool {
+		pi, pj := keys[i].position, keys[j].position
+		if pi == 0 || pj == 0 {
+			return pi != 0 && pj == 0
+		}
+		return pi < pj
+	})
+
+	doc := make(bson.D, len(keys))
+	for i, k := range keys {
+		doc[i] = k.key
+	}
+	return doc
+}
+
+// isDigits checks if s is a non-empty string of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// walkIndexTags calls fn with the bson key and index tag of every tagged field,
+// descending into inline embedded structs
+func walkIndexTags(t reflect.Type, fn func(key, tag string) error) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		bsonTag := field.Tag.Get("bson")
+		name, flags, _ := strings.Cut(bsonTag, ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && strings.Contains(flags, "inline") {
+			if err := walkIndexTags(fieldType, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("index")
+		if !ok {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if err := fn(name, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type indexedPost struct {
+	Slug    string `bson:"slug" index:"unique"`
+	Title   string `bson:"title" index:"text"`
+	Body    string `bson:"body" index:"text"`
+	Author  string `bson:"author" index:"compound:author_date,1"`
+	Created string `bson:"created" index:"compound:author_date,2"`
+}
+
+func TestIndexModelsMergesTextFields(t *testing.T) {
+	indexes, err := IndexModels(&indexedPost{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var text []bson.D
+	for _, index := range indexes {
+		keys := index.Keys.(bson.D)
+		if keys[0].Value == "text" {
+			text = append(text, keys)
+		}
+	}
+	if len(text) != 1 {
+		t.Fatalf("got %d text indexes, want 1", len(text))
+	}
+	if got := indexKeySignature(text[0]); got != indexKeySignature(bson.D{{Key: "title", Value: "text"}, {Key: "body", Value: "text"}}) {
+		t.Errorf("text index keys = %v", text[0])
+	}
+}
+
+func TestIndexModelsCompoundOrderAndUnique(t *testing.T) {
+	indexes, err := IndexModels(&indexedPost{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indexes) != 3 {
+		t.Fatalf("got %d indexes, want 3", len(indexes))
+	}
+
+	var sawUnique, sawCompound bool
+	for _, index := range indexes {
+		keys := index.Keys.(bson.D)
+		switch keys[0].Key {
+		case "slug":
+			sawUnique = wantsUnique(index)
+		case "author":
+			sawCompound = len(keys) == 2 && keys[1].Key == "created"
+		}
+	}
+	if !sawUnique {
+		t.Error("slug index is not unique")
+	}
+	if !sawCompound {
+		t.Error("compound index keys not ordered author, created")
+	}
+}
+
+func TestMissingIndexesReportsUniqueConflict(t *testing.T) {
+	indexes, err := IndexModels(&indexedPost{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slug := indexKeySignature(bson.D{{Key: "slug", Value: 1}})
+
+	missing, err := missingIndexes(indexes, map[string]bool{slug: false}, "posts")
+	if !errors.Is(err, ErrIndexConflict) {
+		t.Fatalf("err = %v, want ErrIndexConflict", err)
+	}
+	if len(missing) != 2 || containsKey(missing, "slug") {
+		t.Errorf("missing = %v, want the text and compound indexes only", missing)
+	}
+
+	missing, err = missingIndexes(indexes, map[string]bool{slug: true}, "posts")
+	if err != nil || len(missing) != 2 {
+		t.Errorf("with unique index present: missing = %d, err = %v", len(missing), err)
+	}
+}
+
+func containsKey(indexes []mongo.IndexModel, key string) bool {
+	for _, index := range indexes {
+		if index.Keys.(bson.D)[0].Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func TestIndexModelsFromTags(t *testing.T) {
+	type account struct {
+		ID      string `bson:"_id"`
+		Email   string `bson:"email" index:"unique"`
+		Created string `bson:"created_at" index:"desc"`
+		Name    string `bson:"name" index:"asc,compound:name_email"`
+		Contact string `bson:"contact" index:"compound:name_email,unique"`
+		Ignored string `bson:"ignored"`
+	}
+
+	indexes, err := IndexModels(account{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []bson.D{
+		{{Key: "email", Value: 1}},
+		{{Key: "created_at", Value: -1}},
+		{{Key: "name", Value: 1}, {Key: "contact", Value: 1}},
+	}
+	if len(indexes) != len(want) {
+		t.Fatalf("got %d indexes, want %d", len(indexes), len(want))
+	}
+	for i, index := range indexes {
+		if got := index.Keys.(bson.D); indexKeySignature(got) != indexKeySignature(want[i]) {
+			t.Errorf("index %d keys = %v, want %v", i, got, want[i])
+		}
+	}
+	if !wantsUnique(indexes[0]) || wantsUnique(indexes[1]) || !wantsUnique(indexes[2]) {
+		t.Error("unique options not applied to the email and name_email indexes only")
+	}
+	if name := indexes[2].Options.Name; name == nil || *name != "name_email" {
+		t.Errorf("compound index name = %v, want name_email", name)
+	}
+
+	type invalid struct {
+		Field string `bson:"field" index:"sideways"`
+	}
+	if _, err := IndexModels(invalid{}); err == nil {
+		t.Error("unknown index tag did not fail")
+	}
+}
@@ -0,0 +1,201 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func newTestQuery() *QueryBuilder {
+	return (&DB{}).NewQueryBuilder().Collection("users")
+}
+
+func assertFilter(t *testing.T, qb *QueryBuilder, want bson.M) {
+	t.Helper()
+	if !reflect.DeepEqual(qb.filter, want) {
+		t.Errorf("filter = %v\nwant     %v", qb.filter, want)
+	}
+}
+
+func TestOrModeAppliesToEveryCondition(t *testing.T) {
+	day := time.Date(2026, 3, 14, 15, 0, 0, 0, time.UTC)
+	midnight := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	expr := bson.M{"$gt": bson.A{"$spent", "$budget"}}
+
+	tests := []struct {
+		name      string
+		condition func(*QueryBuilder)
+		want      bson.M
+	}{
+		{"WhereIn", func(qb *QueryBuilder) { qb.WhereIn("role", []interface{}{"admin"}) },
+			bson.M{"role": bson.M{"$in": []interface{}{"admin"}}}},
+		{"WhereNotIn", func(qb *QueryBuilder) { qb.WhereNotIn("role", []interface{}{"guest"}) },
+			bson.M{"role": bson.M{"$nin": []interface{}{"guest"}}}},
+		{"WhereBetween", func(qb *QueryBuilder) { qb.WhereBetween("age", 18, 30) },
+			bson.M{"age": bson.M{"$gte": 18, "$lte": 30}}},
+		{"WhereExists", func(qb *QueryBuilder) { qb.WhereExists("email") },
+			bson.M{"email": bson.M{"$exists": true}}},
+		{"WhereNotExists", func(qb *QueryBuilder) { qb.WhereNotExists("email") },
+			bson.M{"email": bson.M{"$exists": false}}},
+		{"WhereDate", func(qb *QueryBuilder) { qb.WhereDate("created_at", day) },
+			bson.M{"created_at": bson.M{"$gte": midnight, "$lt": midnight.AddDate(0, 0, 1)}}},
+		{"WhereDateBetween", func(qb *QueryBuilder) { qb.WhereDateBetween("created_at", day, day.AddDate(0, 0, 2)) },
+			bson.M{"created_at": bson.M{"$gte": midnight, "$lt": midnight.AddDate(0, 0, 3)}}},
+		{"WhereExpr", func(qb *QueryBuilder) { qb.WhereExpr(expr) },
+			bson.M{"$expr": expr}},
+		{"WhereFields", func(qb *QueryBuilder) { qb.WhereFields("spent", ">", "budget") },
+			bson.M{"$expr": expr}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qb := newTestQuery().Where("status", "=", "active").Or()
+			tt.condition(qb)
+			assertFilter(t, qb, bson.M{"$or": bson.A{bson.M{"status": "active"}, tt.want}})
+		})
+	}
+}
+
+func TestOrAndModesApplyLeftToRight(t *testing.T) {
+	qb := newTestQuery().
+		Where("status", "=", "active").
+		Or().WhereIn("role", []interface{}{"admin"}).
+		WhereExists("owner").
+		And().WhereBetween("age", 18, 30)
+
+	assertFilter(t, qb, bson.M{
+		"$or": bson.A{
+			bson.M{"status": "active"},
+			bson.M{"role": bson.M{"$in": []interface{}{"admin"}}},
+			bson.M{"owner": bson.M{"$exists": true}},
+		},
+		"age": bson.M{"$gte": 18, "$lte": 30},
+	})
+}
+
+func TestAndModeMergesRangesAndExpressions(t *testing.T) {
+	qb := newTestQuery().
+		Where("age", ">", 10).
+		WhereBetween("age", 18, 30).
+		WhereExpr(bson.M{"$gt": bson.A{"$a", "$b"}}).
+		WhereFields("c", "=", "d")
+
+	assertFilter(t, qb, bson.M{
+		"age": bson.M{"$gt": 10, "$gte": 18, "$lte": 30},
+		"$expr": bson.M{"$and": bson.A{
+			bson.M{"$gt": bson.A{"$a", "$b"}},
+			bson.M{"$eq": bson.A{"$c", "$d"}},
+		}},
+	})
+}
+
+func TestOrModeOnEmptyFilter(t *testing.T) {
+	qb := newTestQuery().Or().WhereIn("role", []interface{}{"admin"})
+	assertFilter(t, qb, bson.M{"role": bson.M{"$in": []interface{}{"admin"}}})
+}
+
+func TestWhereGroupInOrMode(t *testing.T) {
+	qb := newTestQuery().
+		Where("status", "=", "active").
+		Or().WhereGroup(func(g *QueryBuilder) {
+		g.Where("role", "=", "admin").WhereExists("team")
+	})
+
+	assertFilter(t, qb, bson.M{"$or": bson.A{
+		bson.M{"status": "active"},
+		bson.M{"role": "admin", "team": bson.M{"$exists": true}},
+	}})
+}
+
+func TestWhereDateBuildsDayRange(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	// 23:30 UTC on March 14 is already March 15 in Tokyo
+	moment := time.Date(2026, 3, 14, 23, 30, 0, 0, time.UTC)
+
+	assertFilter(t, newTestQuery().WhereDate("created_at", moment), bson.M{
+		"created_at": bson.M{
+			"$gte": time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC),
+			"$lt":  time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+		},
+	})
+
+	qb := newTestQuery().WhereDate("created_at", moment, tokyo)
+	cond := qb.filter["created_at"].(bson.M)
+	if start := cond["$gte"].(time.Time); !start.Equal(time.Date(2026, 3, 15, 0, 0, 0, 0, tokyo)) {
+		t.Errorf("Tokyo day starts at %v", start)
+	}
+	if end := cond["$lt"].(time.Time); !end.Equal(time.Date(2026, 3, 16, 0, 0, 0, 0, tokyo)) {
+		t.Errorf("Tokyo day ends at %v", end)
+	}
+}
+
+func TestWhereDateBetweenIncludesBothDays(t *testing.T) {
+	from := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 31, 6, 0, 0, 0, time.UTC)
+
+	assertFilter(t, newTestQuery().WhereDateBetween("created_at", from, to), bson.M{
+		"created_at": bson.M{
+			"$gte": time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			"$lt":  time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+	})
+}
+
+func TestWhereFieldsOperators(t *testing.T) {
+	tests := map[string]string{
+		"=": "$eq", "!=": "$ne", "<>": "$ne",
+		">": "$gt", ">=": "$gte", "<": "$lt", "<=": "$lte",
+	}
+	for operator, op := range tests {
+		assertFilter(t, newTestQuery().WhereFields("spent", operator, "budget"), bson.M{
+			"$expr": bson.M{op: bson.A{"$spent", "$budget"}},
+		})
+	}
+}
+
+func TestWhereExprCombinesWithValueConditions(t *testing.T) {
+	qb := newTestQuery().Where("status", "=", "active").WhereFields("spent", ">", "budget")
+	assertFilter(t, qb, bson.M{
+		"status": "active",
+		"$expr":  bson.M{"$gt": bson.A{"$spent", "$budget"}},
+	})
+}
+
+func TestOrWhereKeepsEarlierConditions(t *testing.T) {
+	qb := newTestQuery().
+		Where("status", "=", "active").
+		Where("team", "=", "core").
+		OrWhere("priority", ">", 5).
+		OrWhere("owner", "=", "ada")
+
+	assertFilter(t, qb, bson.M{"$or": bson.A{
+		bson.M{"status": "active", "team": "core"},
+		bson.M{"priority": bson.M{"$gt": 5}},
+		bson.M{"owner": "ada"},
+	}})
+
+	// A Where after an OrWhere is ANDed with the whole $or
+	qb.Where("archived", "=", false)
+	if qb.filter["archived"] != false || qb.filter["$or"] == nil {
+		t.Errorf("filter = %v, want archived ANDed with the $or", qb.filter)
+	}
+}
+
+func TestWhereGroupAndsSubFilter(t *testing.T) {
+	qb := newTestQuery().
+		Where("status", "=", "active").
+		WhereGroup(func(g *QueryBuilder) {
+			g.Where("priority", ">", 5).OrWhere("pinned", "=", true)
+		}).
+		WhereGroup(func(g *QueryBuilder) {})
+
+	assertFilter(t, qb, bson.M{
+		"status": "active",
+		"$and": bson.A{bson.M{"$or": bson.A{
+			bson.M{"priority": bson.M{"$gt": 5}},
+			bson.M{"pinned": true},
+		}}},
+	})
+}
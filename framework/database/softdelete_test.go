@@ -0,0 +1,54 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestScopedPipeline(t *testing.T) {
+	pipeline := []bson.M{{"$group": bson.M{"_id": "$role"}}}
+	tests := map[string]struct {
+		qb   *QueryBuilder
+		want []bson.M
+	}{
+		"no soft deletes": {
+			qb:   (&DB{}).NewQueryBuilder(),
+			want: pipeline,
+		},
+		"soft deletes": {
+			qb:   (&DB{}).NewQueryBuilder().SoftDeletes(),
+			want: append([]bson.M{{"$match": bson.M{"deleted_at": nil}}}, pipeline...),
+		},
+		"with trashed": {
+			qb:   (&DB{}).NewQueryBuilder().WithTrashed(),
+			want: pipeline,
+		},
+		"only trashed": {
+			qb:   (&DB{}).NewQueryBuilder().OnlyTrashed(),
+			want: append([]bson.M{{"$match": bson.M{"deleted_at": bson.M{"$ne": nil}}}}, pipeline...),
+		},
+	}
+	for name, tt := range tests {
+		if got := tt.qb.scopedPipeline(pipeline); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: pipeline = %v, want %v", name, got, tt.want)
+		}
+	}
+	if len(pipeline) != 1 {
+		t.Error("scopedPipeline modified the caller's pipeline")
+	}
+}
+
+func TestSoftDeleteFilter(t *testing.T) {
+	filter := (&DB{}).NewQueryBuilder().SoftDeletes().Where("role", "=", "admin").queryFilter()
+	want := bson.M{"role": "admin", "deleted_at": nil}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("filter = %v, want %v", filter, want)
+	}
+
+	filter = (&DB{}).NewQueryBuilder().OnlyTrashed().Where("deleted_at", ">", 1).queryFilter()
+	if _, ok := filter["$and"]; !ok {
+		t.Errorf("filter = %v, want the user's deleted_at condition and the scope combined with $and", filter)
+	}
+}
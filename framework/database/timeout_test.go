@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetDefaultTimeoutAppliesToNewBuilders(t *testing.T) {
+	db := &DB{}
+	db.SetDefaultTimeout(3 * time.Second)
+
+	if got := db.NewQueryBuilder().timeout; got != 3*time.Second {
+		t.Errorf("query builder timeout = %v, want 3s", got)
+	}
+	if got := db.NewAggregation().timeout; got != 3*time.Second {
+		t.Errorf("aggregate builder timeout = %v, want 3s", got)
+	}
+}
+
+func TestSetDefaultTimeoutConcurrentWithBuilders(t *testing.T) {
+	db := &DB{}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			db.SetDefaultTimeout(time.Duration(i) * time.Second)
+		}(i)
+		go func() {
+			defer wg.Done()
+			db.NewQueryBuilder()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestOperationContextUsesTimeout(t *testing.T) {
+	db := &DB{}
+	db.SetDefaultTimeout(time.Minute)
+
+	ctx, cancel := db.NewQueryBuilder().operationContext()
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Minute || time.Until(deadline) < 50*time.Second {
+		t.Errorf("default deadline = %v, %v; want about a minute from now", deadline, ok)
+	}
+
+	ctx, cancel = db.NewQueryBuilder().Timeout(time.Second).operationContext()
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Second {
+		t.Errorf("overridden deadline = %v, %v; want within a second", deadline, ok)
+	}
+
+	ctx, cancel = db.NewQueryBuilder().Timeout(0).operationContext()
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Timeout(0) still applied a deadline")
+	}
+}
+
+func TestOperationContextKeepsCallerContext(t *testing.T) {
+	type key struct{}
+	parent, cancelParent := context.WithCancel(context.WithValue(context.Background(), key{}, "request"))
+	db := &DB{}
+	db.SetDefaultTimeout(time.Minute)
+
+	ctx, cancel := db.NewQueryBuilder().Context(parent).operationContext()
+	defer cancel()
+	if ctx.Value(key{}) != "request" {
+		t.Error("operation context lost the caller's values")
+	}
+	cancelParent()
+	if ctx.Err() == nil {
+		t.Error("operation context outlived the caller's context")
+	}
+}
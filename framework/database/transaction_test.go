@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TestTransactionCommitsAndAborts needs a replica set; set
+// MONGODB_TEST_REPLICA_URI (e.g. mongodb://localhost:27017/?replicaSet=rs0)
+// to run it
+func TestTransactionCommitsAndAborts(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_REPLICA_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_REPLICA_URI not set")
+	}
+
+	db, err := Connect(uri, fmt.Sprintf("golara_test_%d", time.Now().UnixNano()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Disconnect()
+	defer db.Database.Drop(context.Background())
+
+	// Collections can't be created implicitly inside older transactions
+	for _, name := range []string{"orders", "payments"} {
+		if err := db.Database.CreateCollection(context.Background(), name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = db.Transaction(context.Background(), func(sc mongo.SessionContext) error {
+		if _, err := db.NewQueryBuilder().Collection("orders").Context(sc).Insert(bson.M{"ref": "a"}); err != nil {
+			return err
+		}
+		_, err := db.NewQueryBuilder().Collection("payments").Context(sc).Insert(bson.M{"ref": "a"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("committed transaction: %v", err)
+	}
+
+	err = db.Transaction(context.Background(), func(sc mongo.SessionContext) error {
+		if _, err := db.NewQueryBuilder().Collection("orders").Context(sc).Insert(bson.M{"ref": "b"}); err != nil {
+			return err
+		}
+		return errTestAbort
+	})
+	if !errors.Is(err, errTestAbort) {
+		t.Fatalf("aborted transaction returned %v", err)
+	}
+
+	for _, name := range []string{"orders", "payments"} {
+		count, err := db.NewQueryBuilder().Collection(name).Count()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Errorf("%s has %d documents, want 1", name, count)
+		}
+	}
+}
+
+var errTestAbort = errors.New("abort")
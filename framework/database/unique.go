@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/taeyelor/golara/framework/validation"
+)
+
+// RegisterValidationRules registers the database-backed "unique" rule on the
+// default validator (see UniqueRule)
+func (db *DB) RegisterValidationRules() {
+	validation.RegisterRule("unique", db.UniqueRule())
+}
+
+// UniqueRule returns a validation rule that fails when another document in a
+// collection already has the field's value. The parameter names the
+// collection, optionally followed by the bson keys of sibling fields that
+// must match as well, for uniqueness across several fields:
+//
+//	Email string `bson:"email" validate:"required,email,unique=users"`
+//	Email string `bson:"email" validate:"unique=users|tenant_id"` // unique per tenant
+//
+// When the struct has a non-zero _id, that document is excluded so updates
+// don't conflict with themselves. Back the rule with EnsureUniqueIndex to
+// enforce the constraint in the database too.
+func (db *DB) UniqueRule() validation.Rule {
+	return func(f validation.Field) error {
+		collection, filter, err := uniqueFilter(f)
+		if err != nil {
+			return err
+		}
+
+		qb := db.NewQueryBuilder().Collection(collection)
+		for key, value := range filter {
+			qb.Where(key, "=", value)
+		}
+		exists, err := qb.Exists()
+		if err != nil {
+			return fmt.Errorf("%s could not be checked for uniqueness", f.Name)
+		}
+		if exists {
+			return fmt.Errorf("%s has already been taken", f.Name)
+		}
+		return nil
+	}
+}
+
+// uniqueFilter builds the collection and filter checked by the unique rule
+func uniqueFilter(f validation.Field) (string, bson.M, error) {
+	parts := strings.Split(f.Param, "|")
+	collection := strings.TrimSpace(parts[0])
+	if collection == "" {
+		return "", nil, fmt.Errorf("%s has an invalid unique rule", f.Name)
+	}
+
+	key := bsonKey(f.Struct)
+	if key == "" {
+		key = f.Name
+	}
+	filter := bson.M{key: f.Value.Interface()}
+
+	for _, other := range parts[1:] {
+		other = strings.TrimSpace(other)
+		value, ok := fieldByBSONKey(f.Parent, other)
+		if !ok {
+			return "", nil, fmt.Errorf("%s has an invalid unique rule: unknown field '%s'", f.Name, other)
+		}
+		filter[other] = value.Interface()
+	}
+
+	if id, ok := fieldByBSONKey(f.Parent, "_id"); ok && !id.IsZero() {
+		filter["_id"] = bson.M{"$ne": id.Interface()}
+	}
+
+	return collection, filter, nil
+}
+
+// bsonKey returns the document key the driver uses for a struct field
+func bsonKey(sf reflect.StructField) string {
+	if sf.Name == "" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(sf.Tag.Get("bson"), ","); name != "" && name != "-" {
+		return name
+	}
+	return strings.ToLower(sf.Name)
+}
+
+// fieldByBSONKey finds a struct field by its document key, descending into
+// embedded structs
+func fieldByBSONKey(v reflect.Value, key string) (reflect.Value, bool) {
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if field, ok := fieldByBSONKey(v.Field(i), key); ok {
+				return field, true
+			}
+			continue
+		}
+		if bsonKey(sf) == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// EnsureUniqueIndex creates a unique ascending index over the given fields,
+// e.g. EnsureUniqueIndex("users", "tenant_id", "email"). It does nothing if
+// a unique index with the same keys already exists, and fails if a
+// non-unique one does.
+func (db *DB) EnsureUniqueIndex(collection string, fields ...string) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("EnsureUniqueIndex requires at least one field")
+	}
+
+	index := uniqueIndexModel(fields)
+	signature := indexKeySignature(index.Keys.(bson.D))
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultIndexTimeout)
+	defer cancel()
+
+	coll := db.Database.Collection(collection)
+	existing, err := existingIndexes(ctx, coll)
+	if err != nil {
+		return err
+	}
+	if unique, ok := existing[signature]; ok {
+		if unique {
+			return nil
+		}
+		return fmt.Errorf("%w: a non-unique index on %s already exists in '%s'", ErrIndexConflict, strings.Join(fields, ", "), collection)
+	}
+
+	_, err = coll.Indexes().CreateOne(ctx, index)
+	return err
+}
+
+// uniqueIndexModel builds the unique ascending index over fields
+func uniqueIndexModel(fields []string) mongo.IndexModel {
+	keys := make(bson.D, 0, len(fields))
+	for _, field := range fields {
+		keys = append(keys, bson.E{Key: field, Value: 1})
+	}
+	return mongo.IndexModel{Keys: keys, Options: options.Index().SetUnique(true)}
+}
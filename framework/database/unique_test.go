@@ -0,0 +1,68 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/taeyelor/golara/framework/validation"
+)
+
+type tenantUser struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID string             `bson:"tenant_id"`
+	Email    string             `bson:"email"`
+}
+
+// uniqueField returns the validation field for user.Email with a unique rule param
+func uniqueField(user *tenantUser, param string) validation.Field {
+	parent := reflect.ValueOf(user).Elem()
+	sf, _ := parent.Type().FieldByName("Email")
+	return validation.Field{
+		Name:   "email",
+		Value:  parent.FieldByName("Email"),
+		Param:  param,
+		Parent: parent,
+		Struct: sf,
+	}
+}
+
+func TestUniqueFilterCombinesFields(t *testing.T) {
+	user := &tenantUser{TenantID: "acme", Email: "ada@example.com"}
+
+	collection, filter, err := uniqueFilter(uniqueField(user, "users"))
+	if err != nil || collection != "users" || !reflect.DeepEqual(filter, bson.M{"email": "ada@example.com"}) {
+		t.Errorf("single field = %q, %v, %v", collection, filter, err)
+	}
+
+	_, filter, err = uniqueFilter(uniqueField(user, "users|tenant_id"))
+	want := bson.M{"email": "ada@example.com", "tenant_id": "acme"}
+	if err != nil || !reflect.DeepEqual(filter, want) {
+		t.Errorf("compound filter = %v, %v; want %v", filter, err, want)
+	}
+
+	user.ID = primitive.NewObjectID()
+	_, filter, _ = uniqueFilter(uniqueField(user, "users|tenant_id"))
+	if !reflect.DeepEqual(filter["_id"], bson.M{"$ne": user.ID}) {
+		t.Errorf("existing document not excluded: %v", filter)
+	}
+
+	for _, param := range []string{"", "users|team_id"} {
+		if _, _, err := uniqueFilter(uniqueField(user, param)); err == nil {
+			t.Errorf("unique=%q did not fail", param)
+		}
+	}
+}
+
+func TestUniqueIndexModel(t *testing.T) {
+	index := uniqueIndexModel([]string{"tenant_id", "email"})
+	want := bson.D{{Key: "tenant_id", Value: 1}, {Key: "email", Value: 1}}
+	if !reflect.DeepEqual(index.Keys, want) {
+		t.Errorf("keys = %v, want %v", index.Keys, want)
+	}
+	if !wantsUnique(index) {
+		t.Error("index is not unique")
+	}
+}
@@ -0,0 +1,122 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/taeyelor/golara/framework/routing"
+)
+
+// diagnosticsCheckTimeout bounds how long a single service health check may take
+const diagnosticsCheckTimeout = 2 * time.Second
+
+// sensitiveKeyParts marks config keys whose values are redacted in diagnostics
+var sensitiveKeyParts = []string{"key", "secret", "password", "token", "credential"}
+
+// IsDebug reports whether the application runs in debug mode.
+// Debug mode is never enabled when app.env is "production".
+func (app *Application) IsDebug() bool {
+	if app.Config.GetString("app.env") == "production" {
+		return false
+	}
+	return app.Config.GetBool("app.debug")
+}
+
+// ConfigDiagnostics returns a snapshot of what the application is doing:
+// the redacted effective configuration, registered routes, container services
+// and the health of the database and RabbitMQ services.
+func (app *Application) ConfigDiagnostics() map[string]interface{} {
+	routes := make([]map[string]string, 0)
+	for _, route := range app.Router.Routes() {
+		routes = append(routes, map[string]string{
+			"method":  route.Method,
+			"pattern": route.Pattern,
+		})
+	}
+
+	return map[string]interface{}{
+		"config":   redactConfig(app.Config.All()),
+		"routes":   routes,
+		"services": app.Container.Names(),
+		"health": map[string]interface{}{
+			"db":       app.serviceHealth("db"),
+			"rabbitmq": app.serviceHealth("rabbitmq"),
+		},
+	}
+}
+
+// EnableDiagnostics registers a GET endpoint serving ConfigDiagnostics.
+// The endpoint responds with 404 unless the application is in debug mode.
+func (app *Application) EnableDiagnostics(path string) {
+	if path == "" {
+		path = "/_debug/diagnostics"
+	}
+
+	app.GET(path, func(c *routing.Context) {
+		if !app.IsDebug() {
+			http.NotFound(c.Writer, c.Request)
+			return
+		}
+		c.JSON(http.StatusOK, app.ConfigDiagnostics())
+	})
+}
+
+// serviceHealth reports the health of a container service that exposes
+// a Health() or Ping() method
+func (app *Application) serviceHealth(name string) map[string]interface{} {
+	if !app.Container.Has(name) {
+		return map[string]interface{}{"status": "not registered"}
+	}
+
+	if err := runHealthCheck(context.Background(), app.ServiceHealthCheck(name), diagnosticsCheckTimeout); err != nil {
+		return map[string]interface{}{"status": "error", "error": err.Error()}
+	}
+	return map[string]interface{}{"status": "ok"}
+}
+
+// redactConfig masks secrets and URL credentials in a config map (in place)
+func redactConfig(data map[string]interface{}) map[string]interface{} {
+	for key, value := range data {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			data[key] = redactConfig(v)
+		case string:
+			if isSensitiveKey(key) && v != "" {
+				data[key] = "********"
+			} else {
+				data[key] = redactURL(v)
+			}
+		}
+	}
+	return data
+}
+
+// isSensitiveKey checks if a config key likely holds a secret
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL masks the password of a URL with embedded credentials
+func redactURL(value string) string {
+	if !strings.Contains(value, "://") {
+		return value
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		return u.Redacted()
+	}
+	return value
+}
@@ -0,0 +1,88 @@
+package framework
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/taeyelor/golara/framework/routing"
+)
+
+func TestRedactConfig(t *testing.T) {
+	config := redactConfig(map[string]interface{}{
+		"app": map[string]interface{}{
+			"name": "golara",
+			"key":  "base64:secret",
+		},
+		"database": map[string]interface{}{
+			"uri":      "mongodb://admin:hunter2@db:27017",
+			"password": "",
+		},
+		"rabbitmq": map[string]interface{}{"url": "amqp://guest@mq:5672/"},
+	})
+
+	app := config["app"].(map[string]interface{})
+	if app["key"] != "********" || app["name"] != "golara" {
+		t.Errorf("app = %v", app)
+	}
+	database := config["database"].(map[string]interface{})
+	if database["uri"] != "mongodb://admin:xxxxx@db:27017" {
+		t.Errorf("database.uri = %v", database["uri"])
+	}
+	if database["password"] != "" {
+		t.Errorf("empty password redacted to %v", database["password"])
+	}
+	if url := config["rabbitmq"].(map[string]interface{})["url"]; url != "amqp://guest@mq:5672/" {
+		t.Errorf("rabbitmq.url without a password = %v", url)
+	}
+}
+
+func TestDiagnosticsEndpoint(t *testing.T) {
+	t.Setenv("APP_KEY", "super-secret")
+	t.Setenv("APP_DEBUG", "true")
+	t.Setenv("APP_ENV", "local")
+	app := NewApplication()
+	app.GET("/users/{id}", func(c *routing.Context) {})
+	app.EnableDiagnostics("")
+
+	rec := httptest.NewRecorder()
+	app.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/_debug/diagnostics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var body struct {
+		Config map[string]map[string]interface{} `json:"config"`
+		Routes []map[string]string               `json:"routes"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if key := body.Config["app"]["key"]; key != "********" {
+		t.Errorf("app.key = %v, want it redacted", key)
+	}
+	found := false
+	for _, route := range body.Routes {
+		found = found || route["pattern"] == "/users/{id}"
+	}
+	if !found {
+		t.Errorf("routes = %v, want /users/{id}", body.Routes)
+	}
+}
+
+func TestDiagnosticsDisabledInProduction(t *testing.T) {
+	t.Setenv("APP_DEBUG", "true")
+	t.Setenv("APP_ENV", "production")
+	app := NewApplication()
+	app.EnableDiagnostics("/diag")
+
+	if app.IsDebug() {
+		t.Error("IsDebug is true in production")
+	}
+	rec := httptest.NewRecorder()
+	app.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/diag", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,154 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HealthCheck checks one dependency of the application, returning nil when
+// it is healthy
+type HealthCheck func(ctx context.Context) error
+
+// healthCheckTimeout bounds each check run by CheckHealth
+const healthCheckTimeout = 5 * time.Second
+
+// AddHealthCheck registers a named check run by CheckHealth and
+// HealthHandler. Registering a name again replaces its check.
+//
+//	app.AddHealthCheck("mongo", app.ServiceHealthCheck("db"))
+//	app.AddHealthCheck("payments-api", func(ctx context.Context) error {
+//	    return payments.Ping(ctx)
+//	})
+func (app *Application) AddHealthCheck(name string, check HealthCheck) {
+	app.healthMux.Lock()
+	defer app.healthMux.Unlock()
+
+	if app.healthChecks == nil {
+		app.healthChecks = make(map[string]HealthCheck)
+	}
+	app.healthChecks[name] = check
+}
+
+// ServiceHealthCheck returns a check for a container service exposing a
+// Health() or Ping() method. The service is resolved when the check runs, so
+// lazily registered services are not started by registering the check.
+func (app *Application) ServiceHealthCheck(name string) HealthCheck {
+	return func(ctx context.Context) error {
+		if !app.Container.Has(name) {
+			return fmt.Errorf("service '%s' is not registered", name)
+		}
+
+		switch service := app.Resolve(name).(type) {
+		case nil:
+			return fmt.Errorf("service '%s' is not available", name)
+		case interface{ Health() error }:
+			return service.Health()
+		case interface{ Ping() error }:
+			return service.Ping()
+		default:
+			return fmt.Errorf("service '%s' does not support health checks", name)
+		}
+	}
+}
+
+// CheckHealth runs every registered check concurrently, each bounded by ctx
+// and a 5 second timeout. It reports whether all checks passed along with a
+// report per check holding its status, duration and any error.
+func (app *Application) CheckHealth(ctx context.Context) (bool, map[string]interface{}) {
+	app.healthMux.Lock()
+	checks := make(map[string]HealthCheck, len(app.healthChecks))
+	for name, check := range app.healthChecks {
+		checks[name] = check
+	}
+	app.healthMux.Unlock()
+
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reports := make([]map[string]interface{}, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			errs[i] = runHealthCheck(ctx, checks[name], healthCheckTimeout)
+			reports[i] = map[string]interface{}{
+				"status":   "ok",
+				"duration": time.Since(start).String(),
+			}
+			if errs[i] != nil {
+				reports[i]["status"] = "error"
+				reports[i]["error"] = errs[i].Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	healthy := true
+	report := make(map[string]interface{}, len(names))
+	for i, name := range names {
+		report[name] = reports[i]
+		if errs[i] != nil {
+			healthy = false
+		}
+	}
+	return healthy, report
+}
+
+// HealthHandler serves the result of CheckHealth as JSON, with status 200
+// when every check passed and 503 otherwise, for use as a readiness probe:
+//
+//	app.GET("/health", app.HealthHandler())
+func (app *Application) HealthHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		healthy, checks := app.CheckHealth(r.Context())
+
+		status, code := "ok", http.StatusOK
+		if !healthy {
+			status, code = "error", http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": status,
+			"checks": checks,
+		})
+	}
+}
+
+// runHealthCheck runs a check with a timeout, also returning when a check
+// ignores its context and converting panics into errors
+func runHealthCheck(ctx context.Context, check HealthCheck, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("health check panicked: %v", r)
+			}
+		}()
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("health check timed out")
+	}
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// authRequest runs a request with the given headers through middleware and
+// returns the response and the principal next saw, if it was reached
+func authRequest(middleware func(http.Handler) http.Handler, headers map[string]string) (*httptest.ResponseRecorder, interface{}, bool) {
+	var principal interface{}
+	var reached bool
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		principal, _ = Principal(r)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec, principal, reached
+}
+
+// validToken accepts "good" as user 42 and "anonymous" without a principal
+func validToken(token string) (interface{}, bool) {
+	switch token {
+	case "good":
+		return map[string]int{"id": 42}, true
+	case "anonymous":
+		return nil, true
+	}
+	return nil, false
+}
+
+func TestBearerAuthRejects(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missing header":  nil,
+		"empty header":    {"Authorization": ""},
+		"basic scheme":    {"Authorization": "Basic Z29vZDpwYXNz"},
+		"scheme only":     {"Authorization": "Bearer"},
+		"blank token":     {"Authorization": "Bearer   "},
+		"token as scheme": {"Authorization": "good"},
+		"invalid token":   {"Authorization": "Bearer bad"},
+	}
+	for name, headers := range tests {
+		rec, _, reached := authRequest(BearerAuth(validToken), headers)
+		if reached {
+			t.Errorf("%s: request reached the handler", name)
+		}
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, rec.Code)
+		}
+		if got := rec.Header().Get("WWW-Authenticate"); got != `Bearer realm="api"` {
+			t.Errorf("%s: WWW-Authenticate = %q", name, got)
+		}
+	}
+}
+
+func TestBearerAuthAccepts(t *testing.T) {
+	for _, header := range []string{"Bearer good", "bearer good", "BEARER good", "Bearer  good "} {
+		rec, principal, reached := authRequest(BearerAuth(validToken), map[string]string{"Authorization": header})
+		if !reached || rec.Code != http.StatusOK {
+			t.Errorf("%q: status = %d, reached = %v", header, rec.Code, reached)
+			continue
+		}
+		if user, ok := principal.(map[string]int); !ok || user["id"] != 42 {
+			t.Errorf("%q: principal = %v, want user 42", header, principal)
+		}
+	}
+}
+
+func TestBearerAuthStoresTokenWithoutPrincipal(t *testing.T) {
+	_, principal, reached := authRequest(BearerAuth(validToken), map[string]string{"Authorization": "Bearer anonymous"})
+	if !reached || principal != "anonymous" {
+		t.Errorf("principal = %v, reached = %v; want the token", principal, reached)
+	}
+}
+
+func TestAPIKeyAuthRejects(t *testing.T) {
+	middleware := APIKeyAuth("X-API-Key", []string{"key-1", "key-2"})
+	tests := map[string]map[string]string{
+		"missing header": nil,
+		"wrong key":      {"X-API-Key": "key-3"},
+		"key prefix":     {"X-API-Key": "key-"},
+		"other header":   {"Authorization": "key-1"},
+	}
+	for name, headers := range tests {
+		rec, _, reached := authRequest(middleware, headers)
+		if reached {
+			t.Errorf("%s: request reached the handler", name)
+		}
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, rec.Code)
+		}
+		if got := rec.Header().Get("WWW-Authenticate"); got != `APIKey realm="api", header="X-API-Key"` {
+			t.Errorf("%s: WWW-Authenticate = %q", name, got)
+		}
+	}
+}
+
+func TestAPIKeyAuthAccepts(t *testing.T) {
+	middleware := APIKeyAuth("X-API-Key", []string{"key-1", "key-2"})
+	rec, principal, reached := authRequest(middleware, map[string]string{"X-API-Key": "key-2"})
+	if !reached || rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, reached = %v", rec.Code, reached)
+	}
+	if principal != "key-2" {
+		t.Errorf("principal = %v, want the matching key", principal)
+	}
+}
+
+func TestAPIKeyAuthWithoutKeysRejectsAll(t *testing.T) {
+	rec, _, reached := authRequest(APIKeyAuth("X-API-Key", nil), map[string]string{"X-API-Key": ""})
+	if reached || rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, reached = %v; want 401", rec.Code, reached)
+	}
+}
+
+func TestPrincipalWithoutAuth(t *testing.T) {
+	if principal, ok := Principal(httptest.NewRequest(http.MethodGet, "/", nil)); ok || principal != nil {
+		t.Errorf("Principal = %v, %v; want none", principal, ok)
+	}
+}
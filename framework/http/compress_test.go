@@ -0,0 +1,142 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var largeBody = strings.Repeat("golara compresses text bodies. ", 100)
+
+func serveCompressed(t *testing.T, handler http.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Compress(gzip.DefaultCompression)(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func gzipRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	return req
+}
+
+func TestCompressGzipsLargeBodies(t *testing.T) {
+	rec := serveCompressed(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, largeBody)
+	}, gzipRequest())
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != largeBody {
+		t.Error("decompressed body differs from the original")
+	}
+}
+
+func TestCompressSkipsSmallBodies(t *testing.T) {
+	rec := serveCompressed(t, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "short")
+	}, gzipRequest())
+
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none", got)
+	}
+	if rec.Body.String() != "short" {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+}
+
+func TestCompressSkipsRangeResponses(t *testing.T) {
+	content := strings.NewReader(largeBody)
+	req := gzipRequest()
+	req.Header.Set("Range", "bytes=0-1499")
+
+	rec := serveCompressed(t, func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "page.txt", time.Time{}, content)
+	}, req)
+
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusPartialContent)
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q on a 206, want none", got)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), []byte(largeBody[:1500])) {
+		t.Error("partial body was altered")
+	}
+}
+
+func TestCompressSkipsContentRange(t *testing.T) {
+	rec := serveCompressed(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Range", "bytes 0-3099/3100")
+		io.WriteString(w, largeBody)
+	}, gzipRequest())
+
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q with Content-Range, want none", got)
+	}
+}
+
+func TestCompressWeakensStrongETag(t *testing.T) {
+	tests := map[string]string{
+		`"v1"`:   `W/"v1"`,
+		`W/"v1"`: `W/"v1"`,
+	}
+	for etag, want := range tests {
+		rec := serveCompressed(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("ETag", etag)
+			io.WriteString(w, largeBody)
+		}, gzipRequest())
+
+		if got := rec.Header().Get("ETag"); got != want {
+			t.Errorf("ETag %s = %s, want %s", etag, got, want)
+		}
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := map[string]bool{
+		"":                    false,
+		"gzip":                true,
+		"br, gzip;q=0.5":      true,
+		"gzip;q=0":            false,
+		"gzip;q=0.0":          false,
+		"gzip; q=0.000":       false,
+		"*":                   true,
+		"*;q=0":               false,
+		"gzip;q=0, *":         false,
+		"*, gzip;q=0":         false,
+		"identity":            false,
+		"deflate, *;q=0.1":    true,
+		"gzip;q=bogus":        false,
+		"GZIP":                true,
+		"x-gzip":              true,
+		"br;q=1.0, gzip;q=.8": true,
+	}
+	for header, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set("Accept-Encoding", header)
+		}
+		if got := acceptsGzip(req); got != want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSConfig configures cross-origin resource sharing
+type CORSConfig struct {
+	// AllowOrigins lists the origins allowed to make requests; "*" allows any
+	AllowOrigins []string
+
+	// AllowMethods defaults to GET, POST, PUT, PATCH, DELETE and OPTIONS
+	AllowMethods []string
+
+	// AllowHeaders defaults to Content-Type and Authorization
+	AllowHeaders []string
+
+	// ExposeHeaders lists response headers scripts may read
+	ExposeHeaders []string
+
+	// AllowCredentials lets browsers send cookies and authorization headers.
+	// It cannot be combined with the "*" origin.
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache preflight results
+	MaxAge time.Duration
+}
+
+// CORS handles cross-origin requests. Preflight requests are answered with
+// 204 No Content without reaching the handler. It panics if AllowCredentials
+// is combined with the "*" origin, which the CORS specification forbids.
+func CORS(config CORSConfig) func(http.Handler) http.Handler {
+	anyOrigin := slices.Contains(config.AllowOrigins, "*")
+	if anyOrigin && config.AllowCredentials {
+		panic("CORS: AllowCredentials cannot be used with the \"*\" origin; list the allowed origins instead")
+	}
+
+	if len(config.AllowMethods) == 0 {
+		config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	}
+	if len(config.AllowHeaders) == 0 {
+		config.AllowHeaders = []string{"Content-Type", "Authorization"}
+	}
+	allowMethods := strings.Join(config.AllowMethods, ", ")
+	allowHeaders := strings.Join(config.AllowHeaders, ", ")
+	exposeHeaders := strings.Join(config.ExposeHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			header := w.Header()
+			header.Add("Vary", "Origin")
+
+			if origin == "" || !(anyOrigin || slices.Contains(config.AllowOrigins, origin)) {
+				// Not a cross-origin request, or one we don't allow: send no
+				// CORS headers and let the browser block it
+				if preflight {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if anyOrigin {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+			if config.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if exposeHeaders != "" {
+					header.Set("Access-Control-Expose-Headers", exposeHeaders)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header.Set("Access-Control-Allow-Methods", allowMethods)
+			header.Set("Access-Control-Allow-Headers", allowHeaders)
+			if config.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(config.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
+
+// RequestIDHeader carries the request ID logged by StructuredLogging
+const RequestIDHeader = "X-Request-ID"
+
+// NewLogger creates a slog logger writing to stdout, as JSON when format is
+// "json" and as key=value text otherwise
+func NewLogger(format string) *slog.Logger {
+	if format == "json" {
+		return slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	}
+	return slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
+
+// StructuredLogging logs each request through logger with method, path,
+// status, duration, bytes and request_id fields. The request ID is taken from
+// the X-Request-ID header, or generated, and is set on both the request and
+// the response. A nil logger uses slog.Default().
+func StructuredLogging(logger *slog.Logger) func(http.Handler) http.Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(RequestIDHeader)
+			if requestID == "" {
+				requestID = newRequestID()
+				r.Header.Set(RequestIDHeader, requestID)
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: 200}
+			next.ServeHTTP(wrapped, r)
+
+			level := slog.LevelInfo
+			if wrapped.statusCode >= 500 {
+				level = slog.LevelError
+			}
+			logger.LogAttrs(r.Context(), level, "request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", wrapped.statusCode),
+				slog.Duration("duration", time.Since(start)),
+				slog.Int("bytes", wrapped.bytes),
+				slog.String("request_id", requestID),
+				slog.String("remote_addr", r.RemoteAddr),
+			)
+		})
+	}
+}
+
+// newRequestID returns a random 16 byte hex ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token-bucket rate limiter keyed by client
+type RateLimiter struct {
+	// KeyFunc identifies the client of a request; it defaults to ClientIP.
+	// Set it to ForwardedClientIP when running behind a reverse proxy.
+	KeyFunc func(r *http.Request) string
+
+	rate      float64 // tokens added per second
+	burst     float64
+	buckets   map[string]*bucket
+	mutex     sync.Mutex
+	lastSweep time.Time
+	idleTTL   time.Duration
+}
+
+// bucket holds the token state for a single client
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a limiter that allows limit requests per window per client
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	if limit <= 0 {
+		limit = 1
+	}
+	if window <= 0 {
+		window = time.Second
+	}
+
+	return newRateLimiter(float64(limit)/window.Seconds(), float64(limit), window)
+}
+
+// NewTokenBucket creates a limiter that allows each client rps requests per
+// second on average, with bursts of up to burst requests
+func NewTokenBucket(rps, burst int) *RateLimiter {
+	if rps <= 0 {
+		rps = 1
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	// A bucket idle for this long has refilled and can be dropped
+	idleTTL := time.Duration(float64(burst) / float64(rps) * float64(time.Second))
+	if idleTTL < time.Second {
+		idleTTL = time.Second
+	}
+	return newRateLimiter(float64(rps), float64(burst), idleTTL)
+}
+
+func newRateLimiter(rate, burst float64, idleTTL time.Duration) *RateLimiter {
+	return &RateLimiter{
+		KeyFunc:   ClientIP,
+		rate:      rate,
+		burst:     burst,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		idleTTL:   idleTTL,
+	}
+}
+
+// Allow reports whether a request for the given key is allowed.
+// When it isn't, the returned duration is how long until a token is available.
+func (rl *RateLimiter) Allow(key string) (bool, time.Duration) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	now := time.Now()
+	rl.sweep(now)
+
+	b, exists := rl.buckets[key]
+	if !exists {
+		b = &bucket{tokens: rl.burst, lastSeen: now}
+		rl.buckets[key] = b
+	} else {
+		elapsed := now.Sub(b.lastSeen).Seconds()
+		b.tokens = math.Min(rl.burst, b.tokens+elapsed*rl.rate)
+		b.lastSeen = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / rl.rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep evicts buckets that have been idle long enough to be full again,
+// so memory doesn't grow with the number of distinct clients. It runs at
+// most once per idle period, from Allow, so an idle limiter costs nothing.
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rl.idleTTL {
+		return
+	}
+	rl.lastSweep = now
+
+	for key, b := range rl.buckets {
+		if now.Sub(b.lastSeen) > rl.idleTTL {
+			delete(rl.buckets, key)
+		}
+	}
+}
+
+// Middleware returns HTTP middleware that rejects requests over the limit with 429
+func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, wait := rl.Allow(rl.KeyFunc(r))
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RateLimitPer limits each client IP to limit requests per window
+func RateLimitPer(limit int, window time.Duration) func(http.Handler) http.Handler {
+	return NewRateLimiter(limit, window).Middleware
+}
+
+// RateLimit limits each client IP to rps requests per second with bursts of
+// up to burst requests. Requests over the limit receive 429 Too Many
+// Requests with a Retry-After header. Behind a reverse proxy, use
+// NewTokenBucket with KeyFunc set to ForwardedClientIP instead.
+func RateLimit(rps, burst int) func(http.Handler) http.Handler {
+	return NewTokenBucket(rps, burst).Middleware
+}
+
+// ClientIP returns the remote IP of the request without the port
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ForwardedClientIP returns the client IP reported by a reverse proxy in
+// X-Forwarded-For, falling back to ClientIP. The last entry is used, being
+// the one appended by the proxy in front of the application; earlier
+// entries are supplied by the client and can be forged.
+func ForwardedClientIP(r *http.Request) string {
+	forwarded := r.Header.Values("X-Forwarded-For")
+	if len(forwarded) == 0 {
+		return ClientIP(r)
+	}
+
+	entries := strings.Split(forwarded[len(forwarded)-1], ",")
+	if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+		return ip
+	}
+	return ClientIP(r)
+}
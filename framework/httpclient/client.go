@@ -0,0 +1,209 @@
+// Package httpclient provides an HTTP client for service-to-service calls
+// with timeouts, retries and JSON helpers
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Config holds client configuration
+type Config struct {
+	Timeout     time.Duration // per-attempt timeout
+	MaxRetries  int           // retries after the first attempt
+	Backoff     time.Duration // initial delay between retries, doubled each retry
+	BearerToken string        // sent as "Authorization: Bearer <token>" when set
+	Headers     map[string]string
+
+	// RetryNonIdempotent also retries POST, PATCH and other non-idempotent
+	// requests. By default they are only retried when they carry an
+	// Idempotency-Key header.
+	RetryNonIdempotent bool
+}
+
+// DefaultConfig returns the default client configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Timeout:    10 * time.Second,
+		MaxRetries: 2,
+		Backoff:    200 * time.Millisecond,
+	}
+}
+
+// Client is an HTTP client that retries network errors and 5xx responses of
+// idempotent requests
+type Client struct {
+	http   *http.Client
+	config Config
+}
+
+// StatusError is returned by the JSON helpers for non-2xx responses
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, bytes.TrimSpace(e.Body))
+}
+
+// New creates a new client
+func New(config *Config) *Client {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Client{
+		http:   &http.Client{Timeout: config.Timeout},
+		config: *config,
+	}
+}
+
+// WithBearerToken returns a copy of the client that sends the given bearer token
+func (c *Client) WithBearerToken(token string) *Client {
+	clone := *c
+	clone.config.BearerToken = token
+	return &clone
+}
+
+// Do sends a request, retrying network errors and 5xx responses with
+// exponential backoff. Only idempotent requests (GET, HEAD, OPTIONS, TRACE,
+// PUT and DELETE, or any request with an Idempotency-Key header) are retried
+// unless Config.RetryNonIdempotent is set. Requests with a body are only
+// retried when the body can be replayed (req.GetBody is set, as it is for
+// requests built with http.NewRequest from a bytes or strings reader).
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	c.applyHeaders(req)
+
+	retryable := canReplay(req) && (c.config.RetryNonIdempotent || isIdempotent(req))
+	delay := c.config.Backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.http.Do(req)
+		if !retryable || !shouldRetry(resp, err) || attempt >= c.config.MaxRetries {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// GetJSON sends a GET request and decodes the JSON response into out
+func (c *Client) GetJSON(ctx context.Context, url string, out interface{}) error {
+	return c.DoJSON(ctx, http.MethodGet, url, nil, out)
+}
+
+// PostJSON sends body as JSON and decodes the JSON response into out
+func (c *Client) PostJSON(ctx context.Context, url string, body, out interface{}) error {
+	return c.DoJSON(ctx, http.MethodPost, url, body, out)
+}
+
+// PutJSON sends body as JSON with PUT and decodes the JSON response into out
+func (c *Client) PutJSON(ctx context.Context, url string, body, out interface{}) error {
+	return c.DoJSON(ctx, http.MethodPut, url, body, out)
+}
+
+// DeleteJSON sends a DELETE request and decodes the JSON response into out
+func (c *Client) DeleteJSON(ctx context.Context, url string, out interface{}) error {
+	return c.DoJSON(ctx, http.MethodDelete, url, nil, out)
+}
+
+// DoJSON sends a request with an optional JSON body and decodes the JSON
+// response into out (skipped when out is nil). Non-2xx responses are
+// returned as *StatusError.
+func (c *Client) DoJSON(ctx context.Context, method, url string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &StatusError{StatusCode: resp.StatusCode, Body: data}
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return nil
+}
+
+// applyHeaders adds the configured headers and bearer token
+func (c *Client) applyHeaders(req *http.Request) {
+	for key, value := range c.config.Headers {
+		if req.Header.Get(key) == "" {
+			req.Header.Set(key, value)
+		}
+	}
+	if c.config.BearerToken != "" && req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.BearerToken)
+	}
+}
+
+// shouldRetry reports whether a response or error is worth retrying
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500
+}
+
+// isIdempotent reports whether sending the request twice has the same effect
+// as sending it once
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// canReplay reports whether the request body can be sent again
+func canReplay(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
@@ -0,0 +1,134 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with 503 and echoes the
+// request body afterwards
+func flakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if len(body) == 0 {
+			body = []byte(`{}`)
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func testClient(config Config) *Client {
+	config.Timeout = time.Second
+	config.Backoff = time.Millisecond
+	return New(&config)
+}
+
+func TestRetriesIdempotentRequests(t *testing.T) {
+	server, calls := flakyServer(t, 2)
+	client := testClient(Config{MaxRetries: 2})
+
+	var out map[string]string
+	if err := client.PutJSON(context.Background(), server.URL, map[string]string{"name": "ada"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server called %d times, want 3", got)
+	}
+	if out["name"] != "ada" {
+		t.Errorf("response = %v; body was not replayed on retry", out)
+	}
+}
+
+func TestDoesNotRetryPostByDefault(t *testing.T) {
+	server, calls := flakyServer(t, 1)
+	client := testClient(Config{MaxRetries: 3})
+
+	err := client.PostJSON(context.Background(), server.URL, map[string]string{"name": "ada"}, nil)
+
+	var status *StatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want a 503 StatusError", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server called %d times, want 1", got)
+	}
+}
+
+func TestRetriesPostWhenOptedIn(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		header string
+	}{
+		{"RetryNonIdempotent", Config{MaxRetries: 1, RetryNonIdempotent: true}, ""},
+		{"Idempotency-Key", Config{MaxRetries: 1}, "order-42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := flakyServer(t, 1)
+			client := testClient(tt.config)
+
+			req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"id": 1}`))
+			if tt.header != "" {
+				req.Header.Set("Idempotency-Key", tt.header)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+				t.Errorf("status %d after %d calls, want 200 after 2", resp.StatusCode, calls.Load())
+			}
+		})
+	}
+}
+
+func TestStopsAfterMaxRetries(t *testing.T) {
+	server, calls := flakyServer(t, 10)
+	client := testClient(Config{MaxRetries: 2})
+
+	err := client.GetJSON(context.Background(), server.URL, nil)
+
+	var status *StatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want a 503 StatusError", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server called %d times, want 3", got)
+	}
+}
+
+func TestAppliesHeadersAndBearerToken(t *testing.T) {
+	var auth, custom string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, custom = r.Header.Get("Authorization"), r.Header.Get("X-Service")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := testClient(Config{Headers: map[string]string{"X-Service": "billing"}}).WithBearerToken("secret")
+	if err := client.DeleteJSON(context.Background(), server.URL, nil); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Bearer secret" || custom != "billing" {
+		t.Errorf("Authorization = %q, X-Service = %q", auth, custom)
+	}
+}
@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the request duration histogram bounds in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// httpKey identifies the requests counted together
+type httpKey struct {
+	method string
+	route  string
+	status int
+}
+
+// httpSeries holds the counters of one method, route and status
+type httpSeries struct {
+	counts []uint64
+	sum    float64
+}
+
+// HTTPCollector records HTTP requests for http_requests_total and
+// http_request_duration_seconds
+type HTTPCollector struct {
+	buckets []float64
+	series  map[httpKey]*httpSeries
+	mutex   sync.Mutex
+}
+
+// NewHTTPCollector creates a collector using the given histogram bounds, or
+// DefaultBuckets when none are given. Register it and wrap handlers with its
+// Middleware.
+func NewHTTPCollector(buckets ...float64) *HTTPCollector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+
+	return &HTTPCollector{
+		buckets: buckets,
+		series:  make(map[httpKey]*httpSeries),
+	}
+}
+
+var (
+	defaultHTTP     *HTTPCollector
+	defaultHTTPOnce sync.Once
+)
+
+// Middleware records requests in a collector registered with the Default
+// registry. Requests are labelled with the matched route pattern rather than
+// the raw path, keeping the number of series bounded.
+func Middleware() func(http.Handler) http.Handler {
+	defaultHTTPOnce.Do(func() {
+		defaultHTTP = NewHTTPCollector()
+		Register(defaultHTTP)
+	})
+	return defaultHTTP.Middleware()
+}
+
+// Middleware records the requests passing through it
+func (c *HTTPCollector) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			route := r.Pattern
+			if route == "" {
+				route = "unmatched"
+			}
+			c.Observe(r.Method, route, recorder.status, time.Since(start))
+		})
+	}
+}
+
+// Observe records one request
+func (c *HTTPCollector) Observe(method, route string, status int, duration time.Duration) {
+	seconds := duration.Seconds()
+	bucket := sort.SearchFloat64s(c.buckets, seconds)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := httpKey{method: method, route: route, status: status}
+	series, exists := c.series[key]
+	if !exists {
+		series = &httpSeries{counts: make([]uint64, len(c.buckets)+1)}
+		c.series[key] = series
+	}
+	series.counts[bucket]++
+	series.sum += seconds
+}
+
+// Collect writes the request counters
+func (c *HTTPCollector) Collect(w *Writer) {
+	c.mutex.Lock()
+	keys := make([]httpKey, 0, len(c.series))
+	snapshot := make(map[httpKey]httpSeries, len(c.series))
+	for key, series := range c.series {
+		keys = append(keys, key)
+		snapshot[key] = httpSeries{counts: append([]uint64{}, series.counts...), sum: series.sum}
+	}
+	c.mutex.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	for _, key := range keys {
+		var total uint64
+		for _, count := range snapshot[key].counts {
+			total += count
+		}
+		w.Counter("http_requests_total", "Total number of HTTP requests.", float64(total), key.labels())
+	}
+	for _, key := range keys {
+		series := snapshot[key]
+		w.Histogram("http_request_duration_seconds", "HTTP request duration in seconds.", c.buckets, series.counts, series.sum, key.labels())
+	}
+}
+
+func (k httpKey) labels() Labels {
+	return Labels{"method": k.method, "route": k.route, "status": strconv.Itoa(k.status)}
+}
+
+// statusRecorder captures the response status code
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush supports streaming responses such as server-sent events
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
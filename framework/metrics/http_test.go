@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/taeyelor/golara/framework/routing"
+)
+
+func scrape(t *testing.T, registry *Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestHTTPCollectorLabelsRoutes(t *testing.T) {
+	collector := NewHTTPCollector()
+	registry := NewRegistry()
+	registry.Register(collector)
+
+	r := routing.NewRouter()
+	r.Use(collector.Middleware())
+	r.GET("/users/{id}", func(c *routing.Context) { c.String(http.StatusOK, "ok") })
+
+	for _, target := range []string{"/users/1", "/users/2", "/nope"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	out := scrape(t, registry)
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/users/{id}",status="200"} 2`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %s\n%s", want, out)
+		}
+	}
+}
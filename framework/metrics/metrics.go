@@ -0,0 +1,204 @@
+// Package metrics exposes application metrics in the Prometheus text format
+// without depending on the Prometheus client library.
+//
+//	app.Use(metrics.Middleware())
+//	metrics.Register(metrics.RabbitMQCollector(rabbit))
+//	app.GET("/metrics", metrics.Handler())
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Collector writes a set of metrics when they are scraped
+type Collector interface {
+	Collect(w *Writer)
+}
+
+// CollectorFunc adapts a function to a Collector
+type CollectorFunc func(w *Writer)
+
+// Collect calls f(w)
+func (f CollectorFunc) Collect(w *Writer) {
+	f(w)
+}
+
+// Labels are the label names and values of a sample
+type Labels map[string]string
+
+// Registry holds the collectors served by its Handler
+type Registry struct {
+	collectors []Collector
+	mutex      sync.RWMutex
+}
+
+// Default is the registry used by the package-level functions. It includes
+// Go runtime metrics.
+var Default = NewRegistry()
+
+func init() {
+	Default.Register(CollectorFunc(collectRuntime))
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a collector to the registry
+func (r *Registry) Register(collector Collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.collectors = append(r.collectors, collector)
+}
+
+// Handler serves the registry's metrics in the Prometheus text format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mutex.RLock()
+		collectors := append([]Collector{}, r.collectors...)
+		r.mutex.RUnlock()
+
+		writer := &Writer{families: make(map[string]bool)}
+		for _, collector := range collectors {
+			collector.Collect(writer)
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write(writer.buf.Bytes())
+	})
+}
+
+// Register adds a collector to the Default registry
+func Register(collector Collector) {
+	Default.Register(collector)
+}
+
+// Handler serves the Default registry's metrics
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// Writer encodes samples in the Prometheus text format. The samples of a
+// metric must be written together, since the format requires each metric
+// family to be contiguous.
+type Writer struct {
+	buf      bytes.Buffer
+	families map[string]bool
+}
+
+// Counter writes a sample of a monotonically increasing value
+func (w *Writer) Counter(name, help string, value float64, labels Labels) {
+	w.header(name, help, "counter")
+	w.sample(name, labels, value)
+}
+
+// Gauge writes a sample of a value that can go up and down
+func (w *Writer) Gauge(name, help string, value float64, labels Labels) {
+	w.header(name, help, "gauge")
+	w.sample(name, labels, value)
+}
+
+// Histogram writes a histogram. counts holds the number of observations in
+// each bucket, not cumulative, with one more entry than bounds for the
+// observations above the last bound.
+func (w *Writer) Histogram(name, help string, bounds []float64, counts []uint64, sum float64, labels Labels) {
+	w.header(name, help, "histogram")
+
+	var cumulative uint64
+	for i, bound := range bounds {
+		cumulative += counts[i]
+		w.sample(name+"_bucket", withLabel(labels, "le", formatFloat(bound)), float64(cumulative))
+	}
+	cumulative += counts[len(bounds)]
+	w.sample(name+"_bucket", withLabel(labels, "le", "+Inf"), float64(cumulative))
+	w.sample(name+"_sum", labels, sum)
+	w.sample(name+"_count", labels, float64(cumulative))
+}
+
+// header writes the HELP and TYPE lines the first time a metric is written
+func (w *Writer) header(name, help, kind string) {
+	if w.families[name] {
+		return
+	}
+	w.families[name] = true
+	fmt.Fprintf(&w.buf, "# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, kind)
+}
+
+// sample writes one sample line with sorted labels
+func (w *Writer) sample(name string, labels Labels, value float64) {
+	w.buf.WriteString(name)
+	if len(labels) > 0 {
+		keys := make([]string, 0, len(labels))
+		for key := range labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		w.buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			fmt.Fprintf(&w.buf, "%s=\"%s\"", key, escapeLabel(labels[key]))
+		}
+		w.buf.WriteByte('}')
+	}
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(formatFloat(value))
+	w.buf.WriteByte('\n')
+}
+
+// withLabel returns a copy of labels with one more label
+func withLabel(labels Labels, name, value string) Labels {
+	result := make(Labels, len(labels)+1)
+	for key, v := range labels {
+		result[key] = v
+	}
+	result[name] = value
+	return result
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+// collectRuntime writes Go runtime metrics
+func collectRuntime(w *Writer) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	w.Gauge("go_goroutines", "Number of goroutines that currently exist.", float64(runtime.NumGoroutine()), nil)
+	w.Gauge("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", float64(stats.Alloc), nil)
+	w.Gauge("go_memstats_sys_bytes", "Number of bytes obtained from system.", float64(stats.Sys), nil)
+	w.Counter("go_gc_cycles_total", "Number of completed GC cycles.", float64(stats.NumGC), nil)
+}
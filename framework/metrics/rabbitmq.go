@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"github.com/taeyelor/golara/framework/rabbitmq"
+)
+
+const consumerMessagesHelp = "Messages handled by RabbitMQ consumers, by result."
+
+// RabbitMQCollector reports the connection state and consumer metrics of a
+// RabbitMQ service. Consumers are labelled with their name (see
+// ConsumerConfig.Name) and queue; the connection with its name.
+func RabbitMQCollector(rabbit *rabbitmq.RabbitMQ) Collector {
+	return CollectorFunc(func(w *Writer) {
+		manager := rabbit.Manager()
+		if manager == nil {
+			w.Gauge("rabbitmq_connected", "Whether the RabbitMQ connection is up.", 0, nil)
+			return
+		}
+
+		connected := 0.0
+		if manager.IsConnected() {
+			connected = 1
+		}
+		w.Gauge("rabbitmq_connected", "Whether the RabbitMQ connection is up.", connected, Labels{"connection": manager.Connection().Name()})
+
+		consumers := manager.Consumers()
+		snapshots := make([]rabbitmq.ConsumerMetrics, len(consumers))
+		for i, consumer := range consumers {
+			snapshots[i] = consumer.Metrics()
+		}
+
+		for _, m := range snapshots {
+			labels := consumerLabels(m)
+			w.Counter("rabbitmq_consumer_messages_total", consumerMessagesHelp, float64(m.Processed), withLabel(labels, "result", "processed"))
+			w.Counter("rabbitmq_consumer_messages_total", consumerMessagesHelp, float64(m.Failed), withLabel(labels, "result", "failed"))
+			w.Counter("rabbitmq_consumer_messages_total", consumerMessagesHelp, float64(m.Retried), withLabel(labels, "result", "retried"))
+		}
+		for _, m := range snapshots {
+			w.Gauge("rabbitmq_consumer_messages_in_flight", "Messages being handled by RabbitMQ consumers.", float64(m.InFlight), consumerLabels(m))
+		}
+		for _, m := range snapshots {
+			w.Counter("rabbitmq_consumer_processing_seconds_total", "Time spent handling messages in RabbitMQ consumers.", m.TotalDuration.Seconds(), consumerLabels(m))
+		}
+	})
+}
+
+func consumerLabels(m rabbitmq.ConsumerMetrics) Labels {
+	return Labels{"consumer": m.Name, "queue": m.Queue}
+}
@@ -0,0 +1,99 @@
+package framework
+
+import (
+	"compress/gzip"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+
+	httpMW "github.com/taeyelor/golara/framework/http"
+)
+
+// registerConfiguredMiddleware registers the built-in middleware enabled in
+// configuration. Everything is off by default, so applications that wire
+// their middleware by hand are unaffected. Middleware is registered in this
+// order, outermost first:
+//
+//	http.logging.enabled   LoggingMiddleware, or StructuredLogging when
+//	                       http.logging.format is "json" or "text"
+//	http.recovery.enabled  RecoveryMiddleware
+//	http.gzip.enabled      Compress at http.gzip.level (default -1, gzip.DefaultCompression)
+//	http.cors.enabled      CORS with http.cors.origins (default "*"),
+//	                       http.cors.credentials and http.cors.max_age;
+//	                       credentials require explicit origins
+//
+// The CORS settings are re-read when the configuration is reloaded.
+func (app *Application) registerConfiguredMiddleware() {
+	if app.Config.GetBool("http.logging.enabled") {
+		switch format := app.Config.GetString("http.logging.format"); format {
+		case "json", "text":
+			app.Use(httpMW.StructuredLogging(httpMW.NewLogger(format)))
+		default:
+			app.Use(httpMW.LoggingMiddleware)
+		}
+	}
+
+	if app.Config.GetBool("http.recovery.enabled") {
+		app.Use(httpMW.RecoveryMiddleware)
+	}
+
+	if app.Config.GetBool("http.gzip.enabled") {
+		app.Use(httpMW.Compress(app.Config.GetInt("http.gzip.level", gzip.DefaultCompression)))
+	}
+
+	// CORS is always registered so a configuration reload can turn it on,
+	// off or change its settings; while disabled it passes requests through
+	app.applyCORSConfig()
+	app.Use(app.corsMiddleware)
+}
+
+// corsMiddleware runs the CORS middleware built from the current
+// configuration, or passes the request through when CORS is disabled
+func (app *Application) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cors := app.cors.Load()
+		if cors == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		(*cors)(next).ServeHTTP(w, r)
+	})
+}
+
+// applyCORSConfig rebuilds the CORS middleware from http.cors.*. An invalid
+// configuration disables CORS.
+func (app *Application) applyCORSConfig() {
+	if !app.Config.GetBool("http.cors.enabled") {
+		app.cors.Store(nil)
+		return
+	}
+
+	config, err := app.corsConfig()
+	if err != nil {
+		log.Printf("CORS disabled: %v", err)
+		app.cors.Store(nil)
+		return
+	}
+	cors := httpMW.CORS(config)
+	app.cors.Store(&cors)
+}
+
+// corsConfig builds the CORS configuration from http.cors.*. Credentials
+// require explicit origins, since browsers reject them with "*".
+func (app *Application) corsConfig() (httpMW.CORSConfig, error) {
+	config := httpMW.CORSConfig{
+		AllowOrigins:     app.Config.GetStringSlice("http.cors.origins"),
+		AllowCredentials: app.Config.GetBool("http.cors.credentials"),
+		MaxAge:           app.Config.GetDuration("http.cors.max_age"),
+	}
+
+	if config.AllowCredentials {
+		if len(config.AllowOrigins) == 0 || slices.Contains(config.AllowOrigins, "*") {
+			return config, errors.New("http.cors.credentials requires explicit http.cors.origins")
+		}
+	} else if len(config.AllowOrigins) == 0 {
+		config.AllowOrigins = []string{"*"}
+	}
+	return config, nil
+}
@@ -0,0 +1,63 @@
+package framework
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func preflight(app *Application, path, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	app.Router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConfiguredCORSPreflight(t *testing.T) {
+	t.Setenv("HTTP_CORS_ENABLED", "true")
+	t.Setenv("HTTP_CORS_ORIGINS", "https://app.example.com")
+	t.Setenv("HTTP_CORS_CREDENTIALS", "true")
+
+	app := NewApplication()
+	app.POST("/orders", func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := preflight(app, "/orders", "https://app.example.com")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Access-Control-Allow-Credentials = %q", got)
+	}
+}
+
+func TestConfiguredCORSCredentialsWithoutOrigins(t *testing.T) {
+	t.Setenv("HTTP_CORS_ENABLED", "true")
+	t.Setenv("HTTP_CORS_CREDENTIALS", "true")
+
+	app := NewApplication() // must not panic
+	if _, err := app.corsConfig(); err == nil {
+		t.Fatal("corsConfig accepted credentials without origins")
+	}
+
+	rec := preflight(app, "/", "https://app.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("CORS enabled despite invalid config: Access-Control-Allow-Origin = %q", got)
+	}
+}
+
+func TestConfiguredCORSDefaultsToAnyOrigin(t *testing.T) {
+	t.Setenv("HTTP_CORS_ENABLED", "true")
+
+	app := NewApplication()
+	app.GET("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := preflight(app, "/", "https://other.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+}
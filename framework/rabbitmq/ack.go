@@ -0,0 +1,48 @@
+package rabbitmq
+
+// Ack acknowledges the delivery, or with multiple every unacknowledged
+// delivery up to it on the same channel
+func (d *Delivery) Ack(multiple bool) error {
+	d.settled.Store(true)
+	defer d.Close()
+	return d.Delivery.Ack(multiple)
+}
+
+// Nack negatively acknowledges the delivery, or with multiple every
+// unacknowledged delivery up to it. With requeue the broker delivers the
+// message again; otherwise it is dropped or dead-lettered.
+func (d *Delivery) Nack(multiple, requeue bool) error {
+	d.settled.Store(true)
+	d.requeued.Store(requeue)
+	defer d.Close()
+	return d.Delivery.Nack(multiple, requeue)
+}
+
+// Reject rejects the delivery, requeueing it when requeue is true. Rejected
+// messages that are not requeued go to the queue's dead-letter exchange.
+func (d *Delivery) Reject(requeue bool) error {
+	d.settled.Store(true)
+	d.requeued.Store(requeue)
+	defer d.Close()
+	return d.Delivery.Reject(requeue)
+}
+
+// Close releases the channel a delivery returned by Queue.Pop was fetched
+// on. Ack, Nack and Reject call it, so it is only needed to abandon an
+// unsettled delivery, which the broker then requeues. It is a no-op for
+// consumed deliveries, whose channel belongs to the consumer.
+func (d *Delivery) Close() error {
+	var err error
+	d.releaseOnce.Do(func() {
+		if d.release != nil {
+			err = d.release()
+		}
+	})
+	return err
+}
+
+// Settled reports whether the delivery has been acked, nacked or rejected
+// through the Delivery
+func (d *Delivery) Settled() bool {
+	return d.settled.Load()
+}
@@ -0,0 +1,200 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// BatchHandler processes a batch of deliveries at once
+type BatchHandler func([]*Delivery) error
+
+// StartBatch consumes messages in batches: deliveries are accumulated until
+// batchSize messages have arrived or maxWait has passed since the first one,
+// then handler is called once with the whole batch. The batch is acknowledged
+// when the handler succeeds and requeued when it fails (all-or-nothing).
+// With ConsumerConfig.MaxAttempts set, messages of a failed batch that have
+// used up their attempts are rejected without requeue, so they are
+// dead-lettered instead of failing every batch they land in.
+//
+// Batch consumption always uses manual acknowledgement and a prefetch of at
+// least batchSize; handlers and middleware registered on the consumer are not
+// used. Messages of an unfinished batch are requeued by the broker when the
+// consumer stops.
+func (c *Consumer) StartBatch(ctx context.Context, batchSize int, maxWait time.Duration, handler BatchHandler) error {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	if maxWait <= 0 {
+		maxWait = time.Second
+	}
+
+	if err := c.begin(); err != nil {
+		return err
+	}
+	defer c.isRunning.Store(false)
+
+	ctx, cancel := c.runContext(ctx)
+	defer cancel()
+	defer c.watchReconnect(ctx)()
+
+	log.Printf("%s: Starting batch consumer for queue '%s' (batch size %d, max wait %v)", c.logPrefix(), c.queue, batchSize, maxWait)
+
+	// Keep one channel, opening a new one only after the broker closed it
+	channelName := c.channelName("batch")
+	defer c.conn.CloseChannel(channelName)
+
+	for {
+		ch, err := c.conn.GetChannel(channelName)
+		if err != nil {
+			err = fmt.Errorf("failed to get channel: %w", err)
+		} else {
+			err = c.processBatches(ctx, ch, batchSize, maxWait, handler)
+		}
+		if err == nil {
+			log.Printf("%s: Batch consumer stopped", c.logPrefix())
+			return nil
+		}
+
+		log.Printf("%s: Batch consumer error: %v", c.logPrefix(), err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.stopCh:
+			return nil
+		case <-c.wake:
+		case <-time.After(workerRetryDelay):
+		}
+	}
+}
+
+// processBatches consumes from a single channel and flushes batches to the handler.
+// It returns nil when the consumer is stopped.
+func (c *Consumer) processBatches(ctx context.Context, ch *amqp.Channel, batchSize int, maxWait time.Duration, handler BatchHandler) error {
+	prefetch := c.prefetchCount
+	if prefetch < batchSize {
+		prefetch = batchSize
+	}
+	if err := ch.Qos(prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	deliveries, err := ch.Consume(
+		c.queue,       // queue
+		c.consumerTag, // consumer
+		false,         // auto-ack
+		c.exclusive,   // exclusive
+		false,         // no-local
+		c.noWait,      // no-wait
+		c.args,        // args
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start consuming: %w", err)
+	}
+
+	return c.collectBatches(ctx, deliveries, batchSize, maxWait, handler)
+}
+
+// collectBatches accumulates deliveries and flushes a batch to the handler
+// when it holds batchSize messages or maxWait has passed since its first
+// message. When the deliveries channel closes, the partial batch is flushed
+// before returning; if the channel itself closed, acknowledging it fails and
+// the broker redelivers the messages.
+func (c *Consumer) collectBatches(ctx context.Context, deliveries <-chan amqp.Delivery, batchSize int, maxWait time.Duration, handler BatchHandler) error {
+	batch := make([]*Delivery, 0, batchSize)
+	timer := time.NewTimer(maxWait)
+	timer.Stop()
+	defer timer.Stop()
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		timer.Stop()
+		c.handleBatch(batch, handler)
+		batch = make([]*Delivery, 0, batchSize)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.stopCh:
+			return nil
+		case <-timer.C:
+			flush()
+		case delivery, ok := <-deliveries:
+			if !ok {
+				flush()
+				return fmt.Errorf("delivery channel closed")
+			}
+
+			batch = append(batch, &Delivery{Delivery: &delivery, ctx: ctx, conn: c.conn, queue: c.queue})
+			if len(batch) == 1 {
+				timer.Reset(maxWait)
+			}
+			if len(batch) >= batchSize {
+				flush()
+			}
+		}
+	}
+}
+
+// handleBatch runs the handler and acknowledges or requeues the whole batch.
+// All deliveries come from the same channel in order, so a single multiple
+// ack/nack on the last delivery covers the batch.
+func (c *Consumer) handleBatch(batch []*Delivery, handler BatchHandler) {
+	last := batch[len(batch)-1]
+
+	start := c.beginMessages(len(batch))
+	err := c.runBatchHandler(batch, handler)
+	c.finishMessages(len(batch), start, err, false)
+
+	if err != nil {
+		log.Printf("%s: Error processing batch of %d messages: %v", c.logPrefix(), len(batch), err)
+		c.requeueBatch(batch)
+		return
+	}
+
+	if err := last.Ack(true); err != nil {
+		log.Printf("%s: Failed to acknowledge batch: %v", c.logPrefix(), err)
+	}
+}
+
+// requeueBatch requeues a failed batch. Messages that reached MaxAttempts
+// are rejected without requeue instead, which needs one nack per message.
+func (c *Consumer) requeueBatch(batch []*Delivery) {
+	if c.maxAttempts <= 0 {
+		if err := batch[len(batch)-1].Nack(true, true); err != nil {
+			log.Printf("%s: Failed to requeue batch: %v", c.logPrefix(), err)
+		}
+		return
+	}
+
+	for _, delivery := range batch {
+		var err error
+		if delivery.Attempts() < c.maxAttempts {
+			err = c.requeue(delivery)
+		} else {
+			log.Printf("%s: Message failed %d attempts, rejecting without requeue", c.logPrefix(), delivery.Attempts())
+			err = delivery.Nack(false, false)
+		}
+		if err != nil {
+			log.Printf("%s: Failed to settle message of failed batch: %v", c.logPrefix(), err)
+		}
+	}
+}
+
+// runBatchHandler calls the handler, converting panics into errors
+func (c *Consumer) runBatchHandler(batch []*Delivery, handler BatchHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("%s: Recovered from panic in batch handler: %v", c.logPrefix(), r)
+			err = ErrPanicRecovered
+		}
+	}()
+	return handler(batch)
+}
@@ -0,0 +1,183 @@
+package rabbitmq
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func testBatch(ack *fakeAcknowledger, consumer *Consumer, headers ...amqp.Table) []*Delivery {
+	batch := make([]*Delivery, len(headers))
+	for i, h := range headers {
+		msg := testDelivery(ack, uint64(i+1), "events", h)
+		batch[i] = &Delivery{Delivery: &msg, ctx: context.Background(), conn: consumer.conn, queue: consumer.queue}
+	}
+	return batch
+}
+
+func TestHandleBatchAcksWholeBatch(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "events"})
+	ack := &fakeAcknowledger{}
+
+	consumer.handleBatch(testBatch(ack, consumer, nil, nil, nil), func(batch []*Delivery) error {
+		for _, d := range batch {
+			if d.conn != consumer.conn || d.queue != "events" {
+				t.Error("batch delivery does not record its source queue")
+			}
+		}
+		return nil
+	})
+
+	want := []ackRecord{{method: "ack", tag: 3, multiple: true}}
+	if got := ack.all(); !reflect.DeepEqual(got, want) {
+		t.Errorf("settlements = %+v, want %+v", got, want)
+	}
+	if m := consumer.Metrics(); m.Processed != 3 {
+		t.Errorf("Processed = %d, want 3", m.Processed)
+	}
+}
+
+func TestHandleBatchRequeuesFailedBatch(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "events"})
+	ack := &fakeAcknowledger{}
+
+	consumer.handleBatch(testBatch(ack, consumer, nil, nil), func([]*Delivery) error { return errTest })
+
+	want := []ackRecord{{method: "nack", tag: 2, multiple: true, requeue: true}}
+	if got := ack.all(); !reflect.DeepEqual(got, want) {
+		t.Errorf("settlements = %+v, want %+v", got, want)
+	}
+}
+
+func TestHandleBatchDeadLettersExhaustedMessages(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "events", MaxAttempts: 3})
+	ack := &fakeAcknowledger{}
+
+	batch := testBatch(ack, consumer,
+		nil,
+		amqp.Table{"x-delivery-count": int64(2)}, // third attempt
+		amqp.Table{"x-delivery-count": int64(1)},
+	)
+	consumer.handleBatch(batch, func([]*Delivery) error { panic("poison message") })
+
+	want := []ackRecord{
+		{method: "nack", tag: 1, requeue: true},
+		{method: "nack", tag: 2, requeue: false},
+		{method: "nack", tag: 3, requeue: true},
+	}
+	if got := ack.all(); !reflect.DeepEqual(got, want) {
+		t.Errorf("settlements = %+v, want %+v", got, want)
+	}
+	if m := consumer.Metrics(); m.Failed != 3 {
+		t.Errorf("Failed = %d, want 3", m.Failed)
+	}
+}
+
+// feedDeliveries returns a channel holding n deliveries acknowledged through
+// ack, closed unless keepOpen is set
+func feedDeliveries(ack *fakeAcknowledger, n int, keepOpen bool) chan amqp.Delivery {
+	deliveries := make(chan amqp.Delivery, n)
+	for i := 0; i < n; i++ {
+		deliveries <- testDelivery(ack, uint64(i+1), "events", nil)
+	}
+	if !keepOpen {
+		close(deliveries)
+	}
+	return deliveries
+}
+
+func TestCollectBatchesFlushesFullBatches(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "events"})
+	ack := &fakeAcknowledger{}
+
+	var sizes []int
+	err := consumer.collectBatches(context.Background(), feedDeliveries(ack, 7, false), 3, time.Hour, func(batch []*Delivery) error {
+		sizes = append(sizes, len(batch))
+		return nil
+	})
+
+	// The last, partial batch is flushed when the deliveries channel closes
+	if err == nil {
+		t.Error("collectBatches returned nil after the deliveries channel closed")
+	}
+	if want := []int{3, 3, 1}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("batch sizes = %v, want %v", sizes, want)
+	}
+	want := []ackRecord{
+		{method: "ack", tag: 3, multiple: true},
+		{method: "ack", tag: 6, multiple: true},
+		{method: "ack", tag: 7, multiple: true},
+	}
+	if got := ack.all(); !reflect.DeepEqual(got, want) {
+		t.Errorf("settlements = %+v, want %+v", got, want)
+	}
+}
+
+func TestCollectBatchesFlushesPartialBatchAfterMaxWait(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "events"})
+	ack := &fakeAcknowledger{}
+	deliveries := feedDeliveries(ack, 2, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	flushed := make(chan int, 1)
+	start := time.Now()
+	go consumer.collectBatches(ctx, deliveries, 10, 50*time.Millisecond, func(batch []*Delivery) error {
+		flushed <- len(batch)
+		return nil
+	})
+
+	select {
+	case size := <-flushed:
+		if size != 2 {
+			t.Errorf("flushed a batch of %d, want 2", size)
+		}
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("partial batch flushed after %v, before the 50ms max wait", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("partial batch was not flushed after the max wait")
+	}
+
+	// The timer restarts with the next batch's first message
+	deliveries <- testDelivery(ack, 3, "events", nil)
+	select {
+	case size := <-flushed:
+		if size != 1 {
+			t.Errorf("flushed a batch of %d, want 1", size)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second partial batch was not flushed")
+	}
+}
+
+func TestCollectBatchesStopsWithoutFlushing(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "events"})
+	ack := &fakeAcknowledger{}
+	deliveries := feedDeliveries(ack, 2, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	var calls int
+	go func() {
+		done <- consumer.collectBatches(ctx, deliveries, 10, time.Hour, func([]*Delivery) error {
+			calls++
+			return nil
+		})
+	}()
+	for len(deliveries) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	// Messages of an unfinished batch are left for the broker to requeue
+	if err := <-done; err != nil {
+		t.Errorf("collectBatches = %v, want nil when stopped", err)
+	}
+	if calls != 0 || len(ack.all()) != 0 {
+		t.Errorf("stopping flushed the unfinished batch (%d calls, %+v)", calls, ack.all())
+	}
+}
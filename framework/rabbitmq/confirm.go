@@ -0,0 +1,62 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// defaultConfirmTimeout is how long Publish waits for a publisher confirm
+// when PublisherConfig.ConfirmTimeout is not set
+const defaultConfirmTimeout = 5 * time.Second
+
+// publishConfirmed publishes a message on a channel in confirm mode and
+// waits for the broker's ack. The broker sends basic.return before the ack of
+// a returned message, so once the ack arrives any return is already queued.
+func (p *Publisher) publishConfirmed(ch *amqp.Channel, message *Message) error {
+	publishing, err := buildPublishing(message)
+	if err != nil {
+		return err
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	var returns chan amqp.Return
+	if p.mandatory {
+		returns = ch.NotifyReturn(make(chan amqp.Return, 1))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.confirmTimeout)
+	defer cancel()
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(
+		ctx,
+		p.exchange,         // exchange
+		message.RoutingKey, // routing key
+		p.mandatory,        // mandatory
+		false,              // immediate
+		publishing,         // message
+	)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("%w after %v (exchange '%s', routing key '%s')", ErrConfirmTimeout, p.confirmTimeout, p.exchange, message.RoutingKey)
+	}
+	if !acked {
+		return fmt.Errorf("%w (exchange '%s', routing key '%s')", ErrPublishNacked, p.exchange, message.RoutingKey)
+	}
+
+	select {
+	case ret := <-returns:
+		return fmt.Errorf("%w: %d %s (exchange '%s', routing key '%s')", ErrMessageReturned, ret.ReplyCode, ret.ReplyText, ret.Exchange, ret.RoutingKey)
+	default:
+		return nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -16,16 +17,26 @@ type Connection struct {
 	conn         *amqp.Connection
 	channels     map[string]*amqp.Channel
 	channelsMux  sync.RWMutex
+	channelSeq   atomic.Uint64
 	reconnectMux sync.Mutex
-	isConnected  bool
+	isConnected  atomic.Bool
 	done         chan bool
+	closeOnce    sync.Once
 	notifyClose  chan *amqp.Error
 	notifyReady  chan bool
 	config       *Config
+
+	listenersMux       sync.Mutex
+	reconnectListeners []chan bool
+	listenersClosed    bool
 }
 
 // Config holds RabbitMQ connection configuration
 type Config struct {
+	// Name identifies the connection in logs, Stats() and the RabbitMQ
+	// management UI (as the connection_name client property)
+	Name string
+
 	URL                 string
 	ReconnectDelay      time.Duration
 	ReconnectAttempts   int
@@ -72,8 +83,9 @@ func NewConnection(url string, config *Config) (*Connection, error) {
 	// Wait for initial connection
 	select {
 	case <-conn.notifyReady:
-		log.Println("RabbitMQ: Initial connection established")
+		log.Printf("%s: Initial connection established", conn.logPrefix())
 	case <-time.After(10 * time.Second):
+		conn.Close()
 		return nil, fmt.Errorf("failed to establish initial RabbitMQ connection within 10 seconds")
 	}
 
@@ -88,15 +100,22 @@ func (c *Connection) connect() error {
 		Heartbeat: c.config.HeartbeatInterval,
 		Locale:    "en_US",
 	}
+	if c.config.Name != "" {
+		config.Properties = amqp.NewConnectionProperties()
+		config.Properties.SetClientConnectionName(c.config.Name)
+	}
 
-	c.conn, err = amqp.DialConfig(c.config.URL, config)
+	conn, err := amqp.DialConfig(c.config.URL, config)
 	if err != nil {
 		return err
 	}
+	c.notifyClose = conn.NotifyClose(make(chan *amqp.Error, 1))
 
-	c.isConnected = true
-	c.notifyClose = make(chan *amqp.Error)
-	c.conn.NotifyClose(c.notifyClose)
+	// GetChannel reads conn under channelsMux
+	c.channelsMux.Lock()
+	c.conn = conn
+	c.isConnected.Store(true)
+	c.channelsMux.Unlock()
 
 	// Signal that connection is ready
 	select {
@@ -104,30 +123,60 @@ func (c *Connection) connect() error {
 	default:
 	}
 
-	log.Println("RabbitMQ: Connected successfully")
+	log.Printf("%s: Connected successfully", c.logPrefix())
 	return nil
 }
 
+// Name returns the connection name, if any
+func (c *Connection) Name() string {
+	return c.config.Name
+}
+
+// logPrefix returns the log prefix, including the connection name when set
+func (c *Connection) logPrefix() string {
+	if c.config.Name != "" {
+		return fmt.Sprintf("RabbitMQ[%s]", c.config.Name)
+	}
+	return "RabbitMQ"
+}
+
 // handleReconnect handles automatic reconnection
 func (c *Connection) handleReconnect() {
+	reconnecting := false
 	for {
 		c.reconnectMux.Lock()
 		err := c.connect()
 		c.reconnectMux.Unlock()
 
 		if err != nil {
-			log.Printf("RabbitMQ: Failed to connect: %v. Retrying in %v", err, c.config.ReconnectDelay)
-			time.Sleep(c.config.ReconnectDelay)
+			log.Printf("%s: Failed to connect: %v. Retrying in %v", c.logPrefix(), err, c.config.ReconnectDelay)
+			select {
+			case <-c.done:
+				return
+			case <-time.After(c.config.ReconnectDelay):
+			}
 			continue
 		}
+		if c.isClosed() {
+			// Close ran while connecting and may have missed this connection
+			c.conn.Close()
+			return
+		}
+		if reconnecting {
+			c.notifyReconnected()
+		}
+		reconnecting = true
 
 		// Wait for connection to close
 		select {
 		case <-c.done:
 			return
 		case <-c.notifyClose:
-			log.Println("RabbitMQ: Connection lost. Attempting to reconnect...")
-			c.isConnected = false
+			if c.isClosed() {
+				return
+			}
+			log.Printf("%s: Connection lost. Attempting to reconnect...", c.logPrefix())
+			c.isConnected.Store(false)
 			c.closeChannels()
 		}
 	}
@@ -163,7 +212,7 @@ func (c *Connection) GetChannel(name string) (*amqp.Channel, error) {
 		return ch, nil
 	}
 
-	if !c.isConnected {
+	if !c.isConnected.Load() || c.conn == nil {
 		return nil, fmt.Errorf("RabbitMQ connection is not available")
 	}
 
@@ -173,12 +222,31 @@ func (c *Connection) GetChannel(name string) (*amqp.Channel, error) {
 	}
 
 	c.channels[name] = ch
+	go c.watchChannel(name, ch)
 	return ch, nil
 }
 
-// NewChannel creates a new channel with auto-generated name
+// watchChannel removes a channel from the pool once it closes, so the next
+// GetChannel call transparently opens a fresh one. Closes caused by channel
+// errors (e.g. a failed assertion) are logged.
+func (c *Connection) watchChannel(name string, ch *amqp.Channel) {
+	err, ok := <-ch.NotifyClose(make(chan *amqp.Error, 1))
+	if ok && err != nil {
+		log.Printf("%s: Channel '%s' closed: %v", c.logPrefix(), name, err)
+	}
+
+	c.channelsMux.Lock()
+	defer c.channelsMux.Unlock()
+
+	if current, exists := c.channels[name]; exists && current == ch {
+		delete(c.channels, name)
+	}
+}
+
+// NewChannel creates a new channel with a unique auto-generated name. The
+// channel is tracked until it is closed, so Close and reconnects close it too.
 func (c *Connection) NewChannel() (*amqp.Channel, error) {
-	name := fmt.Sprintf("channel_%d", time.Now().UnixNano())
+	name := fmt.Sprintf("channel_%d", c.channelSeq.Add(1))
 	return c.GetChannel(name)
 }
 
@@ -198,30 +266,44 @@ func (c *Connection) CloseChannel(name string) error {
 
 // IsConnected returns true if connected to RabbitMQ
 func (c *Connection) IsConnected() bool {
-	return c.isConnected
+	return c.isConnected.Load()
 }
 
-// Close closes the connection and all channels
+// Close closes the connection and all channels and stops reconnecting. It is
+// safe to call more than once, including while a reconnect is in progress.
 func (c *Connection) Close() error {
-	if !c.isConnected {
-		return nil
-	}
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.isConnected.Store(false)
+		c.closeChannels()
+		c.closeReconnectListeners()
+
+		c.channelsMux.RLock()
+		conn := c.conn
+		c.channelsMux.RUnlock()
 
-	close(c.done)
-	c.closeChannels()
+		if conn != nil && !conn.IsClosed() {
+			err = conn.Close()
+		}
+		log.Printf("%s: Connection closed", c.logPrefix())
+	})
+	return err
+}
 
-	if c.conn != nil && !c.conn.IsClosed() {
-		return c.conn.Close()
+// isClosed reports whether Close has been called
+func (c *Connection) isClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
 	}
-
-	c.isConnected = false
-	log.Println("RabbitMQ: Connection closed")
-	return nil
 }
 
 // WaitForConnection waits until connection is established
 func (c *Connection) WaitForConnection(timeout time.Duration) error {
-	if c.isConnected {
+	if c.isConnected.Load() {
 		return nil
 	}
 
@@ -0,0 +1,56 @@
+package rabbitmq
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestGetChannelWithoutConnection(t *testing.T) {
+	conn := testConnection()
+	if _, err := conn.GetChannel("publisher"); err == nil {
+		t.Error("GetChannel succeeded without a connection")
+	}
+	if err := conn.CloseChannel("publisher"); err != nil {
+		t.Errorf("closing an unknown channel = %v", err)
+	}
+}
+
+// TestChannelRecreatedAfterChannelError needs a broker; set RABBITMQ_TEST_URL
+// to run it
+func TestChannelRecreatedAfterChannelError(t *testing.T) {
+	url := os.Getenv("RABBITMQ_TEST_URL")
+	if url == "" {
+		t.Skip("RABBITMQ_TEST_URL not set")
+	}
+
+	conn, err := NewConnection(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.GetChannel("publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A passive declare of a missing queue closes the channel with 404
+	if _, err := ch.QueueDeclarePassive("golara_test_missing_queue", false, false, false, false, nil); err == nil {
+		t.Fatal("passive declare of a missing queue succeeded")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		fresh, err := conn.GetChannel("publisher")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fresh != ch && !fresh.IsClosed() {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("GetChannel kept returning the closed channel")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -3,38 +3,65 @@ package rabbitmq
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"log"
 	"runtime"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// consumerSeq numbers consumers, keeping their channel names unique
+var consumerSeq atomic.Uint64
+
+// workerRetryDelay is how long a worker waits before consuming again after
+// an error
+const workerRetryDelay = time.Second
+
 // Consumer handles message consumption from RabbitMQ
 type Consumer struct {
-	conn          *Connection
-	queue         string
-	exchange      string
-	routingKey    string
-	consumerTag   string
-	durable       bool
-	autoDelete    bool
-	exclusive     bool
-	noWait        bool
-	args          amqp.Table
-	concurrency   int
-	prefetchCount int
-	autoAck       bool
-	handlers      map[string]MessageHandler
-	middleware    []MiddlewareFunc
-	isRunning     bool
-	stopCh        chan struct{}
-	wg            sync.WaitGroup
+	conn                 *Connection
+	id                   uint64
+	name                 string
+	queue                string
+	exchange             string
+	routingKey           string
+	consumerTag          string
+	durable              bool
+	autoDelete           bool
+	exclusive            bool
+	noWait               bool
+	args                 amqp.Table
+	concurrency          int
+	prefetchCount        int
+	globalPrefetch       bool
+	autoAck              bool
+	manualAck            bool
+	orderingKey          func(*Delivery) string
+	maxAttempts          int
+	deadLetterExchange   string
+	deadLetterRoutingKey string
+	handlers             map[string]MessageHandler
+	middleware           []MiddlewareFunc
+	metrics              consumerMetrics
+	onMetric             []func(ConsumerMetrics)
+	isRunning            atomic.Bool
+	stopCh               chan struct{}
+	stopOnce             sync.Once
+	wake                 chan struct{}
+	wg                   sync.WaitGroup
 }
 
 // ConsumerConfig holds consumer configuration
 type ConsumerConfig struct {
+	// Name identifies the consumer in logs and Stats(). It is also used as
+	// the AMQP consumer tag when ConsumerTag is empty.
+	Name string
+
 	Queue         string
 	Exchange      string
 	RoutingKey    string
@@ -47,12 +74,71 @@ type ConsumerConfig struct {
 	Concurrency   int
 	PrefetchCount int
 	AutoAck       bool
+
+	// GlobalPrefetch makes PrefetchCount the total number of unacknowledged
+	// messages for the whole consumer rather than per worker. Each worker
+	// consumes on its own channel, so by default up to PrefetchCount *
+	// Concurrency messages can be in flight; with GlobalPrefetch the budget
+	// is divided evenly across workers (at least one message each). Ordered
+	// consumers use a single channel, so the two modes are the same there.
+	GlobalPrefetch bool
+
+	// ManualAck leaves acknowledgement to the handler, which must call Ack,
+	// Nack or Reject on every delivery. The consumer neither acks successes
+	// nor requeues failures; returned errors are only logged. It cannot be
+	// combined with AutoAck.
+	ManualAck bool
+
+	// OrderingKey, when set, routes messages with the same key to the same
+	// worker so they are processed in order, while different keys are still
+	// processed in parallel. Messages are consumed through a single AMQP
+	// consumer and dispatched to workers; a slow key blocks its worker and
+	// every other key hashed to it. When a message is requeued, dispatching
+	// stops and the channel is reopened once in-flight messages finish, so
+	// the broker redelivers it ahead of the later messages for its key.
+	// Messages republished by RetryMiddleware, or for MaxAttempts on a
+	// classic queue, go to the back of the queue and lose their place.
+	OrderingKey func(*Delivery) string
+
+	// DeadLetterExchange and DeadLetterRoutingKey are added to the queue's
+	// arguments (x-dead-letter-exchange, x-dead-letter-routing-key) when the
+	// consumer declares it, so rejected messages are routed there instead of
+	// being dropped. See DeclareDeadLetter.
+	DeadLetterExchange   string
+	DeadLetterRoutingKey string
+
+	// MaxAttempts, when positive, stops failed messages from being requeued
+	// forever: a message failing its MaxAttempts-th attempt (see
+	// Delivery.Attempts) is rejected without requeue and dead-lettered.
+	// Only quorum queues (Args "x-queue-type": "quorum") count redeliveries,
+	// so on other queues a failed message is not requeued in place but
+	// republished to the back of the queue with its attempts recorded in
+	// x-retry-count. If republishing fails it is requeued in place, which a
+	// classic queue counts as a second attempt at most.
+	MaxAttempts int
 }
 
 // Delivery wraps amqp.Delivery with additional helper methods
 type Delivery struct {
 	*amqp.Delivery
 	ctx context.Context
+
+	// conn and queue record where the delivery was consumed from, so it can
+	// be republished for a retry
+	conn  *Connection
+	queue string
+
+	// settled is set once the delivery is acked, nacked or rejected, and
+	// requeued when that sent it back to the queue
+	settled  atomic.Bool
+	requeued atomic.Bool
+
+	// retried is set when RetryMiddleware republished the delivery
+	retried bool
+
+	// release closes a channel owned by the delivery, set by Queue.Pop
+	release     func() error
+	releaseOnce sync.Once
 }
 
 // MessageHandler defines the interface for message handlers
@@ -80,28 +166,44 @@ func NewConsumer(conn *Connection, config *ConsumerConfig) (*Consumer, error) {
 		}
 	}
 
+	if config.AutoAck && config.ManualAck {
+		return nil, fmt.Errorf("%w: AutoAck and ManualAck are mutually exclusive", ErrInvalidConfig)
+	}
+
 	// Set default concurrency
 	if config.Concurrency <= 0 {
 		config.Concurrency = runtime.NumCPU()
 	}
+	if config.ConsumerTag == "" {
+		config.ConsumerTag = config.Name
+	}
 
 	consumer := &Consumer{
-		conn:          conn,
-		queue:         config.Queue,
-		exchange:      config.Exchange,
-		routingKey:    config.RoutingKey,
-		consumerTag:   config.ConsumerTag,
-		durable:       config.Durable,
-		autoDelete:    config.AutoDelete,
-		exclusive:     config.Exclusive,
-		noWait:        config.NoWait,
-		args:          config.Args,
-		concurrency:   config.Concurrency,
-		prefetchCount: config.PrefetchCount,
-		autoAck:       config.AutoAck,
-		handlers:      make(map[string]MessageHandler),
-		middleware:    make([]MiddlewareFunc, 0),
-		stopCh:        make(chan struct{}),
+		conn:                 conn,
+		id:                   consumerSeq.Add(1),
+		name:                 config.Name,
+		queue:                config.Queue,
+		exchange:             config.Exchange,
+		routingKey:           config.RoutingKey,
+		consumerTag:          config.ConsumerTag,
+		durable:              config.Durable,
+		autoDelete:           config.AutoDelete,
+		exclusive:            config.Exclusive,
+		noWait:               config.NoWait,
+		args:                 config.Args,
+		concurrency:          config.Concurrency,
+		prefetchCount:        config.PrefetchCount,
+		globalPrefetch:       config.GlobalPrefetch,
+		autoAck:              config.AutoAck,
+		manualAck:            config.ManualAck,
+		orderingKey:          config.OrderingKey,
+		maxAttempts:          config.MaxAttempts,
+		deadLetterExchange:   config.DeadLetterExchange,
+		deadLetterRoutingKey: config.DeadLetterRoutingKey,
+		handlers:             make(map[string]MessageHandler),
+		middleware:           make([]MiddlewareFunc, 0),
+		stopCh:               make(chan struct{}),
+		wake:                 make(chan struct{}, config.Concurrency),
 	}
 
 	// Declare queue if auto-declare is enabled
@@ -129,7 +231,7 @@ func (c *Consumer) declareQueue() error {
 		c.autoDelete, // delete when unused
 		c.exclusive,  // exclusive
 		c.noWait,     // no-wait
-		c.args,       // arguments
+		deadLetterArgs(c.args, c.deadLetterExchange, c.deadLetterRoutingKey), // arguments
 	)
 	if err != nil {
 		return err
@@ -152,6 +254,24 @@ func (c *Consumer) declareQueue() error {
 	return nil
 }
 
+// Name returns the consumer name, if any
+func (c *Consumer) Name() string {
+	return c.name
+}
+
+// Queue returns the name of the consumed queue
+func (c *Consumer) Queue() string {
+	return c.queue
+}
+
+// logPrefix returns the log prefix, including the consumer name when set
+func (c *Consumer) logPrefix() string {
+	if c.name != "" {
+		return fmt.Sprintf("RabbitMQ Consumer[%s]", c.name)
+	}
+	return "RabbitMQ Consumer"
+}
+
 // Handle registers a message handler for a specific routing key
 func (c *Consumer) Handle(routingKey string, handler MessageHandler) {
 	c.handlers[routingKey] = handler
@@ -167,89 +287,205 @@ func (c *Consumer) Use(middleware MiddlewareFunc) {
 	c.middleware = append(c.middleware, middleware)
 }
 
-// Start starts consuming messages
+// Start starts consuming messages and blocks until ctx is cancelled or Stop
+// is called. A stopped consumer cannot be started again.
 func (c *Consumer) Start(ctx context.Context) error {
-	if c.isRunning {
-		return fmt.Errorf("consumer is already running")
+	// Refuse to start without handlers, otherwise every message would be
+	// acknowledged and silently discarded
+	if len(c.handlers) == 0 {
+		return fmt.Errorf("%w: register a handler with Handle or HandleAll before starting consumer for queue '%s'", ErrNoHandlerFound, c.queue)
+	}
+
+	if err := c.begin(); err != nil {
+		return err
 	}
+	defer c.isRunning.Store(false)
+
+	log.Printf("%s: Starting consumer for queue '%s' with %d workers", c.logPrefix(), c.queue, c.concurrency)
 
-	c.isRunning = true
-	log.Printf("RabbitMQ Consumer: Starting consumer for queue '%s' with %d workers", c.queue, c.concurrency)
+	runCtx, cancel := c.runContext(ctx)
+	defer cancel()
+	defer c.watchReconnect(runCtx)()
 
-	// Start workers
-	for i := 0; i < c.concurrency; i++ {
+	// Start workers (ordered consumption dispatches to its own workers)
+	if c.orderingKey != nil {
 		c.wg.Add(1)
-		go c.worker(ctx, i)
+		go c.worker(runCtx, 0)
+	} else {
+		for i := 0; i < c.concurrency; i++ {
+			c.wg.Add(1)
+			go c.worker(runCtx, i)
+		}
 	}
 
 	// Wait for stop signal or context cancellation
 	select {
 	case <-ctx.Done():
-		log.Println("RabbitMQ Consumer: Context cancelled, stopping...")
+		log.Printf("%s: Context cancelled, stopping...", c.logPrefix())
 	case <-c.stopCh:
-		log.Println("RabbitMQ Consumer: Stop signal received")
+		log.Printf("%s: Stop signal received", c.logPrefix())
 	}
 
-	c.isRunning = false
-	close(c.stopCh)
+	c.Stop()
+	cancel()
 	c.wg.Wait()
 
-	log.Println("RabbitMQ Consumer: All workers stopped")
+	log.Printf("%s: All workers stopped", c.logPrefix())
 	return nil
 }
 
-// Stop stops the consumer
+// Stop stops the consumer. It is safe to call more than once and from any
+// goroutine; Start returns once the workers have finished.
 func (c *Consumer) Stop() {
-	if c.isRunning {
+	c.stopOnce.Do(func() {
 		close(c.stopCh)
+	})
+}
+
+// IsRunning reports whether the consumer is consuming
+func (c *Consumer) IsRunning() bool {
+	return c.isRunning.Load()
+}
+
+// runContext derives the context handed to workers and their deliveries, so
+// that stopping the consumer also cancels the context seen by in-flight
+// handlers via Delivery.Context()
+func (c *Consumer) runContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	runCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-c.stopCh:
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+	return runCtx, cancel
+}
+
+// begin marks the consumer as running, failing if it already is or has
+// been stopped
+func (c *Consumer) begin() error {
+	select {
+	case <-c.stopCh:
+		return ErrConsumerClosed
+	default:
+	}
+
+	if !c.isRunning.CompareAndSwap(false, true) {
+		return ErrConsumerAlreadyRunning
 	}
+	return nil
 }
 
-// worker processes messages in a separate goroutine
+// worker processes messages in a separate goroutine. It keeps one channel
+// for its lifetime, opening a new one only after the broker closed it.
 func (c *Consumer) worker(ctx context.Context, workerID int) {
 	defer c.wg.Done()
 
-	log.Printf("RabbitMQ Consumer: Worker %d started", workerID)
+	channelName := c.channelName(fmt.Sprintf("worker_%d", workerID))
+	defer c.conn.CloseChannel(channelName)
+
+	log.Printf("%s: Worker %d started", c.logPrefix(), workerID)
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Printf("RabbitMQ Consumer: Worker %d stopped (context cancelled)", workerID)
+			log.Printf("%s: Worker %d stopped (context cancelled)", c.logPrefix(), workerID)
 			return
 		case <-c.stopCh:
-			log.Printf("RabbitMQ Consumer: Worker %d stopped (stop signal)", workerID)
+			log.Printf("%s: Worker %d stopped (stop signal)", c.logPrefix(), workerID)
 			return
 		default:
-			if err := c.processMessages(ctx, workerID); err != nil {
-				log.Printf("RabbitMQ Consumer: Worker %d error: %v", workerID, err)
-				// Add small delay before retrying
-				select {
-				case <-ctx.Done():
-					return
-				case <-c.stopCh:
-					return
-				default:
-					// Continue processing
-				}
-			}
+		}
+
+		process := c.processMessages
+		if c.orderingKey != nil {
+			process = c.processOrderedMessages
+		}
+
+		ch, err := c.conn.GetChannel(channelName)
+		if err != nil {
+			err = fmt.Errorf("failed to get channel: %w", err)
+		} else {
+			err = process(ctx, ch)
+		}
+		if err == nil {
+			continue
+		}
+
+		log.Printf("%s: Worker %d error: %v", c.logPrefix(), workerID, err)
+		select {
+		case <-ctx.Done():
+		case <-c.stopCh:
+		case <-c.wake:
+		case <-time.After(workerRetryDelay):
 		}
 	}
 }
 
-// processMessages handles the actual message processing
-func (c *Consumer) processMessages(ctx context.Context, workerID int) error {
-	ch, err := c.conn.NewChannel()
+// channelName returns the name of a channel owned by this consumer
+func (c *Consumer) channelName(purpose string) string {
+	return fmt.Sprintf("consumer_%d_%s", c.id, purpose)
+}
+
+// processMessages consumes from ch until the consumer stops (returning nil)
+// or consumption fails
+func (c *Consumer) processMessages(ctx context.Context, ch *amqp.Channel) error {
+	// Set QoS (prefetch count)
+	if err := ch.Qos(c.workerPrefetch(), 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	// Start consuming
+	deliveries, err := ch.Consume(
+		c.queue,       // queue
+		c.consumerTag, // consumer
+		c.autoAck,     // auto-ack
+		c.exclusive,   // exclusive
+		false,         // no-local
+		c.noWait,      // no-wait
+		c.args,        // args
+	)
 	if err != nil {
-		return fmt.Errorf("failed to get channel: %w", err)
+		return fmt.Errorf("failed to start consuming: %w", err)
 	}
-	defer ch.Close()
 
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.stopCh:
+			return nil
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("delivery channel closed")
+			}
+
+			c.process(ctx, delivery)
+		}
+	}
+}
+
+// workerPrefetch returns the prefetch count for each worker's channel. The
+// AMQP global flag only shares a limit between consumers on one channel, so
+// a consumer-wide limit across workers is approximated by splitting it.
+func (c *Consumer) workerPrefetch() int {
+	if !c.globalPrefetch || c.prefetchCount <= 0 {
+		return c.prefetchCount
+	}
+	return max(1, c.prefetchCount/c.concurrency)
+}
+
+// processOrderedMessages consumes through a single channel and dispatches each
+// delivery to a worker chosen by hashing its ordering key, preserving per-key
+// order. When a delivery is requeued the channel is closed, so the broker
+// requeues every unprocessed delivery in its original position.
+func (c *Consumer) processOrderedMessages(ctx context.Context, ch *amqp.Channel) error {
 	// Set QoS (prefetch count)
 	if err := ch.Qos(c.prefetchCount, 0, false); err != nil {
 		return fmt.Errorf("failed to set QoS: %w", err)
 	}
 
-	// Start consuming
 	deliveries, err := ch.Consume(
 		c.queue,       // queue
 		c.consumerTag, // consumer
@@ -263,41 +499,150 @@ func (c *Consumer) processMessages(ctx context.Context, workerID int) error {
 		return fmt.Errorf("failed to start consuming: %w", err)
 	}
 
+	if err := c.dispatchOrdered(ctx, deliveries); !errors.Is(err, errOrderRequeued) {
+		return err
+	}
+	ch.Close()
+	return nil
+}
+
+// errOrderRequeued stops ordered dispatch after a delivery was requeued
+var errOrderRequeued = errors.New("delivery requeued")
+
+// dispatchOrdered hands deliveries to one goroutine per worker, each with its
+// own in-order queue. Once a delivery is requeued it returns errOrderRequeued
+// after every in-flight delivery has been processed.
+func (c *Consumer) dispatchOrdered(ctx context.Context, deliveries <-chan amqp.Delivery) error {
+	requeued := make(chan struct{})
+	var requeuedOnce sync.Once
+
+	queues := make([]chan amqp.Delivery, c.concurrency)
+	var workers sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan amqp.Delivery)
+		workers.Add(1)
+		go func(queue <-chan amqp.Delivery) {
+			defer workers.Done()
+			for delivery := range queue {
+				if c.process(ctx, delivery) {
+					// Take no more deliveries for this worker's keys until
+					// the requeued one comes back
+					requeuedOnce.Do(func() { close(requeued) })
+					return
+				}
+			}
+		}(queues[i])
+	}
+	defer func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+		workers.Wait()
+	}()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
 		case <-c.stopCh:
 			return nil
+		case <-requeued:
+			return errOrderRequeued
 		case delivery, ok := <-deliveries:
 			if !ok {
 				return fmt.Errorf("delivery channel closed")
 			}
 
-			// Wrap delivery
-			d := &Delivery{
-				Delivery: &delivery,
-				ctx:      ctx,
+			key := c.orderingKey(&Delivery{Delivery: &delivery, ctx: ctx})
+			select {
+			case queues[workerIndex(key, c.concurrency)] <- delivery:
+			case <-ctx.Done():
+				return nil
+			case <-c.stopCh:
+				return nil
+			case <-requeued:
+				return errOrderRequeued
 			}
+		}
+	}
+}
 
-			// Process message
-			if err := c.handleMessage(d); err != nil {
-				log.Printf("RabbitMQ Consumer: Error processing message: %v", err)
-				if !c.autoAck {
-					d.Nack(false, true) // Requeue the message
-				}
+// process wraps and handles a single delivery, requeueing it on failure.
+// Messages that fail validation are rejected without requeue. Deliveries the
+// handler settled itself, or any delivery in ManualAck mode, are left alone.
+// It reports whether the delivery was requeued, by the consumer or the handler.
+func (c *Consumer) process(ctx context.Context, delivery amqp.Delivery) bool {
+	// Wrap delivery
+	d := &Delivery{
+		Delivery: &delivery,
+		ctx:      ctx,
+		conn:     c.conn,
+		queue:    c.queue,
+	}
+
+	// Process message
+	if err := c.handleMessage(d); err != nil {
+		log.Printf("%s: Error processing message: %v", c.logPrefix(), err)
+		if c.ownsAck(d) {
+			requeue := !errors.Is(err, ErrValidationFailed) && !errors.Is(err, ErrInvalidMessage) && !errors.Is(err, ErrRetriesExhausted)
+			if requeue && c.maxAttempts > 0 && d.Attempts() >= c.maxAttempts {
+				log.Printf("%s: Message failed %d attempts, rejecting without requeue", c.logPrefix(), d.Attempts())
+				requeue = false
+			}
+			if requeue {
+				c.requeue(d)
+			} else {
+				d.Nack(false, false)
 			}
 		}
 	}
+	return d.requeued.Load()
 }
 
-// handleMessage processes a single message
-func (c *Consumer) handleMessage(delivery *Delivery) error {
+// requeue returns a failed delivery to its queue for another attempt.
+// Classic queues don't count redeliveries, so with MaxAttempts set the
+// consumer counts them itself there: the message is republished with
+// x-retry-count set to the attempts so far and the original is acked.
+// Quorum queues count redeliveries in x-delivery-count, so the message is
+// simply requeued in place.
+func (c *Consumer) requeue(d *Delivery) error {
+	if c.maxAttempts <= 0 || c.quorumQueue() {
+		return d.Nack(false, true)
+	}
+	if err := d.republish(d.Attempts(), 0); err != nil {
+		log.Printf("%s: Failed to republish message for its next attempt, requeueing it: %v", c.logPrefix(), err)
+		return d.Nack(false, true)
+	}
+	return d.Ack(false)
+}
+
+// quorumQueue reports whether the consumer's queue arguments declare a
+// quorum queue
+func (c *Consumer) quorumQueue() bool {
+	queueType, _ := c.args["x-queue-type"].(string)
+	return queueType == "quorum"
+}
+
+// workerIndex maps an ordering key to a worker index
+func workerIndex(key string, workers int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(workers))
+}
+
+// handleMessage processes a single message and records its metrics
+func (c *Consumer) handleMessage(delivery *Delivery) (err error) {
+	start := c.beginMessages(1)
+	defer func() {
+		c.finishMessages(1, start, err, delivery.retried)
+	}()
+
 	// Find appropriate handler
-	handler := c.findHandler(delivery.RoutingKey)
+	routingKey := delivery.OriginalRoutingKey()
+	handler := c.findHandler(routingKey)
 	if handler == nil {
-		log.Printf("RabbitMQ Consumer: No handler found for routing key: %s", delivery.RoutingKey)
-		if !c.autoAck {
+		log.Printf("%s: No handler found for routing key: %s", c.logPrefix(), routingKey)
+		if c.ownsAck(delivery) {
 			delivery.Ack(false)
 		}
 		return nil
@@ -314,14 +659,24 @@ func (c *Consumer) handleMessage(delivery *Delivery) error {
 		return err
 	}
 
-	// Acknowledge message if not auto-ack
-	if !c.autoAck {
+	if c.manualAck && !delivery.Settled() {
+		log.Printf("%s: Handler returned without settling delivery %d in ManualAck mode", c.logPrefix(), delivery.DeliveryTag)
+	}
+
+	// Acknowledge message unless auto-ack or the handler already did
+	if c.ownsAck(delivery) {
 		return delivery.Ack(false)
 	}
 
 	return nil
 }
 
+// ownsAck reports whether the consumer should ack or nack a delivery: not in
+// AutoAck or ManualAck mode, and not once the handler has settled it
+func (c *Consumer) ownsAck(delivery *Delivery) bool {
+	return !c.autoAck && !c.manualAck && !delivery.Settled()
+}
+
 // findHandler finds the appropriate handler for a routing key
 func (c *Consumer) findHandler(routingKey string) MessageHandler {
 	// Try exact match first
@@ -354,7 +709,8 @@ func (d *Delivery) Bytes() []byte {
 	return d.Body
 }
 
-// Context returns the context associated with the delivery
+// Context returns the context associated with the delivery. For consumed
+// deliveries it is cancelled when the consumer stops.
 func (d *Delivery) Context() context.Context {
 	return d.ctx
 }
@@ -374,3 +730,37 @@ func (d *Delivery) GetStringHeader(key string) (string, bool) {
 	}
 	return "", false
 }
+
+// GetIntHeader gets a header value as an integer, accepting any of the
+// signed and unsigned integer types AMQP tables decode to
+func (d *Delivery) GetIntHeader(key string) (int64, bool) {
+	switch v := d.Headers[key].(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// RetryCount returns how many times RetryMiddleware has republished the
+// message, from its x-retry-count header
+func (d *Delivery) RetryCount() int {
+	count, _ := d.GetIntHeader("x-retry-count")
+	return int(count)
+}
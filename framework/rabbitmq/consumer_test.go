@@ -0,0 +1,314 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func orderedConsumer(t *testing.T, handler MessageHandler) *Consumer {
+	t.Helper()
+	consumer := newTestConsumer(t, &ConsumerConfig{
+		Queue:       "events",
+		Concurrency: 4,
+		OrderingKey: func(d *Delivery) string {
+			key, _ := d.GetStringHeader("key")
+			return key
+		},
+	})
+	consumer.HandleAll(handler)
+	return consumer
+}
+
+func TestDispatchOrderedKeepsPerKeyOrder(t *testing.T) {
+	var mutex sync.Mutex
+	seen := make(map[string][]uint64)
+	active := make(map[string]bool)
+	consumer := orderedConsumer(t, func(d *Delivery) error {
+		key, _ := d.GetStringHeader("key")
+		mutex.Lock()
+		if active[key] {
+			t.Errorf("key %s handled concurrently", key)
+		}
+		active[key] = true
+		mutex.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mutex.Lock()
+		active[key] = false
+		seen[key] = append(seen[key], d.DeliveryTag)
+		mutex.Unlock()
+		return nil
+	})
+
+	ack := &fakeAcknowledger{}
+	keys := []string{"a", "b", "c", "d", "e"}
+	deliveries := make(chan amqp.Delivery, 50)
+	for tag := uint64(1); tag <= 50; tag++ {
+		deliveries <- testDelivery(ack, tag, "events", amqp.Table{"key": keys[tag%5]})
+	}
+	close(deliveries)
+
+	if err := consumer.dispatchOrdered(context.Background(), deliveries); err == nil || errors.Is(err, errOrderRequeued) {
+		t.Fatalf("dispatchOrdered = %v, want the closed channel error", err)
+	}
+
+	for _, key := range keys {
+		tags := seen[key]
+		if len(tags) != 10 {
+			t.Errorf("key %s handled %d messages, want 10", key, len(tags))
+		}
+		for i := 1; i < len(tags); i++ {
+			if tags[i] < tags[i-1] {
+				t.Errorf("key %s handled out of order: %v", key, tags)
+				break
+			}
+		}
+	}
+	if got := len(ack.all()); got != 50 {
+		t.Errorf("%d settlements, want 50", got)
+	}
+}
+
+func TestDispatchOrderedStopsWhenRequeued(t *testing.T) {
+	var mutex sync.Mutex
+	var handled []uint64
+	consumer := orderedConsumer(t, func(d *Delivery) error {
+		mutex.Lock()
+		handled = append(handled, d.DeliveryTag)
+		mutex.Unlock()
+		if d.DeliveryTag == 1 {
+			return errTest
+		}
+		return nil
+	})
+
+	ack := &fakeAcknowledger{}
+	deliveries := make(chan amqp.Delivery, 3)
+	deliveries <- testDelivery(ack, 1, "events", amqp.Table{"key": "a"})
+	deliveries <- testDelivery(ack, 2, "events", amqp.Table{"key": "a"})
+	deliveries <- testDelivery(ack, 3, "events", amqp.Table{"key": "a"})
+
+	done := make(chan error, 1)
+	go func() { done <- consumer.dispatchOrdered(context.Background(), deliveries) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, errOrderRequeued) {
+			t.Fatalf("dispatchOrdered = %v, want errOrderRequeued", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispatchOrdered did not stop after a requeue")
+	}
+
+	if len(handled) != 1 {
+		t.Errorf("handled %v, want only the requeued delivery", handled)
+	}
+	want := []ackRecord{{method: "nack", tag: 1, requeue: true}}
+	if got := ack.all(); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("settlements = %+v, want %+v", got, want)
+	}
+}
+
+func TestProcessReportsHandlerRequeue(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "events", ManualAck: true})
+	consumer.HandleAll(func(d *Delivery) error { return d.Reject(true) })
+
+	ack := &fakeAcknowledger{}
+	if !consumer.process(context.Background(), testDelivery(ack, 1, "events", nil)) {
+		t.Error("process did not report the handler's requeue")
+	}
+
+	consumer = newTestConsumer(t, &ConsumerConfig{Queue: "events"})
+	consumer.HandleAll(func(d *Delivery) error { return ErrValidationFailed })
+	if consumer.process(context.Background(), testDelivery(ack, 2, "events", nil)) {
+		t.Error("process reported a requeue for a rejected invalid message")
+	}
+}
+
+func TestWorkerIndexIsStable(t *testing.T) {
+	for _, key := range []string{"", "a", "account-42"} {
+		index := workerIndex(key, 8)
+		if index < 0 || index >= 8 {
+			t.Fatalf("workerIndex(%q) = %d, out of range", key, index)
+		}
+		for i := 0; i < 10; i++ {
+			if workerIndex(key, 8) != index {
+				t.Fatalf("workerIndex(%q) is not stable", key)
+			}
+		}
+	}
+}
+
+func TestStartWithoutHandlersFails(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "events"})
+
+	err := consumer.Start(context.Background())
+	if !errors.Is(err, ErrNoHandlerFound) {
+		t.Fatalf("Start = %v, want ErrNoHandlerFound", err)
+	}
+	if consumer.IsRunning() {
+		t.Error("consumer is running after failing to start")
+	}
+
+	// The consumer can still be started once a handler is registered
+	consumer.HandleAll(func(*Delivery) error { return nil })
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := consumer.Start(ctx); err != nil {
+		t.Errorf("Start with a handler = %v", err)
+	}
+}
+
+func TestStopCancelsDeliveryContext(t *testing.T) {
+	started := make(chan struct{})
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "events"})
+	consumer.HandleAll(func(d *Delivery) error {
+		close(started)
+		<-d.Context().Done()
+		return d.Context().Err()
+	})
+
+	ctx, cancel := consumer.runContext(context.Background())
+	defer cancel()
+
+	ack := &fakeAcknowledger{}
+	done := make(chan bool, 1)
+	go func() { done <- consumer.process(ctx, testDelivery(ack, 1, "events", nil)) }()
+
+	<-started
+	consumer.Stop()
+	select {
+	case requeued := <-done:
+		if !requeued {
+			t.Error("cancelled handler's delivery was not requeued")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not observe the consumer stopping")
+	}
+}
+
+func TestProcessRequeuesQuorumMessagesInPlace(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{
+		Queue:       "events",
+		MaxAttempts: 3,
+		Args:        amqp.Table{"x-queue-type": "quorum"},
+	})
+	consumer.HandleAll(func(*Delivery) error { return errTest })
+
+	ack := &fakeAcknowledger{}
+	consumer.process(context.Background(), testDelivery(ack, 1, "events", amqp.Table{"x-delivery-count": int64(1)}))
+	consumer.process(context.Background(), testDelivery(ack, 2, "events", amqp.Table{"x-delivery-count": int64(2)}))
+
+	want := []ackRecord{
+		{method: "nack", tag: 1, requeue: true},
+		{method: "nack", tag: 2, requeue: false},
+	}
+	if got := ack.all(); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("settlements = %+v, want %+v", got, want)
+	}
+}
+
+func TestProcessCountsClassicAttemptsInRetryCount(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "events", MaxAttempts: 3})
+	consumer.HandleAll(func(*Delivery) error { return errTest })
+
+	// A redelivered classic message without headers is only attempt 2;
+	// x-retry-count carries the count past that
+	ack := &fakeAcknowledger{}
+	redelivered := testDelivery(ack, 1, "events", nil)
+	redelivered.Redelivered = true
+	consumer.process(context.Background(), redelivered)
+	consumer.process(context.Background(), testDelivery(ack, 2, "events", amqp.Table{"x-retry-count": int32(2)}))
+
+	// The test connection can't republish, so attempt 2 falls back to a requeue
+	want := []ackRecord{
+		{method: "nack", tag: 1, requeue: true},
+		{method: "nack", tag: 2, requeue: false},
+	}
+	if got := ack.all(); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("settlements = %+v, want %+v", got, want)
+	}
+}
+
+// TestMaxAttemptsOnClassicQueue needs a broker; set RABBITMQ_TEST_URL to run it
+func TestMaxAttemptsOnClassicQueue(t *testing.T) {
+	url := os.Getenv("RABBITMQ_TEST_URL")
+	if url == "" {
+		t.Skip("RABBITMQ_TEST_URL not set")
+	}
+
+	config := DefaultConfig()
+	config.AutoDeclareExchange = false
+	conn, err := NewConnection(url, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	name := fmt.Sprintf("golara_test_attempts_%d", time.Now().UnixNano())
+	if err := DeclareDeadLetter(conn, name+".dlx", name+".dead"); err != nil {
+		t.Fatal(err)
+	}
+	ch, err := conn.NewChannel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ch.Close()
+	defer ch.ExchangeDelete(name+".dlx", false, false)
+	defer ch.QueueDelete(name+".dead", false, false, false)
+
+	consumer, err := NewConsumer(conn, &ConsumerConfig{
+		Queue:              name,
+		AutoDelete:         true,
+		Concurrency:        1,
+		MaxAttempts:        4,
+		DeadLetterExchange: name + ".dlx",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attempts []int
+	var mu sync.Mutex
+	consumer.HandleAll(func(d *Delivery) error {
+		mu.Lock()
+		attempts = append(attempts, d.Attempts())
+		mu.Unlock()
+		return errTest
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go consumer.Start(ctx)
+
+	if err := ch.Publish("", name, false, false, amqp.Publishing{Body: []byte(`{}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		q, err := ch.QueueDeclarePassive(name+".dead", true, false, false, false, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Messages == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("failing message was not dead-lettered")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(attempts) != 4 || attempts[3] != 4 {
+		t.Errorf("attempts = %v, want 1 to 4", attempts)
+	}
+}
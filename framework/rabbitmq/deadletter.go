@@ -0,0 +1,67 @@
+package rabbitmq
+
+import (
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeclareDeadLetter declares a durable fanout exchange and a durable queue
+// bound to it, to use as a consumer's DeadLetterExchange. Every message
+// dead-lettered to the exchange lands in the queue, whatever its routing key.
+func DeclareDeadLetter(conn *Connection, exchange, queue string) error {
+	ch, err := conn.NewChannel()
+	if err != nil {
+		return fmt.Errorf("failed to get channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := ch.ExchangeDeclare(exchange, "fanout", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead-letter exchange '%s': %w", exchange, err)
+	}
+	if _, err := ch.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead-letter queue '%s': %w", queue, err)
+	}
+	if err := ch.QueueBind(queue, "", exchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind dead-letter queue '%s': %w", queue, err)
+	}
+
+	return nil
+}
+
+// deadLetterArgs returns args with the dead-letter arguments added, leaving
+// args itself unchanged
+func deadLetterArgs(args amqp.Table, exchange, routingKey string) amqp.Table {
+	if exchange == "" {
+		return args
+	}
+
+	result := make(amqp.Table, len(args)+2)
+	for key, value := range args {
+		result[key] = value
+	}
+	result["x-dead-letter-exchange"] = exchange
+	if routingKey != "" {
+		result["x-dead-letter-routing-key"] = routingKey
+	}
+	return result
+}
+
+// Attempts returns which delivery attempt this is, starting at 1. It uses the
+// x-delivery-count header set by quorum queues, or the x-retry-count header
+// set by RetryMiddleware and by consumers with MaxAttempts on other queues.
+// Classic queues don't count redeliveries, so there a redelivered message
+// without those headers counts as its second attempt.
+func (d *Delivery) Attempts() int {
+	attempts := 1
+	if d.Redelivered {
+		attempts = 2
+	}
+	if count, ok := d.GetIntHeader("x-delivery-count"); ok && int(count)+1 > attempts {
+		attempts = int(count) + 1
+	}
+	if count := d.RetryCount(); count+1 > attempts {
+		attempts = count + 1
+	}
+	return attempts
+}
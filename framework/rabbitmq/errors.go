@@ -5,9 +5,10 @@ import "errors"
 // Common RabbitMQ errors
 var (
 	// Connection errors
-	ErrConnectionClosed  = errors.New("rabbitmq connection is closed")
-	ErrConnectionTimeout = errors.New("rabbitmq connection timeout")
-	ErrReconnectFailed   = errors.New("failed to reconnect to rabbitmq")
+	ErrConnectionClosed   = errors.New("rabbitmq connection is closed")
+	ErrConnectionTimeout  = errors.New("rabbitmq connection timeout")
+	ErrReconnectFailed    = errors.New("failed to reconnect to rabbitmq")
+	ErrServiceUnavailable = errors.New("rabbitmq service is not available")
 
 	// Channel errors
 	ErrChannelClosed         = errors.New("rabbitmq channel is closed")
@@ -18,6 +19,10 @@ var (
 	ErrExchangeNotFound = errors.New("exchange not found")
 	ErrQueueNotFound    = errors.New("queue not found")
 	ErrInvalidMessage   = errors.New("invalid message format")
+	ErrPublishNacked    = errors.New("message was nacked by the broker")
+	ErrMessageReturned  = errors.New("message was returned as unroutable")
+	ErrConfirmTimeout   = errors.New("timed out waiting for publisher confirm")
+	ErrRequestFailed    = errors.New("rpc request failed")
 
 	// Consumer errors
 	ErrConsumerClosed         = errors.New("consumer is closed")
@@ -29,6 +34,8 @@ var (
 	ErrProcessingTimeout   = errors.New("message processing timeout")
 	ErrValidationFailed    = errors.New("message validation failed")
 	ErrDeduplicationFailed = errors.New("message deduplication failed")
+	ErrMessageInProgress   = errors.New("message is being processed by another consumer")
+	ErrRetriesExhausted    = errors.New("message retries exhausted")
 
 	// Configuration errors
 	ErrInvalidConfig = errors.New("invalid configuration")
@@ -50,7 +57,8 @@ var (
 func IsConnectionError(err error) bool {
 	return err == ErrConnectionClosed ||
 		err == ErrConnectionTimeout ||
-		err == ErrReconnectFailed
+		err == ErrReconnectFailed ||
+		err == ErrServiceUnavailable
 }
 
 // IsChannelError checks if the error is related to channel issues
@@ -64,7 +72,10 @@ func IsPublishError(err error) bool {
 	return err == ErrPublishFailed ||
 		err == ErrExchangeNotFound ||
 		err == ErrQueueNotFound ||
-		err == ErrInvalidMessage
+		err == ErrInvalidMessage ||
+		err == ErrPublishNacked ||
+		err == ErrMessageReturned ||
+		err == ErrConfirmTimeout
 }
 
 // IsConsumerError checks if the error is related to consuming
@@ -79,14 +90,16 @@ func IsMiddlewareError(err error) bool {
 	return err == ErrPanicRecovered ||
 		err == ErrProcessingTimeout ||
 		err == ErrValidationFailed ||
-		err == ErrDeduplicationFailed
+		err == ErrDeduplicationFailed ||
+		err == ErrMessageInProgress
 }
 
 // IsRetryableError checks if the error should trigger a retry
 func IsRetryableError(err error) bool {
 	return IsConnectionError(err) ||
 		IsChannelError(err) ||
-		err == ErrProcessingTimeout
+		err == ErrProcessingTimeout ||
+		err == ErrMessageInProgress
 }
 
 // IsTemporaryError checks if the error is temporary and should be retried
@@ -94,5 +107,6 @@ func IsTemporaryError(err error) bool {
 	return IsConnectionError(err) ||
 		IsChannelError(err) ||
 		err == ErrProcessingTimeout ||
+		err == ErrMessageInProgress ||
 		err == ErrPublishFailed
 }
@@ -0,0 +1,80 @@
+package rabbitmq
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var errTest = errors.New("test failure")
+
+// ackRecord is one settlement recorded by fakeAcknowledger
+type ackRecord struct {
+	method   string
+	tag      uint64
+	multiple bool
+	requeue  bool
+}
+
+// fakeAcknowledger records acks, nacks and rejects instead of sending them
+// to a broker
+type fakeAcknowledger struct {
+	mutex   sync.Mutex
+	records []ackRecord
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.record(ackRecord{method: "ack", tag: tag, multiple: multiple})
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.record(ackRecord{method: "nack", tag: tag, multiple: multiple, requeue: requeue})
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	a.record(ackRecord{method: "reject", tag: tag, requeue: requeue})
+	return nil
+}
+
+func (a *fakeAcknowledger) record(r ackRecord) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.records = append(a.records, r)
+}
+
+func (a *fakeAcknowledger) all() []ackRecord {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return append([]ackRecord{}, a.records...)
+}
+
+// testConnection returns a connection that never dials, for consumers whose
+// queues are not declared
+func testConnection() *Connection {
+	return &Connection{config: &Config{}, channels: make(map[string]*amqp.Channel)}
+}
+
+// newTestConsumer creates a consumer on testConnection
+func newTestConsumer(t *testing.T, config *ConsumerConfig) *Consumer {
+	t.Helper()
+	consumer, err := NewConsumer(testConnection(), config)
+	if err != nil {
+		t.Fatalf("NewConsumer: %v", err)
+	}
+	return consumer
+}
+
+// testDelivery builds a delivery acknowledged through ack
+func testDelivery(ack *fakeAcknowledger, tag uint64, routingKey string, headers amqp.Table) amqp.Delivery {
+	return amqp.Delivery{
+		Acknowledger: ack,
+		DeliveryTag:  tag,
+		RoutingKey:   routingKey,
+		Headers:      headers,
+		Body:         []byte(`{}`),
+	}
+}
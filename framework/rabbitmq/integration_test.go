@@ -0,0 +1,54 @@
+package rabbitmq
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/taeyelor/golara/framework"
+)
+
+func TestOptionalSwallowsPushesWhenUnavailable(t *testing.T) {
+	app := framework.NewApplication()
+	// A registered service whose connection failed resolves to nil
+	app.Singleton("rabbitmq", func() interface{} { return nil })
+
+	rabbit := Optional(app)
+	if !rabbit.IsNoop() {
+		t.Fatal("Optional did not return a no-op instance")
+	}
+	if err := rabbit.Push("emails", map[string]string{"to": "ada@example.com"}); err != nil {
+		t.Errorf("Push = %v, want nil", err)
+	}
+	if err := rabbit.PushJob("emails", "welcome", nil); err != nil {
+		t.Errorf("PushJob = %v, want nil", err)
+	}
+	if err := rabbit.PushDelayedTTL("emails", "later", time.Minute); err != nil {
+		t.Errorf("PushDelayedTTL = %v, want nil", err)
+	}
+	if err := rabbit.Health(); !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("Health = %v, want ErrServiceUnavailable", err)
+	}
+	if _, err := rabbit.Queue("emails"); !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("Queue = %v, want ErrServiceUnavailable", err)
+	}
+	if err := rabbit.Close(); err != nil {
+		t.Errorf("Close = %v", err)
+	}
+}
+
+func TestRequiredSurfacesUnavailability(t *testing.T) {
+	app := framework.NewApplication()
+	if rabbit, err := Required(app); rabbit != nil || !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("Required without a service = %v, %v; want ErrServiceUnavailable", rabbit, err)
+	}
+
+	registered := &RabbitMQ{manager: &Manager{}}
+	app.Singleton("rabbitmq", func() interface{} { return registered })
+	if rabbit, err := Required(app); rabbit != registered || err != nil {
+		t.Errorf("Required = %v, %v; want the registered service", rabbit, err)
+	}
+	if Optional(app) != registered {
+		t.Error("Optional did not return the registered service")
+	}
+}
@@ -0,0 +1,211 @@
+package rabbitmq
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// JSONSchema is a compiled JSON Schema supporting the commonly used subset of
+// the specification: type, properties, required, additionalProperties (boolean),
+// items, enum, minimum, maximum, minLength, maxLength, pattern, minItems and
+// maxItems. Unsupported keywords are ignored.
+type JSONSchema struct {
+	Type                 interface{}            `json:"type"`
+	Properties           map[string]*JSONSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *JSONSchema            `json:"items"`
+	Enum                 []interface{}          `json:"enum"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+
+	pattern *regexp.Regexp
+}
+
+// CompileJSONSchema parses a JSON Schema document
+func CompileJSONSchema(schema string) (*JSONSchema, error) {
+	var s JSONSchema
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// compile validates the schema and compiles its patterns
+func (s *JSONSchema) compile() error {
+	switch t := s.Type.(type) {
+	case nil, string:
+	case []interface{}:
+		for _, name := range t {
+			if _, ok := name.(string); !ok {
+				return fmt.Errorf("invalid JSON schema: type must be a string or an array of strings")
+			}
+		}
+	default:
+		return fmt.Errorf("invalid JSON schema: type must be a string or an array of strings")
+	}
+
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid JSON schema pattern '%s': %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+
+	for _, property := range s.Properties {
+		if err := property.compile(); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+	return nil
+}
+
+// ValidateBytes validates a JSON document against the schema
+func (s *JSONSchema) ValidateBytes(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("body is not valid JSON: %w", err)
+	}
+	return s.Validate(value)
+}
+
+// Validate validates a decoded JSON value against the schema
+func (s *JSONSchema) Validate(value interface{}) error {
+	return s.validate(value, "$")
+}
+
+func (s *JSONSchema) validate(value interface{}, path string) error {
+	if s.Type != nil && !s.matchesType(value) {
+		return fmt.Errorf("%s: expected type %v", path, s.Type)
+	}
+
+	if len(s.Enum) > 0 {
+		matched := false
+		for _, option := range s.Enum {
+			if reflect.DeepEqual(option, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: value is not one of the allowed values", path)
+		}
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			return fmt.Errorf("%s: must be >= %v", path, *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			return fmt.Errorf("%s: must be <= %v", path, *s.Maximum)
+		}
+
+	case string:
+		length := len([]rune(v))
+		if s.MinLength != nil && length < *s.MinLength {
+			return fmt.Errorf("%s: must be at least %d characters", path, *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fmt.Errorf("%s: must be at most %d characters", path, *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			return fmt.Errorf("%s: does not match pattern '%s'", path, s.Pattern)
+		}
+
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			return fmt.Errorf("%s: must have at least %d items", path, *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			return fmt.Errorf("%s: must have at most %d items", path, *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, exists := v[name]; !exists {
+				return fmt.Errorf("%s: missing required property '%s'", path, name)
+			}
+		}
+		for name, propertyValue := range v {
+			property, known := s.Properties[name]
+			if !known {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: unexpected property '%s'", path, name)
+				}
+				continue
+			}
+			if err := property.validate(propertyValue, path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// matchesType checks a value against the schema's type keyword
+func (s *JSONSchema) matchesType(value interface{}) bool {
+	switch t := s.Type.(type) {
+	case string:
+		return matchesJSONType(t, value)
+	case []interface{}:
+		for _, name := range t {
+			if matchesJSONType(name.(string), value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matchesJSONType checks a decoded JSON value against a JSON Schema type name
+func matchesJSONType(typeName string, value interface{}) bool {
+	switch strings.ToLower(typeName) {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package rabbitmq
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+const orderSchema = `{
+	"type": "object",
+	"required": ["id", "items"],
+	"additionalProperties": false,
+	"properties": {
+		"id": {"type": "string", "pattern": "^ord_"},
+		"status": {"enum": ["new", "paid"]},
+		"total": {"type": "number", "minimum": 0},
+		"items": {"type": "array", "minItems": 1, "items": {"type": "string", "minLength": 1}}
+	}
+}`
+
+func TestJSONSchemaValidate(t *testing.T) {
+	schema, err := CompileJSONSchema(orderSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := `{"id": "ord_1", "status": "paid", "total": 9.5, "items": ["book"]}`
+	if err := schema.ValidateBytes([]byte(valid)); err != nil {
+		t.Errorf("valid document: %v", err)
+	}
+
+	invalid := map[string]string{
+		"missing required": `{"id": "ord_1"}`,
+		"wrong type":       `{"id": 1, "items": ["book"]}`,
+		"pattern":          `{"id": "x_1", "items": ["book"]}`,
+		"enum":             `{"id": "ord_1", "status": "lost", "items": ["book"]}`,
+		"minimum":          `{"id": "ord_1", "total": -1, "items": ["book"]}`,
+		"minItems":         `{"id": "ord_1", "items": []}`,
+		"nested minLength": `{"id": "ord_1", "items": [""]}`,
+		"additional":       `{"id": "ord_1", "items": ["book"], "extra": true}`,
+		"not JSON":         `{"id":`,
+	}
+	for name, doc := range invalid {
+		if err := schema.ValidateBytes([]byte(doc)); err == nil {
+			t.Errorf("%s: document passed validation", name)
+		}
+	}
+
+	if _, err := CompileJSONSchema(`{"pattern": "("}`); err == nil {
+		t.Error("schema with an invalid pattern compiled")
+	}
+}
+
+func TestWithJSONSchemaRejectsInvalidMessages(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "orders"})
+	consumer.Use(WithJSONSchema(orderSchema))
+	var handled int
+	consumer.HandleAll(func(*Delivery) error {
+		handled++
+		return nil
+	})
+
+	ack := &fakeAcknowledger{}
+	valid := testDelivery(ack, 1, "orders", nil)
+	valid.Body = []byte(`{"id": "ord_1", "items": ["book"]}`)
+	invalid := testDelivery(ack, 2, "orders", nil)
+	invalid.Body = []byte(`{"id": "ord_2"}`)
+
+	consumer.process(context.Background(), valid)
+	consumer.process(context.Background(), invalid)
+
+	if handled != 1 {
+		t.Errorf("handler ran %d times, want 1", handled)
+	}
+	want := []ackRecord{
+		{method: "ack", tag: 1},
+		{method: "nack", tag: 2, requeue: false},
+	}
+	if got := ack.all(); !reflect.DeepEqual(got, want) {
+		t.Errorf("settlements = %+v, want %+v", got, want)
+	}
+}
+
+func TestWithJSONSchemaPanicsOnInvalidSchema(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WithJSONSchema accepted an invalid schema")
+		}
+	}()
+	WithJSONSchema(`{"type": `)
+}
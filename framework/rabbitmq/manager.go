@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -20,6 +22,7 @@ type Manager struct {
 
 // ManagerConfig holds the configuration for the RabbitMQ manager
 type ManagerConfig struct {
+	Name                string
 	URL                 string
 	ReconnectDelay      string
 	ReconnectAttempts   int
@@ -54,17 +57,21 @@ func NewManager(url string, config *Config) (*Manager, error) {
 		return nil, err
 	}
 
-	return &Manager{
+	manager := &Manager{
 		conn:       conn,
 		publishers: make(map[string]*Publisher),
 		consumers:  make(map[string]*Consumer),
 		queues:     make(map[string]*Queue),
-	}, nil
+	}
+	go manager.watchReconnect()
+
+	return manager, nil
 }
 
 // NewManagerFromConfig creates a new manager from configuration
 func NewManagerFromConfig(config *ManagerConfig) (*Manager, error) {
 	rabbitConfig := DefaultConfig()
+	rabbitConfig.Name = config.Name
 	rabbitConfig.URL = config.URL
 
 	if config.ReconnectAttempts > 0 {
@@ -73,6 +80,12 @@ func NewManagerFromConfig(config *ManagerConfig) (*Manager, error) {
 	if config.ChannelPoolSize > 0 {
 		rabbitConfig.ChannelPoolSize = config.ChannelPoolSize
 	}
+	if delay, err := time.ParseDuration(config.ReconnectDelay); err == nil && delay > 0 {
+		rabbitConfig.ReconnectDelay = delay
+	}
+	if interval, err := time.ParseDuration(config.HeartbeatInterval); err == nil && interval > 0 {
+		rabbitConfig.HeartbeatInterval = interval
+	}
 
 	rabbitConfig.AutoDeclareQueues = config.AutoDeclareQueues
 	rabbitConfig.AutoDeclareExchange = config.AutoDeclareExchange
@@ -110,6 +123,63 @@ func (m *Manager) DeclareExchange(config *ExchangeConfig) error {
 	return err
 }
 
+// BindQueueToExchange binds a queue to an exchange with a routing key or,
+// for topic exchanges, a pattern such as "events.user.*" or "events.#"
+func (m *Manager) BindQueueToExchange(queue, exchange, pattern string) error {
+	ch, err := m.conn.NewChannel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := ch.QueueBind(queue, pattern, exchange, false, nil); err != nil {
+		return fmt.Errorf("%w: %v", ErrQueueBindFailed, err)
+	}
+
+	log.Printf("RabbitMQ Manager: Bound queue '%s' to exchange '%s' with pattern '%s'", queue, exchange, pattern)
+	return nil
+}
+
+// UnbindQueueFromExchange removes a binding made with BindQueueToExchange
+func (m *Manager) UnbindQueueFromExchange(queue, exchange, pattern string) error {
+	ch, err := m.conn.NewChannel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	return ch.QueueUnbind(queue, pattern, exchange, nil)
+}
+
+// ExchangeBind routes messages published to source that match the routing
+// key or pattern on to destination, e.g. from an "events" topic exchange to
+// a "user-events" exchange for "events.user.#"
+func (m *Manager) ExchangeBind(destination, source, pattern string) error {
+	ch, err := m.conn.NewChannel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	err = ch.ExchangeBind(destination, pattern, source, false, nil)
+	if err == nil {
+		log.Printf("RabbitMQ Manager: Bound exchange '%s' to exchange '%s' with pattern '%s'", destination, source, pattern)
+	}
+
+	return err
+}
+
+// ExchangeUnbind removes a binding made with ExchangeBind
+func (m *Manager) ExchangeUnbind(destination, source, pattern string) error {
+	ch, err := m.conn.NewChannel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	return ch.ExchangeUnbind(destination, pattern, source, false, nil)
+}
+
 // Queue gets or creates a queue
 func (m *Manager) Queue(name string, config *QueueConfig) (*Queue, error) {
 	m.mutex.RLock()
@@ -216,6 +286,21 @@ func (m *Manager) Consumer(queue string, config *ConsumerConfig) (*Consumer, err
 	return consumer, nil
 }
 
+// Consumers returns the consumers created through the manager
+func (m *Manager) Consumers() []*Consumer {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	consumers := make([]*Consumer, 0, len(m.consumers))
+	for _, consumer := range m.consumers {
+		consumers = append(consumers, consumer)
+	}
+	sort.Slice(consumers, func(i, j int) bool {
+		return consumers[i].Queue() < consumers[j].Queue()
+	})
+	return consumers
+}
+
 // Publish publishes a message to an exchange
 func (m *Manager) Publish(exchange, routingKey string, data interface{}) error {
 	publisher, err := m.Publisher(exchange, nil)
@@ -354,10 +439,45 @@ func (m *Manager) Stats() map[string]interface{} {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
+	publishers := make([]map[string]interface{}, 0, len(m.publishers))
+	for _, publisher := range m.publishers {
+		publishers = append(publishers, map[string]interface{}{
+			"name":     publisher.Name(),
+			"exchange": publisher.Exchange(),
+		})
+	}
+
+	var totals ConsumerMetrics
+	consumers := make([]map[string]interface{}, 0, len(m.consumers))
+	for _, consumer := range m.consumers {
+		metrics := consumer.Metrics()
+		totals.Processed += metrics.Processed
+		totals.Failed += metrics.Failed
+		totals.Retried += metrics.Retried
+		totals.InFlight += metrics.InFlight
+
+		consumers = append(consumers, map[string]interface{}{
+			"name":      consumer.Name(),
+			"queue":     consumer.Queue(),
+			"processed": metrics.Processed,
+			"failed":    metrics.Failed,
+			"retried":   metrics.Retried,
+			"in_flight": metrics.InFlight,
+			"avg_time":  metrics.AverageDuration().String(),
+		})
+	}
+
 	return map[string]interface{}{
-		"connected":        m.conn.IsConnected(),
-		"total_publishers": len(m.publishers),
-		"total_consumers":  len(m.consumers),
-		"total_queues":     len(m.queues),
+		"name":               m.conn.Name(),
+		"connected":          m.conn.IsConnected(),
+		"total_publishers":   len(m.publishers),
+		"total_consumers":    len(m.consumers),
+		"total_queues":       len(m.queues),
+		"publishers":         publishers,
+		"consumers":          consumers,
+		"messages_processed": totals.Processed,
+		"messages_failed":    totals.Failed,
+		"messages_retried":   totals.Retried,
+		"messages_in_flight": totals.InFlight,
 	}
 }
@@ -0,0 +1,58 @@
+package rabbitmq
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestStatsIncludeNames(t *testing.T) {
+	conn := testConnection()
+	conn.config.Name = "orders-service"
+	consumer, err := NewConsumer(conn, &ConsumerConfig{Name: "notification-consumer", Queue: "notifications"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := &Manager{
+		conn:       conn,
+		publishers: map[string]*Publisher{"emails": {name: "email-publisher", exchange: "emails"}},
+		consumers:  map[string]*Consumer{"notifications": consumer},
+		queues:     map[string]*Queue{},
+	}
+
+	stats := manager.Stats()
+	if stats["name"] != "orders-service" {
+		t.Errorf("connection name = %v", stats["name"])
+	}
+	publishers := stats["publishers"].([]map[string]interface{})
+	if len(publishers) != 1 || publishers[0]["name"] != "email-publisher" {
+		t.Errorf("publishers = %v", publishers)
+	}
+	consumers := stats["consumers"].([]map[string]interface{})
+	if len(consumers) != 1 || consumers[0]["name"] != "notification-consumer" || consumers[0]["queue"] != "notifications" {
+		t.Errorf("consumers = %v", consumers)
+	}
+}
+
+func TestConsumerNameInLogsAndMetrics(t *testing.T) {
+	var output bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&output)
+
+	consumer := newTestConsumer(t, &ConsumerConfig{Name: "notification-consumer", Queue: "notifications"})
+	var snapshot ConsumerMetrics
+	consumer.OnMetric(func(m ConsumerMetrics) { snapshot = m })
+	consumer.Handle("known", func(*Delivery) error { return nil })
+
+	ack := &fakeAcknowledger{}
+	consumer.process(context.Background(), testDelivery(ack, 1, "unknown", nil))
+
+	if !strings.Contains(output.String(), "RabbitMQ Consumer[notification-consumer]") {
+		t.Errorf("log output %q does not name the consumer", output.String())
+	}
+	if snapshot.Name != "notification-consumer" || snapshot.Queue != "notifications" {
+		t.Errorf("metric snapshot = %+v", snapshot)
+	}
+}
@@ -0,0 +1,105 @@
+package rabbitmq
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ConsumerMetrics is a snapshot of a consumer's message counters
+type ConsumerMetrics struct {
+	Name  string
+	Queue string
+
+	// Processed counts messages handled successfully
+	Processed uint64
+
+	// Failed counts messages whose handler returned an error
+	Failed uint64
+
+	// Retried counts failed messages republished by RetryMiddleware
+	Retried uint64
+
+	// InFlight is the number of messages being handled right now
+	InFlight int64
+
+	// TotalDuration is the time spent handling all finished messages and
+	// LastDuration the time spent on the most recent one
+	TotalDuration time.Duration
+	LastDuration  time.Duration
+}
+
+// Handled returns the number of finished messages
+func (m ConsumerMetrics) Handled() uint64 {
+	return m.Processed + m.Failed + m.Retried
+}
+
+// AverageDuration returns the mean handling time per finished message
+func (m ConsumerMetrics) AverageDuration() time.Duration {
+	if handled := m.Handled(); handled > 0 {
+		return m.TotalDuration / time.Duration(handled)
+	}
+	return 0
+}
+
+// consumerMetrics holds a consumer's counters, updated atomically by its
+// workers
+type consumerMetrics struct {
+	processed  atomic.Uint64
+	failed     atomic.Uint64
+	retried    atomic.Uint64
+	inFlight   atomic.Int64
+	totalNanos atomic.Int64
+	lastNanos  atomic.Int64
+}
+
+// Metrics returns a snapshot of the consumer's counters
+func (c *Consumer) Metrics() ConsumerMetrics {
+	return ConsumerMetrics{
+		Name:          c.name,
+		Queue:         c.queue,
+		Processed:     c.metrics.processed.Load(),
+		Failed:        c.metrics.failed.Load(),
+		Retried:       c.metrics.retried.Load(),
+		InFlight:      c.metrics.inFlight.Load(),
+		TotalDuration: time.Duration(c.metrics.totalNanos.Load()),
+		LastDuration:  time.Duration(c.metrics.lastNanos.Load()),
+	}
+}
+
+// OnMetric registers a callback invoked with a fresh snapshot after every
+// handled message, e.g. to feed an external metrics system. Callbacks run on
+// the worker goroutine, so they should be quick. Register them before Start.
+func (c *Consumer) OnMetric(callback func(ConsumerMetrics)) {
+	c.onMetric = append(c.onMetric, callback)
+}
+
+// beginMessages marks n messages as in flight and returns the start time
+func (c *Consumer) beginMessages(n int) time.Time {
+	c.metrics.inFlight.Add(int64(n))
+	return time.Now()
+}
+
+// finishMessages records the outcome of n messages started at start. A
+// delivery republished by RetryMiddleware counts as retried.
+func (c *Consumer) finishMessages(n int, start time.Time, err error, retried bool) {
+	elapsed := time.Since(start)
+	c.metrics.inFlight.Add(-int64(n))
+	c.metrics.totalNanos.Add(int64(elapsed))
+	c.metrics.lastNanos.Store(int64(elapsed))
+
+	switch {
+	case err != nil:
+		c.metrics.failed.Add(uint64(n))
+	case retried:
+		c.metrics.retried.Add(uint64(n))
+	default:
+		c.metrics.processed.Add(uint64(n))
+	}
+
+	if len(c.onMetric) > 0 {
+		snapshot := c.Metrics()
+		for _, callback := range c.onMetric {
+			callback(snapshot)
+		}
+	}
+}
@@ -1,10 +1,11 @@
 package rabbitmq
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"sync"
 	"time"
-
-	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 // Built-in middleware functions
@@ -28,47 +29,57 @@ func LoggingMiddleware(next MessageHandler) MessageHandler {
 	}
 }
 
-// RetryMiddleware provides retry functionality for failed messages
+// maxRetryDelay caps the exponential backoff of RetryMiddleware
+const maxRetryDelay = 24 * time.Hour
+
+// RetryMiddleware retries failed messages with exponential backoff. The
+// message is acknowledged and a copy is republished to its queue with the
+// x-retry-count header incremented, after retryDelay for the first retry and
+// twice as long for each one after. Delays use the same delay queues as
+// Queue.PushDelayedTTL, so no broker plugin is needed.
+//
+// Once maxRetries retries have failed, the error is wrapped in
+// ErrRetriesExhausted and the consumer rejects the message without requeue,
+// dead-lettering it if the queue has a dead-letter exchange. Validation
+// errors are not retried.
 func RetryMiddleware(maxRetries int, retryDelay time.Duration) MiddlewareFunc {
 	return func(next MessageHandler) MessageHandler {
 		return func(delivery *Delivery) error {
-			retryCount := 0
-
-			// Check if this message has been retried before
-			if retryHeader, exists := delivery.GetHeader("x-retry-count"); exists {
-				if count, ok := retryHeader.(int); ok {
-					retryCount = count
-				}
+			err := next(delivery)
+			if err == nil || errors.Is(err, ErrValidationFailed) || errors.Is(err, ErrInvalidMessage) {
+				return err
 			}
 
-			err := next(delivery)
-			if err != nil && retryCount < maxRetries {
-				// Increment retry count
-				retryCount++
-				log.Printf("RabbitMQ Middleware: Retrying message (attempt %d/%d): %v", retryCount, maxRetries, err)
-
-				// Publish the message back to the queue with retry count
-				headers := make(amqp.Table)
-				for k, v := range delivery.Headers {
-					headers[k] = v
-				}
-				headers["x-retry-count"] = retryCount
-
-				// Add delay before retry
-				if retryDelay > 0 {
-					time.Sleep(retryDelay)
-				}
-
-				// Note: In a real implementation, you'd want to republish to a retry queue
-				// For now, we'll just acknowledge the message and let it fail
-				return nil
+			retryCount := delivery.RetryCount()
+			if retryCount >= maxRetries {
+				log.Printf("RabbitMQ Middleware: Giving up after %d retries: %v", retryCount, err)
+				return fmt.Errorf("%w: %w", ErrRetriesExhausted, err)
 			}
 
-			return err
+			retryCount++
+			delay := retryBackoff(retryDelay, retryCount)
+			log.Printf("RabbitMQ Middleware: Retrying message in %v (attempt %d/%d): %v", delay, retryCount, maxRetries, err)
+
+			if rerr := delivery.republish(retryCount, delay); rerr != nil {
+				// Fall back to the consumer's requeue
+				log.Printf("RabbitMQ Middleware: Failed to schedule retry: %v", rerr)
+				return err
+			}
+			delivery.retried = true
+			return nil
 		}
 	}
 }
 
+// retryBackoff returns base doubled for every retry after the first
+func retryBackoff(base time.Duration, retry int) time.Duration {
+	delay := base
+	for i := 1; i < retry && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
 // RateLimitMiddleware provides rate limiting
 func RateLimitMiddleware(requestsPerSecond int) MiddlewareFunc {
 	limiter := time.NewTicker(time.Second / time.Duration(requestsPerSecond))
@@ -95,6 +106,19 @@ func ValidationMiddleware(validator func(*Delivery) error) MiddlewareFunc {
 	}
 }
 
+// JSONSchemaMiddleware validates each delivery body against a JSON Schema before
+// the handler runs. Invalid messages fail with ErrValidationFailed and are
+// rejected without requeue, so they are dead-lettered when the queue has a
+// dead-letter exchange.
+func JSONSchemaMiddleware(schema *JSONSchema) MiddlewareFunc {
+	return ValidationMiddleware(func(delivery *Delivery) error {
+		if err := schema.ValidateBytes(delivery.Body); err != nil {
+			return fmt.Errorf("%w: %v", ErrValidationFailed, err)
+		}
+		return nil
+	})
+}
+
 // RecoveryMiddleware recovers from panics
 func RecoveryMiddleware(next MessageHandler) MessageHandler {
 	return func(delivery *Delivery) (err error) {
@@ -140,6 +164,10 @@ func DeduplicationMiddleware(store MessageStore) MiddlewareFunc {
 				messageID = generateMessageID(delivery)
 			}
 
+			if claimer, ok := store.(ClaimingMessageStore); ok {
+				return processClaimed(claimer, messageID, next, delivery)
+			}
+
 			// Check if we've already processed this message
 			if store.HasProcessed(messageID) {
 				log.Printf("RabbitMQ Middleware: Duplicate message detected, skipping: %s", messageID)
@@ -158,57 +186,143 @@ func DeduplicationMiddleware(store MessageStore) MiddlewareFunc {
 	}
 }
 
-// MessageStore interface for deduplication
+// processClaimed claims a message ID before running the handler, marks it
+// processed when the handler succeeds and releases it when it fails, so a
+// redelivery is processed again. A message whose ID is claimed by another
+// consumer fails with ErrMessageInProgress so it is requeued rather than
+// acked: that consumer may still fail, or may have crashed.
+func processClaimed(store ClaimingMessageStore, messageID string, next MessageHandler, delivery *Delivery) error {
+	if !store.Claim(messageID) {
+		if !store.HasProcessed(messageID) {
+			return ErrMessageInProgress
+		}
+		log.Printf("RabbitMQ Middleware: Duplicate message detected, skipping: %s", messageID)
+		return nil
+	}
+
+	err := next(delivery)
+	if err != nil {
+		store.Release(messageID)
+		return err
+	}
+	store.MarkProcessed(messageID)
+	return nil
+}
+
+// MessageStore records processed message IDs for DeduplicationMiddleware.
+// InMemoryMessageStore is local to the process; redisstore.RedisMessageStore
+// is shared between instances and survives restarts.
 type MessageStore interface {
 	HasProcessed(messageID string) bool
 	MarkProcessed(messageID string)
 }
 
-// InMemoryMessageStore is a simple in-memory implementation
+// ClaimingMessageStore is a MessageStore that can claim an ID atomically
+// before processing. DeduplicationMiddleware prefers it: with separate check
+// and mark steps, two consumers receiving the same message at once would
+// both process it. HasProcessed must not report an ID that is only claimed.
+type ClaimingMessageStore interface {
+	MessageStore
+
+	// Claim records messageID as being processed unless it is already
+	// claimed or processed, reporting whether this caller claimed it.
+	// MarkProcessed confirms the claim.
+	Claim(messageID string) bool
+
+	// Release forgets a claimed ID whose processing failed
+	Release(messageID string)
+}
+
+// InMemoryMessageStore is a simple in-memory implementation. Expired IDs are
+// swept while new ones are marked, so it needs no background goroutine.
 type InMemoryMessageStore struct {
 	processed map[string]time.Time
+	claimed   map[string]struct{}
 	ttl       time.Duration
+	lastSweep time.Time
+	mutex     sync.RWMutex
 }
 
-// NewInMemoryMessageStore creates a new in-memory message store
+// inMemorySweepInterval is the longest time expired IDs are kept in memory
+const inMemorySweepInterval = time.Minute
+
+// NewInMemoryMessageStore creates a new in-memory message store that
+// remembers message IDs for ttl
 func NewInMemoryMessageStore(ttl time.Duration) *InMemoryMessageStore {
-	store := &InMemoryMessageStore{
+	return &InMemoryMessageStore{
 		processed: make(map[string]time.Time),
+		claimed:   make(map[string]struct{}),
 		ttl:       ttl,
+		lastSweep: time.Now(),
 	}
-
-	// Start cleanup goroutine
-	go store.cleanup()
-
-	return store
 }
 
-// HasProcessed checks if a message has been processed
+// HasProcessed reports whether a message was marked processed within the TTL
 func (s *InMemoryMessageStore) HasProcessed(messageID string) bool {
-	_, exists := s.processed[messageID]
-	return exists
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	markedAt, exists := s.processed[messageID]
+	return exists && time.Since(markedAt) <= s.ttl
 }
 
-// MarkProcessed marks a message as processed
+// MarkProcessed marks a message as processed, replacing its claim
 func (s *InMemoryMessageStore) MarkProcessed(messageID string) {
-	s.processed[messageID] = time.Now()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.processed[messageID] = now
+	delete(s.claimed, messageID)
+	if now.Sub(s.lastSweep) >= min(s.ttl, inMemorySweepInterval) {
+		s.sweep(now)
+	}
 }
 
-// cleanup removes expired entries
-func (s *InMemoryMessageStore) cleanup() {
-	ticker := time.NewTicker(time.Minute)
-	defer ticker.Stop()
+// Claim records messageID as being processed unless it is claimed or was
+// processed within the TTL
+func (s *InMemoryMessageStore) Claim(messageID string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-	for range ticker.C {
-		now := time.Now()
-		for id, timestamp := range s.processed {
-			if now.Sub(timestamp) > s.ttl {
-				delete(s.processed, id)
-			}
+	if _, exists := s.claimed[messageID]; exists {
+		return false
+	}
+	if markedAt, exists := s.processed[messageID]; exists && time.Since(markedAt) <= s.ttl {
+		return false
+	}
+	s.claimed[messageID] = struct{}{}
+	return true
+}
+
+// Release forgets a claimed message ID
+func (s *InMemoryMessageStore) Release(messageID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.claimed, messageID)
+}
+
+// sweep removes expired entries; the caller holds the write lock
+func (s *InMemoryMessageStore) sweep(now time.Time) {
+	s.lastSweep = now
+	for id, markedAt := range s.processed {
+		if now.Sub(markedAt) > s.ttl {
+			delete(s.processed, id)
 		}
 	}
 }
 
+// Close releases the stored message IDs. It is safe to call more than once.
+func (s *InMemoryMessageStore) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.processed = make(map[string]time.Time)
+	s.claimed = make(map[string]struct{})
+	return nil
+}
+
 // Helper functions
 
 func generateMessageID(delivery *Delivery) string {
@@ -222,9 +336,9 @@ func generateMessageID(delivery *Delivery) string {
 	}
 
 	if timestamp := delivery.Timestamp; !timestamp.IsZero() {
-		return delivery.RoutingKey + "_" + timestamp.Format("20060102150405.000000")
+		return delivery.OriginalRoutingKey() + "_" + timestamp.Format("20060102150405.000000")
 	}
 
 	// Fallback to routing key + current time
-	return delivery.RoutingKey + "_" + time.Now().Format("20060102150405.000000")
+	return delivery.OriginalRoutingKey() + "_" + time.Now().Format("20060102150405.000000")
 }
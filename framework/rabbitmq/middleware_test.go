@@ -0,0 +1,129 @@
+package rabbitmq
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestInMemoryMessageStoreExpiresOnRead(t *testing.T) {
+	store := NewInMemoryMessageStore(20 * time.Millisecond)
+	store.MarkProcessed("a")
+
+	if !store.HasProcessed("a") {
+		t.Fatal("fresh ID not reported as processed")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if store.HasProcessed("a") {
+		t.Error("expired ID still reported as processed")
+	}
+}
+
+func TestInMemoryMessageStoreSweepsExpiredIDs(t *testing.T) {
+	store := NewInMemoryMessageStore(10 * time.Millisecond)
+	store.MarkProcessed("old")
+	time.Sleep(20 * time.Millisecond)
+	store.MarkProcessed("new")
+
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	if _, kept := store.processed["old"]; kept {
+		t.Error("expired ID not swept")
+	}
+	if _, kept := store.processed["new"]; !kept {
+		t.Error("fresh ID swept")
+	}
+}
+
+func TestInMemoryMessageStoreConcurrentUse(t *testing.T) {
+	store := NewInMemoryMessageStore(time.Millisecond)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id := string(rune('a' + i))
+				store.MarkProcessed(id)
+				store.HasProcessed(id)
+			}
+		}(i)
+	}
+	wg.Wait()
+	store.Close()
+}
+
+func TestDeduplicationSkipsProcessedMessages(t *testing.T) {
+	var calls int
+	handler := WithDeduplication(time.Minute)(func(d *Delivery) error {
+		calls++
+		if calls == 1 {
+			return errTest
+		}
+		return nil
+	})
+
+	delivery := &Delivery{Delivery: &amqp.Delivery{MessageId: "order-1"}}
+	handler(delivery) // fails, so it is not marked
+	handler(delivery)
+	handler(delivery) // duplicate
+
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+}
+
+// markingStore is a MessageStore without Claim
+type markingStore map[string]bool
+
+func (s markingStore) HasProcessed(id string) bool { return s[id] }
+func (s markingStore) MarkProcessed(id string)     { s[id] = true }
+
+func TestDeduplicationWithPlainStore(t *testing.T) {
+	store := markingStore{}
+	var calls int
+	handler := WithDeduplication(time.Minute, store)(func(d *Delivery) error {
+		calls++
+		return nil
+	})
+
+	delivery := &Delivery{Delivery: &amqp.Delivery{MessageId: "order-1"}}
+	handler(delivery)
+	handler(delivery)
+
+	if calls != 1 || !store["order-1"] {
+		t.Errorf("handler ran %d times, marked = %v", calls, store["order-1"])
+	}
+}
+
+func TestInMemoryClaimIsExclusive(t *testing.T) {
+	store := NewInMemoryMessageStore(time.Minute)
+	if !store.Claim("a") || store.Claim("a") {
+		t.Fatal("claim not exclusive")
+	}
+	store.Release("a")
+	if !store.Claim("a") {
+		t.Error("released ID could not be claimed again")
+	}
+}
+
+func TestDeduplicationRequeuesClaimedMessage(t *testing.T) {
+	store := NewInMemoryMessageStore(time.Minute)
+	handler := DeduplicationMiddleware(store)(func(d *Delivery) error { return nil })
+	delivery := &Delivery{Delivery: &amqp.Delivery{MessageId: "order-1"}}
+
+	store.Claim("order-1")
+	if store.HasProcessed("order-1") {
+		t.Fatal("claimed ID reported as processed")
+	}
+	if err := handler(delivery); err != ErrMessageInProgress {
+		t.Fatalf("duplicate of a claimed message = %v, want ErrMessageInProgress", err)
+	}
+
+	store.MarkProcessed("order-1")
+	if err := handler(delivery); err != nil {
+		t.Errorf("duplicate of a processed message = %v, want it skipped", err)
+	}
+}
@@ -10,18 +10,25 @@ import (
 
 // Publisher handles message publishing to RabbitMQ
 type Publisher struct {
-	conn         *Connection
-	exchange     string
-	exchangeType string
-	durable      bool
-	autoDelete   bool
-	internal     bool
-	noWait       bool
-	args         amqp.Table
+	conn           *Connection
+	name           string
+	exchange       string
+	exchangeType   string
+	durable        bool
+	autoDelete     bool
+	internal       bool
+	noWait         bool
+	args           amqp.Table
+	confirm        bool
+	mandatory      bool
+	confirmTimeout time.Duration
 }
 
 // PublisherConfig holds publisher configuration
 type PublisherConfig struct {
+	// Name identifies the publisher in Stats()
+	Name string
+
 	Exchange     string
 	ExchangeType string
 	Durable      bool
@@ -29,6 +36,20 @@ type PublisherConfig struct {
 	Internal     bool
 	NoWait       bool
 	Args         amqp.Table
+
+	// Confirm puts the publishing channel into confirm mode, so Publish
+	// waits for the broker to acknowledge each message and returns an error
+	// wrapping ErrPublishNacked or ErrConfirmTimeout when it doesn't.
+	Confirm bool
+
+	// Mandatory asks the broker to return messages that no queue is bound to
+	// receive; Publish then fails with ErrMessageReturned. It implies Confirm,
+	// since the broker's ack is what says no return is coming.
+	Mandatory bool
+
+	// ConfirmTimeout bounds how long Publish waits for a confirmation
+	// (default 5s)
+	ConfirmTimeout time.Duration
 }
 
 // Message represents a message to be published
@@ -62,14 +83,21 @@ func NewPublisher(conn *Connection, config *PublisherConfig) (*Publisher, error)
 	}
 
 	publisher := &Publisher{
-		conn:         conn,
-		exchange:     config.Exchange,
-		exchangeType: config.ExchangeType,
-		durable:      config.Durable,
-		autoDelete:   config.AutoDelete,
-		internal:     config.Internal,
-		noWait:       config.NoWait,
-		args:         config.Args,
+		conn:           conn,
+		name:           config.Name,
+		exchange:       config.Exchange,
+		exchangeType:   config.ExchangeType,
+		durable:        config.Durable,
+		autoDelete:     config.AutoDelete,
+		internal:       config.Internal,
+		noWait:         config.NoWait,
+		args:           config.Args,
+		confirm:        config.Confirm || config.Mandatory,
+		mandatory:      config.Mandatory,
+		confirmTimeout: config.ConfirmTimeout,
+	}
+	if publisher.confirmTimeout <= 0 {
+		publisher.confirmTimeout = defaultConfirmTimeout
 	}
 
 	// Declare exchange if auto-declare is enabled
@@ -82,6 +110,16 @@ func NewPublisher(conn *Connection, config *PublisherConfig) (*Publisher, error)
 	return publisher, nil
 }
 
+// Name returns the publisher name, if any
+func (p *Publisher) Name() string {
+	return p.name
+}
+
+// Exchange returns the exchange messages are published to
+func (p *Publisher) Exchange() string {
+	return p.exchange
+}
+
 // declareExchange declares the exchange
 func (p *Publisher) declareExchange() error {
 	ch, err := p.conn.NewChannel()
@@ -101,7 +139,8 @@ func (p *Publisher) declareExchange() error {
 	)
 }
 
-// Publish publishes a message
+// Publish publishes a message. With Confirm or Mandatory set it returns
+// only once the broker has accepted the message.
 func (p *Publisher) Publish(message *Message) error {
 	ch, err := p.conn.NewChannel()
 	if err != nil {
@@ -109,8 +148,33 @@ func (p *Publisher) Publish(message *Message) error {
 	}
 	defer ch.Close()
 
+	if p.confirm {
+		return p.publishConfirmed(ch, message)
+	}
+	return p.publishOn(ch, message)
+}
+
+// publishOn publishes a message on the given channel
+func (p *Publisher) publishOn(ch *amqp.Channel, message *Message) error {
+	publishing, err := buildPublishing(message)
+	if err != nil {
+		return err
+	}
+
+	return ch.Publish(
+		p.exchange,         // exchange
+		message.RoutingKey, // routing key
+		false,              // mandatory
+		false,              // immediate
+		publishing,         // message
+	)
+}
+
+// buildPublishing serializes a message and fills in its defaults
+func buildPublishing(message *Message) (amqp.Publishing, error) {
 	// Serialize message body
 	var body []byte
+	var err error
 	switch v := message.Body.(type) {
 	case []byte:
 		body = v
@@ -119,7 +183,7 @@ func (p *Publisher) Publish(message *Message) error {
 	default:
 		body, err = json.Marshal(v)
 		if err != nil {
-			return fmt.Errorf("failed to serialize message body: %w", err)
+			return amqp.Publishing{}, fmt.Errorf("failed to serialize message body: %w", err)
 		}
 		if message.ContentType == "" {
 			message.ContentType = "application/json"
@@ -156,14 +220,7 @@ func (p *Publisher) Publish(message *Message) error {
 		publishing.DeliveryMode = 2
 	}
 
-	// Publish the message
-	return ch.Publish(
-		p.exchange,         // exchange
-		message.RoutingKey, // routing key
-		false,              // mandatory
-		false,              // immediate
-		publishing,         // message
-	)
+	return publishing, nil
 }
 
 // PublishJSON publishes a JSON message
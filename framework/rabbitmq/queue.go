@@ -28,6 +28,12 @@ type QueueConfig struct {
 	Exclusive  bool
 	NoWait     bool
 	Args       amqp.Table
+
+	// Exchange and Bindings bind the queue to Exchange once for every
+	// routing key pattern in Bindings, e.g. "events.user.*" on a topic
+	// exchange, when the queue is created
+	Exchange string
+	Bindings []string
 }
 
 // QueueInfo holds information about a queue
@@ -67,6 +73,14 @@ func NewQueue(conn *Connection, config *QueueConfig) (*Queue, error) {
 		}
 	}
 
+	if config.Exchange != "" {
+		for _, pattern := range config.Bindings {
+			if err := queue.Bind(config.Exchange, pattern, nil); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrQueueBindFailed, err)
+			}
+		}
+	}
+
 	return queue, nil
 }
 
@@ -233,15 +247,82 @@ func (q *Queue) PushDelayed(data interface{}, delay time.Duration) error {
 	return publisher.PublishDelayed(q.name, data, delay)
 }
 
-// Pop pops a single message from the queue
+// PushDelayedTTL pushes a delayed message to the queue without the delayed-message
+// plugin. The message is parked in a "<queue>.delay.<bucket>" queue whose
+// x-message-ttl equals the bucket and whose dead-letter target is this queue.
+// Delays are rounded up to a bucket (see delayBucket) to limit the number of
+// delay queues.
+func (q *Queue) PushDelayedTTL(data interface{}, delay time.Duration) error {
+	bucket, label := delayBucket(delay)
+	if bucket <= 0 {
+		return q.Push(data)
+	}
+
+	delayQueue, err := declareDelayQueue(q.conn, q.name, bucket, label)
+	if err != nil {
+		return err
+	}
+
+	return delayQueue.Push(data)
+}
+
+// declareDelayQueue declares the delay queue whose messages dead-letter to
+// queue after bucket
+func declareDelayQueue(conn *Connection, queue string, bucket time.Duration, label string) (*Queue, error) {
+	delayQueue := &Queue{
+		conn:    conn,
+		name:    fmt.Sprintf("%s.delay.%s", queue, label),
+		durable: true,
+		args: amqp.Table{
+			"x-message-ttl":             bucket.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": queue,
+		},
+	}
+	if err := delayQueue.Declare(); err != nil {
+		return nil, fmt.Errorf("failed to declare delay queue: %w", err)
+	}
+	return delayQueue, nil
+}
+
+// delayBucket rounds a delay up to whole seconds below one minute, whole
+// minutes below one hour and whole hours above, returning the bucket and its
+// queue name suffix
+func delayBucket(delay time.Duration) (time.Duration, string) {
+	switch {
+	case delay <= 0:
+		return 0, ""
+	case delay <= time.Minute:
+		bucket := roundUp(delay, time.Second)
+		return bucket, fmt.Sprintf("%ds", bucket/time.Second)
+	case delay <= time.Hour:
+		bucket := roundUp(delay, time.Minute)
+		return bucket, fmt.Sprintf("%dm", bucket/time.Minute)
+	default:
+		bucket := roundUp(delay, time.Hour)
+		return bucket, fmt.Sprintf("%dh", bucket/time.Hour)
+	}
+}
+
+// roundUp rounds d up to a multiple of unit
+func roundUp(d, unit time.Duration) time.Duration {
+	if rem := d % unit; rem != 0 {
+		return d + unit - rem
+	}
+	return d
+}
+
+// Pop pops a single message from the queue, returning nil when it is empty.
+// Without autoAck the message is fetched on its own channel, which stays open
+// until the delivery is acked, nacked or rejected; call Close to give up an
+// unsettled delivery. Every popped delivery must be settled or closed, or its
+// channel leaks.
 func (q *Queue) Pop(autoAck bool) (*Delivery, error) {
 	ch, err := q.conn.NewChannel()
 	if err != nil {
 		return nil, err
 	}
 
-	// Don't defer close here as the delivery might need the channel
-
 	delivery, ok, err := ch.Get(q.name, autoAck)
 	if err != nil {
 		ch.Close()
@@ -253,10 +334,21 @@ func (q *Queue) Pop(autoAck bool) (*Delivery, error) {
 		return nil, nil // No message available
 	}
 
-	return &Delivery{
+	d := &Delivery{
 		Delivery: &delivery,
 		ctx:      context.Background(),
-	}, nil
+		conn:     q.conn,
+		queue:    q.name,
+	}
+
+	// An auto-acked message is already settled; otherwise the channel stays
+	// open until the delivery is acked, nacked, rejected or closed
+	if autoAck {
+		ch.Close()
+	} else {
+		d.release = ch.Close
+	}
+	return d, nil
 }
 
 // Listen starts listening for messages with a simple callback
@@ -289,6 +381,81 @@ func (q *Queue) ListenWithWorkers(ctx context.Context, workers int, handler func
 	return consumer.Start(ctx)
 }
 
+// drainPollInterval is how often DrainWithGrace polls an empty queue
+const drainPollInterval = 100 * time.Millisecond
+
+// Drain processes messages until the queue is empty and returns the number
+// of messages handled. Each message is acked after the handler succeeds,
+// unless the handler settled it itself. If the handler fails, the message is
+// requeued and draining stops with the error.
+// Unlike Listen, Drain returns instead of blocking, which suits one-shot and
+// cron-triggered jobs.
+func (q *Queue) Drain(ctx context.Context, handler func(*Delivery) error) (int, error) {
+	return q.DrainWithGrace(ctx, 0, handler)
+}
+
+// DrainWithGrace is like Drain, but keeps polling an empty queue for the
+// grace period so messages published while draining are processed too.
+// The grace period restarts after every message.
+func (q *Queue) DrainWithGrace(ctx context.Context, grace time.Duration, handler func(*Delivery) error) (int, error) {
+	ch, err := q.conn.NewChannel()
+	if err != nil {
+		return 0, err
+	}
+	defer ch.Close()
+
+	processed := 0
+	emptySince := time.Time{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+
+		delivery, ok, err := ch.Get(q.name, false)
+		if err != nil {
+			return processed, err
+		}
+
+		if !ok {
+			if emptySince.IsZero() {
+				emptySince = time.Now()
+			}
+			if time.Since(emptySince) >= grace {
+				return processed, nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return processed, ctx.Err()
+			case <-time.After(drainPollInterval):
+			}
+			continue
+		}
+		emptySince = time.Time{}
+
+		d := &Delivery{Delivery: &delivery, ctx: ctx, conn: q.conn, queue: q.name}
+		if err := drainDelivery(d, handler); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+}
+
+// drainDelivery runs the handler, then acks the delivery or requeues it if
+// the handler failed. A delivery the handler settled itself is left alone.
+func drainDelivery(d *Delivery, handler func(*Delivery) error) error {
+	if err := handler(d); err != nil {
+		if !d.Settled() {
+			d.Nack(false, true)
+		}
+		return err
+	}
+	if d.Settled() {
+		return nil
+	}
+	return d.Ack(false)
+}
+
 // Name returns the queue name
 func (q *Queue) Name() string {
 	return q.name
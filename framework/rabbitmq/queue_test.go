@@ -0,0 +1,190 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDelayBucketRoundsUp(t *testing.T) {
+	tests := []struct {
+		delay  time.Duration
+		bucket time.Duration
+		label  string
+	}{
+		{0, 0, ""},
+		{-time.Second, 0, ""},
+		{1500 * time.Millisecond, 2 * time.Second, "2s"},
+		{30 * time.Second, 30 * time.Second, "30s"},
+		{time.Minute, time.Minute, "60s"},
+		{61 * time.Second, 2 * time.Minute, "2m"},
+		{time.Hour, time.Hour, "60m"},
+		{90 * time.Minute, 2 * time.Hour, "2h"},
+	}
+	for _, tt := range tests {
+		bucket, label := delayBucket(tt.delay)
+		if bucket != tt.bucket || label != tt.label {
+			t.Errorf("delayBucket(%v) = %v, %q; want %v, %q", tt.delay, bucket, label, tt.bucket, tt.label)
+		}
+	}
+}
+
+// TestPushDelayedTTLIntegration needs a broker; set RABBITMQ_TEST_URL to run it
+func TestPushDelayedTTLIntegration(t *testing.T) {
+	url := os.Getenv("RABBITMQ_TEST_URL")
+	if url == "" {
+		t.Skip("RABBITMQ_TEST_URL not set")
+	}
+
+	rabbit, err := Connect(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rabbit.Close()
+
+	name := fmt.Sprintf("golara_test_delay_%d", time.Now().UnixNano())
+	queue, err := rabbit.Queue(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Delete(false, false)
+
+	if err := rabbit.PushDelayedTTL(name, map[string]string{"hello": "later"}, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	delayQueue, err := declareDelayQueue(queue.conn, name, time.Second, "1s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer delayQueue.Delete(false, false)
+
+	if d, err := rabbit.Pop(name); err != nil || d != nil {
+		t.Fatalf("message arrived before its delay: %v, %v", d, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		d, err := rabbit.Pop(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d != nil {
+			var body map[string]string
+			if err := d.JSON(&body); err != nil || body["hello"] != "later" {
+				t.Errorf("body = %v, %v", body, err)
+			}
+			d.Ack(false)
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatal("delayed message never arrived")
+}
+
+func TestDrainWithoutConnection(t *testing.T) {
+	queue := &Queue{conn: testConnection(), name: "jobs"}
+	n, err := queue.Drain(context.Background(), func(*Delivery) error { return nil })
+	if err == nil || n != 0 {
+		t.Errorf("Drain = %d, %v; want an error", n, err)
+	}
+}
+
+func TestDrainDeliverySettlesOnce(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(*Delivery) error
+		want    ackRecord
+	}{
+		{"acked by drain", func(*Delivery) error { return nil }, ackRecord{method: "ack", tag: 1}},
+		{"requeued by drain", func(*Delivery) error { return errTest }, ackRecord{method: "nack", tag: 1, requeue: true}},
+		{"acked by handler", func(d *Delivery) error { return d.Ack(false) }, ackRecord{method: "ack", tag: 1}},
+		{"rejected by handler", func(d *Delivery) error { return d.Reject(false) }, ackRecord{method: "reject", tag: 1}},
+		{"nacked by failing handler", func(d *Delivery) error {
+			d.Nack(false, false)
+			return errTest
+		}, ackRecord{method: "nack", tag: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ack := &fakeAcknowledger{}
+			delivery := testDelivery(ack, 1, "jobs", nil)
+			drainDelivery(&Delivery{Delivery: &delivery}, tt.handler)
+
+			if records := ack.all(); len(records) != 1 || records[0] != tt.want {
+				t.Errorf("settlements = %+v, want only %+v", records, tt.want)
+			}
+		})
+	}
+}
+
+// TestDrainIntegration needs a broker; set RABBITMQ_TEST_URL to run it
+func TestDrainIntegration(t *testing.T) {
+	url := os.Getenv("RABBITMQ_TEST_URL")
+	if url == "" {
+		t.Skip("RABBITMQ_TEST_URL not set")
+	}
+
+	rabbit, err := Connect(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rabbit.Close()
+
+	name := fmt.Sprintf("golara_test_drain_%d", time.Now().UnixNano())
+	queue, err := rabbit.Queue(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Delete(false, false)
+
+	for i := 0; i < 5; i++ {
+		if err := queue.Push(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var seen []int
+	n, err := queue.Drain(context.Background(), func(d *Delivery) error {
+		var value int
+		if err := d.JSON(&value); err != nil {
+			return err
+		}
+		seen = append(seen, value)
+		return nil
+	})
+	if err != nil || n != 5 {
+		t.Fatalf("Drain = %d, %v; want 5 messages", n, err)
+	}
+	for i, value := range seen {
+		if value != i {
+			t.Errorf("drained %v, want 0..4 in order", seen)
+			break
+		}
+	}
+
+	// A handler that acks a message itself is not acked twice, which would
+	// close the channel with PRECONDITION_FAILED
+	for i := 0; i < 2; i++ {
+		if err := queue.Push(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n, err = queue.Drain(context.Background(), func(d *Delivery) error { return d.Ack(false) })
+	if err != nil || n != 2 {
+		t.Fatalf("Drain with handler acks = %d, %v; want 2 messages", n, err)
+	}
+
+	// A failing handler stops draining and leaves the message queued
+	if err := queue.Push(99); err != nil {
+		t.Fatal(err)
+	}
+	n, err = queue.Drain(context.Background(), func(*Delivery) error { return errTest })
+	if err != errTest || n != 0 {
+		t.Errorf("failing Drain = %d, %v", n, err)
+	}
+	if count, err := queue.Count(); err != nil || count != 1 {
+		t.Errorf("queue holds %d messages after a failed drain, want 1 (%v)", count, err)
+	}
+}
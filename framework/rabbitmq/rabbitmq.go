@@ -3,16 +3,20 @@ package rabbitmq
 
 import (
 	"context"
+	"log"
 	"time"
 )
 
-// RabbitMQ provides a simple interface for common RabbitMQ operations
+// RabbitMQ provides a simple interface for common RabbitMQ operations.
+// A RabbitMQ without a manager is a no-op instance (see Optional): pushes and
+// publishes are logged and dropped, everything else returns ErrServiceUnavailable.
 type RabbitMQ struct {
 	manager *Manager
 }
 
 // Config represents the configuration for RabbitMQ
 type RabbitMQConfig struct {
+	Name                string `json:"name" yaml:"name"`
 	URL                 string `json:"url" yaml:"url"`
 	ReconnectDelay      string `json:"reconnect_delay" yaml:"reconnect_delay"`
 	ReconnectAttempts   int    `json:"reconnect_attempts" yaml:"reconnect_attempts"`
@@ -45,6 +49,7 @@ func New(config *RabbitMQConfig) (*RabbitMQ, error) {
 
 	// Convert to manager config
 	managerConfig := &ManagerConfig{
+		Name:                config.Name,
 		URL:                 config.URL,
 		ReconnectDelay:      config.ReconnectDelay,
 		ReconnectAttempts:   config.ReconnectAttempts,
@@ -76,25 +81,51 @@ func Connect(url string) (*RabbitMQ, error) {
 
 // Queue gets or creates a queue
 func (r *RabbitMQ) Queue(name string) (*Queue, error) {
+	if r.manager == nil {
+		return nil, ErrServiceUnavailable
+	}
 	return r.manager.Queue(name, nil)
 }
 
 // QueueWithConfig gets or creates a queue with custom configuration
 func (r *RabbitMQ) QueueWithConfig(name string, config *QueueConfig) (*Queue, error) {
+	if r.manager == nil {
+		return nil, ErrServiceUnavailable
+	}
 	return r.manager.Queue(name, config)
 }
 
 // Push pushes data to a queue
 func (r *RabbitMQ) Push(queueName string, data interface{}) error {
+	if r.manager == nil {
+		return r.drop("push to queue", queueName)
+	}
 	return r.manager.PublishToQueue(queueName, data)
 }
 
 // PushJob pushes a job to a queue
 func (r *RabbitMQ) PushJob(queueName, jobType string, payload interface{}) error {
+	if r.manager == nil {
+		return r.drop("push job to queue", queueName)
+	}
 	return r.manager.PublishJob(queueName, jobType, payload)
 }
 
-// Pop pops a message from a queue
+// PushDelayedTTL pushes data to a queue after a delay, using a TTL queue with a
+// dead-letter route instead of the delayed-message plugin
+func (r *RabbitMQ) PushDelayedTTL(queueName string, data interface{}, delay time.Duration) error {
+	if r.manager == nil {
+		return r.drop("push delayed to queue", queueName)
+	}
+	queue, err := r.Queue(queueName)
+	if err != nil {
+		return err
+	}
+	return queue.PushDelayedTTL(data, delay)
+}
+
+// Pop pops a message from a queue. The delivery must be acked, nacked,
+// rejected or closed to release its channel (see Queue.Pop).
 func (r *RabbitMQ) Pop(queueName string) (*Delivery, error) {
 	queue, err := r.Queue(queueName)
 	if err != nil {
@@ -107,11 +138,17 @@ func (r *RabbitMQ) Pop(queueName string) (*Delivery, error) {
 
 // Publish publishes a message to an exchange
 func (r *RabbitMQ) Publish(exchange, routingKey string, data interface{}) error {
+	if r.manager == nil {
+		return r.drop("publish to exchange", exchange)
+	}
 	return r.manager.Publish(exchange, routingKey, data)
 }
 
 // PublishString publishes a string message
 func (r *RabbitMQ) PublishString(exchange, routingKey, data string) error {
+	if r.manager == nil {
+		return r.drop("publish to exchange", exchange)
+	}
 	publisher, err := r.manager.Publisher(exchange, nil)
 	if err != nil {
 		return err
@@ -121,6 +158,9 @@ func (r *RabbitMQ) PublishString(exchange, routingKey, data string) error {
 
 // PublishBytes publishes raw bytes
 func (r *RabbitMQ) PublishBytes(exchange, routingKey string, data []byte) error {
+	if r.manager == nil {
+		return r.drop("publish to exchange", exchange)
+	}
 	publisher, err := r.manager.Publisher(exchange, nil)
 	if err != nil {
 		return err
@@ -128,15 +168,30 @@ func (r *RabbitMQ) PublishBytes(exchange, routingKey string, data []byte) error
 	return publisher.PublishBytes(routingKey, data)
 }
 
+// Request publishes payload to a queue and waits for the correlated JSON
+// response, decoding it into dest (see Manager.Request)
+func (r *RabbitMQ) Request(ctx context.Context, queueName string, payload interface{}, dest interface{}) error {
+	if r.manager == nil {
+		return ErrServiceUnavailable
+	}
+	return r.manager.Request(ctx, queueName, payload, dest)
+}
+
 // Consumer operations
 
 // Listen starts listening to a queue with a simple callback
 func (r *RabbitMQ) Listen(ctx context.Context, queueName string, handler func(*Delivery) error) error {
+	if r.manager == nil {
+		return ErrServiceUnavailable
+	}
 	return r.manager.Consume(ctx, queueName, handler)
 }
 
 // ListenWithWorkers starts listening with multiple workers
 func (r *RabbitMQ) ListenWithWorkers(ctx context.Context, queueName string, workers int, handler func(*Delivery) error) error {
+	if r.manager == nil {
+		return ErrServiceUnavailable
+	}
 	config := &ConsumerConfig{
 		Queue:       queueName,
 		Concurrency: workers,
@@ -145,8 +200,24 @@ func (r *RabbitMQ) ListenWithWorkers(ctx context.Context, queueName string, work
 	return r.manager.ConsumeWithConfig(ctx, config, handler)
 }
 
+// ListenBatch starts listening to a queue, calling handler with batches of up
+// to batchSize messages or whatever arrived within maxWait (see Consumer.StartBatch)
+func (r *RabbitMQ) ListenBatch(ctx context.Context, queueName string, batchSize int, maxWait time.Duration, handler BatchHandler) error {
+	if r.manager == nil {
+		return ErrServiceUnavailable
+	}
+	consumer, err := r.manager.Consumer(queueName, nil)
+	if err != nil {
+		return err
+	}
+	return consumer.StartBatch(ctx, batchSize, maxWait, handler)
+}
+
 // ListenForJobs starts listening for jobs with type-based routing
 func (r *RabbitMQ) ListenForJobs(ctx context.Context, queueName string, handlers map[string]MessageHandler) error {
+	if r.manager == nil {
+		return ErrServiceUnavailable
+	}
 	return r.manager.ConsumeJobs(ctx, queueName, handlers)
 }
 
@@ -154,16 +225,25 @@ func (r *RabbitMQ) ListenForJobs(ctx context.Context, queueName string, handlers
 
 // CreateConsumer creates a consumer with advanced configuration
 func (r *RabbitMQ) CreateConsumer(config *ConsumerConfig) (*Consumer, error) {
+	if r.manager == nil {
+		return nil, ErrServiceUnavailable
+	}
 	return r.manager.Consumer(config.Queue, config)
 }
 
 // CreatePublisher creates a publisher with advanced configuration
 func (r *RabbitMQ) CreatePublisher(config *PublisherConfig) (*Publisher, error) {
+	if r.manager == nil {
+		return nil, ErrServiceUnavailable
+	}
 	return r.manager.Publisher(config.Exchange, config)
 }
 
 // DeclareExchange declares an exchange
 func (r *RabbitMQ) DeclareExchange(name, exchangeType string, durable bool) error {
+	if r.manager == nil {
+		return ErrServiceUnavailable
+	}
 	config := &ExchangeConfig{
 		Name:       name,
 		Type:       exchangeType,
@@ -175,29 +255,79 @@ func (r *RabbitMQ) DeclareExchange(name, exchangeType string, durable bool) erro
 	return r.manager.DeclareExchange(config)
 }
 
+// DeclareDeadLetter declares a dead-letter exchange and queue pair
+// (see DeclareDeadLetter)
+func (r *RabbitMQ) DeclareDeadLetter(exchange, queue string) error {
+	if r.manager == nil {
+		return ErrServiceUnavailable
+	}
+	return DeclareDeadLetter(r.manager.Connection(), exchange, queue)
+}
+
 // Utility methods
 
+// BindQueue binds a queue to an exchange with a routing key or topic pattern
+func (r *RabbitMQ) BindQueue(queueName, exchange, pattern string) error {
+	if r.manager == nil {
+		return ErrServiceUnavailable
+	}
+	return r.manager.BindQueueToExchange(queueName, exchange, pattern)
+}
+
+// BindExchange routes messages from source to destination by routing key or
+// topic pattern
+func (r *RabbitMQ) BindExchange(destination, source, pattern string) error {
+	if r.manager == nil {
+		return ErrServiceUnavailable
+	}
+	return r.manager.ExchangeBind(destination, source, pattern)
+}
+
 // IsConnected checks if the connection is active
 func (r *RabbitMQ) IsConnected() bool {
+	if r.manager == nil {
+		return false
+	}
 	return r.manager.IsConnected()
 }
 
 // Health checks the health of the connection
 func (r *RabbitMQ) Health() error {
+	if r.manager == nil {
+		return ErrServiceUnavailable
+	}
 	return r.manager.Health()
 }
 
 // Stats returns connection statistics
 func (r *RabbitMQ) Stats() map[string]interface{} {
+	if r.manager == nil {
+		return map[string]interface{}{"connected": false, "noop": true}
+	}
 	return r.manager.Stats()
 }
 
 // Close closes all connections and resources
 func (r *RabbitMQ) Close() error {
+	if r.manager == nil {
+		return nil
+	}
 	return r.manager.Close()
 }
 
-// Manager returns the underlying manager for advanced operations
+// IsNoop reports whether this is a no-op instance returned by Optional
+func (r *RabbitMQ) IsNoop() bool {
+	return r.manager == nil
+}
+
+// drop logs and discards a message sent through a no-op instance
+func (r *RabbitMQ) drop(operation, target string) error {
+	log.Printf("RabbitMQ: Service unavailable, dropped %s '%s'", operation, target)
+	return nil
+}
+
+// Manager returns the underlying manager for advanced operations.
+// It returns nil for no-op instances.
 func (r *RabbitMQ) Manager() *Manager {
 	return r.manager
 }
@@ -234,8 +364,25 @@ func WithValidation(validator func(*Delivery) error) MiddlewareFunc {
 	return ValidationMiddleware(validator)
 }
 
-// WithDeduplication adds deduplication middleware
-func WithDeduplication(ttl time.Duration) MiddlewareFunc {
-	store := NewInMemoryMessageStore(ttl)
-	return DeduplicationMiddleware(store)
+// WithJSONSchema adds JSON Schema validation middleware. It panics if the
+// schema is invalid; use CompileJSONSchema with JSONSchemaMiddleware to handle
+// the error instead.
+func WithJSONSchema(schema string) MiddlewareFunc {
+	compiled, err := CompileJSONSchema(schema)
+	if err != nil {
+		panic(err)
+	}
+	return JSONSchemaMiddleware(compiled)
+}
+
+// WithDeduplication adds deduplication middleware. Processed message IDs are
+// kept in memory for ttl unless a store, such as a
+// redisstore.RedisMessageStore, is given; the store then applies its own
+// TTL. The in-memory store runs no background goroutine, so nothing needs to
+// be closed when the consumer stops.
+func WithDeduplication(ttl time.Duration, store ...MessageStore) MiddlewareFunc {
+	if len(store) > 0 {
+		return DeduplicationMiddleware(store[0])
+	}
+	return DeduplicationMiddleware(NewInMemoryMessageStore(ttl))
 }
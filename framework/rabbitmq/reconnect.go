@@ -0,0 +1,139 @@
+package rabbitmq
+
+import (
+	"context"
+	"log"
+)
+
+// NotifyReconnect returns a channel that receives true every time the
+// connection is re-established after being lost. Notifications are not
+// queued: a slow reader sees one value for several reconnects. The channel
+// is closed when the connection is closed.
+func (c *Connection) NotifyReconnect() <-chan bool {
+	c.listenersMux.Lock()
+	defer c.listenersMux.Unlock()
+
+	ch := make(chan bool, 1)
+	if c.listenersClosed {
+		close(ch)
+		return ch
+	}
+	c.reconnectListeners = append(c.reconnectListeners, ch)
+	return ch
+}
+
+// stopNotifyReconnect unregisters and closes a NotifyReconnect channel
+func (c *Connection) stopNotifyReconnect(listener <-chan bool) {
+	c.listenersMux.Lock()
+	defer c.listenersMux.Unlock()
+
+	for i, ch := range c.reconnectListeners {
+		if ch == listener {
+			c.reconnectListeners = append(c.reconnectListeners[:i], c.reconnectListeners[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
+
+// notifyReconnected signals every NotifyReconnect listener
+func (c *Connection) notifyReconnected() {
+	c.listenersMux.Lock()
+	defer c.listenersMux.Unlock()
+
+	for _, ch := range c.reconnectListeners {
+		select {
+		case ch <- true:
+		default:
+		}
+	}
+}
+
+// closeReconnectListeners closes every NotifyReconnect channel
+func (c *Connection) closeReconnectListeners() {
+	c.listenersMux.Lock()
+	defer c.listenersMux.Unlock()
+
+	for _, ch := range c.reconnectListeners {
+		close(ch)
+	}
+	c.reconnectListeners = nil
+	c.listenersClosed = true
+}
+
+// watchReconnect re-declares the consumer's queue after a reconnect, since a
+// restarted broker loses non-durable queues, and wakes workers waiting to
+// retry so they resume consuming at once. It returns a function that stops
+// watching.
+func (c *Consumer) watchReconnect(ctx context.Context) (stop func()) {
+	reconnected := c.conn.NotifyReconnect()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-reconnected:
+				if !ok {
+					return
+				}
+			}
+
+			log.Printf("%s: Connection re-established, resuming queue '%s'", c.logPrefix(), c.queue)
+			if c.conn.config.AutoDeclareQueues {
+				if err := c.declareQueue(); err != nil {
+					log.Printf("%s: Failed to re-declare queue '%s': %v", c.logPrefix(), c.queue, err)
+				}
+			}
+			for i := 0; i < cap(c.wake); i++ {
+				select {
+				case c.wake <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return func() {
+		c.conn.stopNotifyReconnect(reconnected)
+	}
+}
+
+// watchReconnect re-declares the exchanges of the manager's publishers and
+// its queues after a reconnect, until the connection is closed
+func (m *Manager) watchReconnect() {
+	for range m.conn.NotifyReconnect() {
+		if !m.conn.config.AutoDeclareExchange && !m.conn.config.AutoDeclareQueues {
+			continue
+		}
+
+		m.mutex.RLock()
+		publishers := make([]*Publisher, 0, len(m.publishers))
+		for _, publisher := range m.publishers {
+			publishers = append(publishers, publisher)
+		}
+		queues := make([]*Queue, 0, len(m.queues))
+		for _, queue := range m.queues {
+			queues = append(queues, queue)
+		}
+		m.mutex.RUnlock()
+
+		if m.conn.config.AutoDeclareExchange {
+			for _, publisher := range publishers {
+				if publisher.exchange == "" {
+					continue
+				}
+				if err := publisher.declareExchange(); err != nil {
+					log.Printf("%s: Failed to re-declare exchange '%s': %v", m.conn.logPrefix(), publisher.exchange, err)
+				}
+			}
+		}
+		if m.conn.config.AutoDeclareQueues {
+			for _, queue := range queues {
+				if err := queue.Declare(); err != nil {
+					log.Printf("%s: Failed to re-declare queue '%s': %v", m.conn.logPrefix(), queue.name, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package redisstore
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client is the subset of Redis commands the store needs. Dial returns a
+// built-in implementation; an existing client such as go-redis can be
+// adapted instead:
+//
+//	type goRedis struct{ *redis.Client }
+//
+//	func (c goRedis) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+//	    return c.Client.SetNX(ctx, key, value, ttl).Result()
+//	}
+//
+//	func (c goRedis) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+//	    return c.Client.Set(ctx, key, value, ttl).Err()
+//	}
+//
+//	func (c goRedis) Get(ctx context.Context, key string) (string, bool, error) {
+//	    value, err := c.Client.Get(ctx, key).Result()
+//	    if err == redis.Nil {
+//	        return "", false, nil
+//	    }
+//	    return value, err == nil, err
+//	}
+//
+//	func (c goRedis) Del(ctx context.Context, key string) error {
+//	    return c.Client.Del(ctx, key).Err()
+//	}
+type Client interface {
+	// SetNX sets key to value with a time to live unless it exists,
+	// reporting whether it was set
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+
+	// Set sets key to value with a time to live
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+
+	// Get returns the value of key, reporting whether it exists
+	Get(ctx context.Context, key string) (string, bool, error)
+
+	// Del deletes key
+	Del(ctx context.Context, key string) error
+
+	Close() error
+}
+
+// dialTimeout bounds connecting to Redis and each command
+const dialTimeout = 5 * time.Second
+
+// redisError is an error reply from the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// conn is a minimal RESP client using a single connection. Commands are
+// serialized; a connection that fails is dropped and redialed on next use.
+type conn struct {
+	addr     string
+	password string
+	db       int
+
+	mu      sync.Mutex
+	netConn net.Conn
+	reader  *bufio.Reader
+}
+
+// Dial connects to Redis at a URL such as redis://:password@localhost:6379/0
+func Dial(rawURL string) (Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	if u.Scheme != "redis" {
+		return nil, fmt.Errorf("invalid redis url: unsupported scheme '%s'", u.Scheme)
+	}
+
+	c := &conn{addr: u.Host}
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if password, ok := u.User.Password(); ok {
+		c.password = password
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if c.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid redis database '%s'", db)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SetNX runs SET key value NX PX ttl
+func (c *conn) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	reply, err := c.do(ctx, "SET", key, value, "NX", "PX", pxMillis(ttl))
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+// Set runs SET key value PX ttl
+func (c *conn) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	_, err := c.do(ctx, "SET", key, value, "PX", pxMillis(ttl))
+	return err
+}
+
+// Get runs GET key
+func (c *conn) Get(ctx context.Context, key string) (string, bool, error) {
+	reply, err := c.do(ctx, "GET", key)
+	if err != nil || reply == nil {
+		return "", false, err
+	}
+	value, _ := reply.(string)
+	return value, true, nil
+}
+
+// Del runs DEL key
+func (c *conn) Del(ctx context.Context, key string) error {
+	_, err := c.do(ctx, "DEL", key)
+	return err
+}
+
+// Close closes the connection
+func (c *conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.netConn == nil {
+		return nil
+	}
+	err := c.netConn.Close()
+	c.netConn = nil
+	return err
+}
+
+// connect dials the server, authenticating and selecting the database.
+// The caller holds c.mu.
+func (c *conn) connect() error {
+	netConn, err := net.DialTimeout("tcp", c.addr, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	netConn.SetDeadline(time.Now().Add(dialTimeout))
+	c.netConn, c.reader = netConn, bufio.NewReader(netConn)
+
+	if c.password != "" {
+		if _, err := c.roundTrip("AUTH", c.password); err != nil {
+			c.drop()
+			return err
+		}
+	}
+	if c.db != 0 {
+		if _, err := c.roundTrip("SELECT", strconv.Itoa(c.db)); err != nil {
+			c.drop()
+			return err
+		}
+	}
+	return nil
+}
+
+// do runs a command, connecting first if needed
+func (c *conn) do(ctx context.Context, args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.netConn == nil {
+		if err := c.connect(); err != nil {
+			return nil, err
+		}
+	}
+
+	deadline := time.Now().Add(dialTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	c.netConn.SetDeadline(deadline)
+
+	reply, err := c.roundTrip(args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		// The connection state is unknown after an I/O error
+		c.drop()
+	}
+	return reply, err
+}
+
+// pxMillis formats ttl for PX, rounding up to whole milliseconds; Redis
+// rejects PX 0
+func pxMillis(ttl time.Duration) string {
+	ms := (ttl + time.Millisecond - 1) / time.Millisecond
+	return strconv.FormatInt(int64(max(ms, 1)), 10)
+}
+
+// drop closes a broken connection so the next command redials
+func (c *conn) drop() {
+	c.netConn.Close()
+	c.netConn = nil
+}
+
+// roundTrip writes a command as a RESP array and reads its reply
+func (c *conn) roundTrip(args ...string) (interface{}, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.netConn, b.String()); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// readReply reads one RESP reply: a string, int64, nil, []interface{} or
+// redisError
+func (c *conn) readReply() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply '%s'", line)
+	}
+}
@@ -0,0 +1,241 @@
+package redisstore
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// closeConn tells the fake server to drop the connection instead of replying
+const closeConn = "CLOSE"
+
+// fakeServer is a RESP server answering commands with replies from a
+// handler, which runs under mu
+type fakeServer struct {
+	listener net.Listener
+	handler  func(args []string) string
+
+	mu       sync.Mutex
+	commands [][]string
+	accepted int
+}
+
+func newFakeServer(t *testing.T, handler func(args []string) string) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeServer{listener: listener, handler: handler}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeServer) url() string {
+	return "redis://" + s.listener.Addr().String()
+}
+
+func (s *fakeServer) serve() {
+	for {
+		netConn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.accepted++
+		s.mu.Unlock()
+		go s.handle(netConn)
+	}
+}
+
+func (s *fakeServer) handle(netConn net.Conn) {
+	defer netConn.Close()
+	reader := bufio.NewReader(netConn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, args)
+		reply := s.handler(args)
+		s.mu.Unlock()
+
+		if reply == closeConn {
+			return
+		}
+		io.WriteString(netConn, reply)
+	}
+}
+
+func (s *fakeServer) connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted
+}
+
+// readCommand reads a RESP array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func dialFake(t *testing.T, s *fakeServer) Client {
+	t.Helper()
+	client, err := Dial(s.url())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestSetNXReplies(t *testing.T) {
+	var existing bool
+	s := newFakeServer(t, func(args []string) string {
+		if existing {
+			return "$-1\r\n"
+		}
+		existing = true
+		return "+OK\r\n"
+	})
+	client := dialFake(t, s)
+
+	set, err := client.SetNX(context.Background(), "k", "v", time.Second)
+	if err != nil || !set {
+		t.Fatalf("first SetNX = %v, %v; want true", set, err)
+	}
+	set, err = client.SetNX(context.Background(), "k", "v", time.Second)
+	if err != nil || set {
+		t.Errorf("SetNX on a null reply = %v, %v; want false", set, err)
+	}
+}
+
+func TestSetSendsMillisecondTTL(t *testing.T) {
+	s := newFakeServer(t, func(args []string) string { return "+OK\r\n" })
+	client := dialFake(t, s)
+
+	ttls := map[time.Duration]string{
+		time.Second:             "1000",
+		1500 * time.Microsecond: "2",
+		time.Microsecond:        "1",
+		0:                       "1",
+	}
+	for ttl, want := range ttls {
+		if err := client.Set(context.Background(), "k", "v", ttl); err != nil {
+			t.Fatal(err)
+		}
+		s.mu.Lock()
+		args := s.commands[len(s.commands)-1]
+		s.mu.Unlock()
+		if got := args[len(args)-1]; args[len(args)-2] != "PX" || got != want {
+			t.Errorf("Set with ttl %v sent %v, want PX %s", ttl, args, want)
+		}
+	}
+}
+
+func TestGetMissingKey(t *testing.T) {
+	s := newFakeServer(t, func(args []string) string { return "$-1\r\n" })
+	client := dialFake(t, s)
+
+	value, exists, err := client.Get(context.Background(), "k")
+	if err != nil || exists || value != "" {
+		t.Errorf("Get on a null reply = %q, %v, %v; want not found", value, exists, err)
+	}
+}
+
+func TestErrorReplyKeepsConnection(t *testing.T) {
+	s := newFakeServer(t, func(args []string) string {
+		if args[0] == "GET" {
+			return "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"
+		}
+		return ":1\r\n"
+	})
+	client := dialFake(t, s)
+
+	_, _, err := client.Get(context.Background(), "k")
+	var replyErr redisError
+	if !errors.As(err, &replyErr) || !strings.HasPrefix(string(replyErr), "WRONGTYPE") {
+		t.Fatalf("err = %v, want the server's error reply", err)
+	}
+	if err := client.Del(context.Background(), "k"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.connections(); got != 1 {
+		t.Errorf("connections = %d, want 1 after an error reply", got)
+	}
+}
+
+func TestReconnectAfterDrop(t *testing.T) {
+	var calls int
+	s := newFakeServer(t, func(args []string) string {
+		calls++
+		if calls == 1 {
+			return closeConn
+		}
+		return "$1\r\n1\r\n"
+	})
+	client := dialFake(t, s)
+
+	if _, _, err := client.Get(context.Background(), "k"); err == nil {
+		t.Fatal("expected an error when the server drops the connection")
+	}
+	value, exists, err := client.Get(context.Background(), "k")
+	if err != nil || !exists || value != "1" {
+		t.Fatalf("Get after reconnect = %q, %v, %v", value, exists, err)
+	}
+	if got := s.connections(); got != 2 {
+		t.Errorf("connections = %d, want 2", got)
+	}
+}
+
+func TestDialAuthenticatesAndSelects(t *testing.T) {
+	s := newFakeServer(t, func(args []string) string { return "+OK\r\n" })
+	client, err := Dial(fmt.Sprintf("redis://:secret@%s/3", s.listener.Addr()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.commands) != 2 || s.commands[0][0] != "AUTH" || s.commands[0][1] != "secret" ||
+		s.commands[1][0] != "SELECT" || s.commands[1][1] != "3" {
+		t.Errorf("commands = %v, want AUTH secret then SELECT 3", s.commands)
+	}
+}
+
+func TestDialRejectsBadURLs(t *testing.T) {
+	for _, url := range []string{"http://localhost", "redis://localhost/db"} {
+		if _, err := Dial(url); err == nil {
+			t.Errorf("Dial(%q) succeeded", url)
+		}
+	}
+}
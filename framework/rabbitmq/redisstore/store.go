@@ -0,0 +1,135 @@
+// Package redisstore provides a Redis-backed rabbitmq.MessageStore, so
+// message deduplication survives restarts and is shared between instances.
+package redisstore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Config holds optional store settings
+type Config struct {
+	// Prefix is prepended to message IDs to form Redis keys
+	// (default "golara:dedup:")
+	Prefix string
+
+	// Lease is how long a claim lasts while its message is being processed
+	// (default 1m, at most the TTL). A message still being handled when its
+	// lease expires may be processed again by another consumer.
+	Lease time.Duration
+
+	// Timeout bounds each Redis command (default 2s)
+	Timeout time.Duration
+}
+
+// Values stored under a message ID's key
+const (
+	leasedValue    = "processing"
+	processedValue = "processed"
+)
+
+// RedisMessageStore records processed message IDs in Redis with a time to
+// live. It implements rabbitmq.ClaimingMessageStore: a message ID is claimed
+// with SET NX and a short lease before the message is processed, kept for
+// the TTL once processing succeeds and released with DEL if it fails.
+// Consumers on other instances requeue it while the lease is held, so a
+// claim left by a consumer that crashed mid-processing expires with the
+// lease and the redelivered message is processed again.
+type RedisMessageStore struct {
+	client  Client
+	ttl     time.Duration
+	lease   time.Duration
+	prefix  string
+	timeout time.Duration
+}
+
+// New creates a store keeping message IDs for ttl, which must be positive
+//
+//	client, err := redisstore.Dial("redis://localhost:6379/0")
+//	...
+//	consumer.Use(rabbitmq.WithDeduplication(time.Hour, redisstore.New(client, time.Hour)))
+func New(client Client, ttl time.Duration, config ...Config) *RedisMessageStore {
+	if ttl <= 0 {
+		panic(fmt.Sprintf("redisstore: invalid ttl %v", ttl))
+	}
+
+	cfg := Config{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "golara:dedup:"
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = time.Minute
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Second
+	}
+
+	return &RedisMessageStore{
+		client:  client,
+		ttl:     ttl,
+		lease:   min(cfg.Lease, ttl),
+		prefix:  cfg.Prefix,
+		timeout: cfg.Timeout,
+	}
+}
+
+// HasProcessed reports whether the message ID was processed; an ID that is
+// only claimed is not. Redis errors are logged and reported as not
+// processed, so an outage doesn't drop messages.
+func (s *RedisMessageStore) HasProcessed(messageID string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	value, _, err := s.client.Get(ctx, s.prefix+messageID)
+	if err != nil {
+		log.Printf("Redis message store: failed to check message %s: %v", messageID, err)
+		return false
+	}
+	return value == processedValue
+}
+
+// Claim leases the message ID with SET NX, reporting whether it was neither
+// claimed nor processed yet. Redis errors are logged and the message is
+// claimed, so an outage doesn't drop messages.
+func (s *RedisMessageStore) Claim(messageID string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	claimed, err := s.client.SetNX(ctx, s.prefix+messageID, leasedValue, s.lease)
+	if err != nil {
+		log.Printf("Redis message store: failed to claim message %s: %v", messageID, err)
+		return true
+	}
+	return claimed
+}
+
+// Release deletes a claimed message ID so a redelivery is processed
+func (s *RedisMessageStore) Release(messageID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if err := s.client.Del(ctx, s.prefix+messageID); err != nil {
+		log.Printf("Redis message store: failed to release message %s: %v", messageID, err)
+	}
+}
+
+// MarkProcessed records the message ID as processed for the store's TTL,
+// replacing its claim
+func (s *RedisMessageStore) MarkProcessed(messageID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if err := s.client.Set(ctx, s.prefix+messageID, processedValue, s.ttl); err != nil {
+		log.Printf("Redis message store: failed to mark message %s: %v", messageID, err)
+	}
+}
+
+// Close closes the Redis client
+func (s *RedisMessageStore) Close() error {
+	return s.client.Close()
+}
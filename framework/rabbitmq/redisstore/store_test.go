@@ -0,0 +1,194 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/taeyelor/golara/framework/rabbitmq"
+)
+
+var _ rabbitmq.ClaimingMessageStore = (*RedisMessageStore)(nil)
+
+// memoryClient is an in-memory Client whose keys expire
+type memoryClient struct {
+	mu   sync.Mutex
+	keys map[string]memoryKey
+	err  error
+}
+
+type memoryKey struct {
+	value     string
+	expiresAt time.Time
+}
+
+func newMemoryClient() *memoryClient {
+	return &memoryClient{keys: make(map[string]memoryKey)}
+}
+
+// get returns a live key; the caller holds c.mu
+func (c *memoryClient) get(key string) (memoryKey, bool) {
+	k, ok := c.keys[key]
+	if ok && !time.Now().Before(k.expiresAt) {
+		delete(c.keys, key)
+		return memoryKey{}, false
+	}
+	return k, ok
+}
+
+func (c *memoryClient) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return false, c.err
+	}
+	if _, ok := c.get(key); ok {
+		return false, nil
+	}
+	c.keys[key] = memoryKey{value: value, expiresAt: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (c *memoryClient) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.keys[key] = memoryKey{value: value, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (c *memoryClient) Get(ctx context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return "", false, c.err
+	}
+	k, ok := c.get(key)
+	return k.value, ok, nil
+}
+
+func (c *memoryClient) Del(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.keys, key)
+	return c.err
+}
+
+func (c *memoryClient) Close() error { return nil }
+
+// ttl returns how long key has left to live
+func (c *memoryClient) ttl(key string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	k, _ := c.get(key)
+	return time.Until(k.expiresAt)
+}
+
+func TestClaimIsExclusive(t *testing.T) {
+	client := newMemoryClient()
+	store := New(client, time.Hour)
+
+	if !store.Claim("m1") {
+		t.Fatal("first claim failed")
+	}
+	if store.Claim("m1") {
+		t.Error("second claim succeeded")
+	}
+	if _, ok := client.keys["golara:dedup:m1"]; !ok {
+		t.Error("claim not stored under the default prefix")
+	}
+}
+
+func TestReleaseAllowsReclaim(t *testing.T) {
+	store := New(newMemoryClient(), time.Hour, Config{Prefix: "test:"})
+	store.Claim("m1")
+	store.Release("m1")
+
+	if !store.Claim("m1") {
+		t.Error("released ID could not be claimed again")
+	}
+}
+
+func TestClaimProcessesDuringOutage(t *testing.T) {
+	client := newMemoryClient()
+	client.err = errors.New("connection refused")
+	store := New(client, time.Hour)
+
+	if !store.Claim("m1") {
+		t.Error("claim refused while Redis is down; the message would be dropped")
+	}
+	if store.HasProcessed("m1") {
+		t.Error("HasProcessed true while Redis is down")
+	}
+}
+
+func TestClaimIsALeaseUntilProcessed(t *testing.T) {
+	client := newMemoryClient()
+	store := New(client, time.Hour, Config{Lease: time.Minute})
+
+	store.Claim("m1")
+	if store.HasProcessed("m1") {
+		t.Error("claimed ID reported as processed")
+	}
+	if left := client.ttl("golara:dedup:m1"); left > time.Minute {
+		t.Errorf("claim lives %v, want at most the 1m lease", left)
+	}
+
+	store.MarkProcessed("m1")
+	if !store.HasProcessed("m1") {
+		t.Error("processed ID not reported")
+	}
+	if left := client.ttl("golara:dedup:m1"); left <= time.Minute {
+		t.Errorf("processed ID lives %v, want the 1h TTL", left)
+	}
+}
+
+func TestLeaseOfCrashedConsumerExpires(t *testing.T) {
+	store := New(newMemoryClient(), time.Hour, Config{Lease: 20 * time.Millisecond})
+	handler := rabbitmq.DeduplicationMiddleware(store)
+
+	// The first consumer claims the message and crashes mid-handler
+	store.Claim("m1")
+
+	var calls int
+	redelivered := handler(func(d *rabbitmq.Delivery) error {
+		calls++
+		return nil
+	})
+	delivery := &rabbitmq.Delivery{Delivery: &amqp.Delivery{MessageId: "m1"}}
+
+	if err := redelivered(delivery); !errors.Is(err, rabbitmq.ErrMessageInProgress) {
+		t.Fatalf("redelivery during the lease = %v, want ErrMessageInProgress so it is requeued", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if err := redelivered(delivery); err != nil || calls != 1 {
+		t.Fatalf("redelivery after the lease = %v with %d calls, want it processed", err, calls)
+	}
+	if err := redelivered(delivery); err != nil || calls != 1 {
+		t.Errorf("duplicate after processing = %v with %d calls, want it skipped", err, calls)
+	}
+}
+
+func TestLeaseIsCappedByTTL(t *testing.T) {
+	client := newMemoryClient()
+	store := New(client, time.Second)
+
+	store.Claim("m1")
+	if left := client.ttl("golara:dedup:m1"); left > time.Second {
+		t.Errorf("claim lives %v, want at most the 1s TTL", left)
+	}
+}
+
+func TestNewRejectsInvalidTTL(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("New accepted a zero TTL")
+		}
+	}()
+	New(newMemoryClient(), 0)
+}
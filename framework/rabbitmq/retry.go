@@ -0,0 +1,102 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// originalRoutingKeyHeader carries a message's first routing key across
+// retries, which are republished through the default exchange with the
+// queue name as routing key
+const originalRoutingKeyHeader = "x-original-routing-key"
+
+// OriginalRoutingKey returns the routing key the message was first published
+// with. It differs from RoutingKey on messages republished by WithRetry.
+func (d *Delivery) OriginalRoutingKey() string {
+	if key, ok := d.GetStringHeader(originalRoutingKeyHeader); ok && key != "" {
+		return key
+	}
+	return d.RoutingKey
+}
+
+// republish publishes a copy of the delivery to the queue it was consumed
+// from after delay, with x-retry-count set to retryCount. It returns once the
+// broker has confirmed the copy, so the original can be acked safely.
+func (d *Delivery) republish(retryCount int, delay time.Duration) error {
+	if d.conn == nil || d.queue == "" {
+		return fmt.Errorf("delivery has no source queue to republish to")
+	}
+
+	routingKey := d.queue
+	if bucket, label := delayBucket(delay); bucket > 0 {
+		delayQueue, err := declareDelayQueue(d.conn, d.queue, bucket, label)
+		if err != nil {
+			return err
+		}
+		routingKey = delayQueue.name
+	}
+
+	ch, err := d.conn.NewChannel()
+	if err != nil {
+		return fmt.Errorf("failed to get channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := ch.Confirm(false); err != nil {
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	returns := ch.NotifyReturn(make(chan amqp.Return, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultConfirmTimeout)
+	defer cancel()
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, "", routingKey, true, false, d.retryPublishing(retryCount))
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("%w after %v (routing key '%s')", ErrConfirmTimeout, defaultConfirmTimeout, routingKey)
+	}
+	if !acked {
+		return fmt.Errorf("%w (routing key '%s')", ErrPublishNacked, routingKey)
+	}
+
+	select {
+	case ret := <-returns:
+		return fmt.Errorf("%w: %d %s (routing key '%s')", ErrMessageReturned, ret.ReplyCode, ret.ReplyText, ret.RoutingKey)
+	default:
+		return nil
+	}
+}
+
+// retryPublishing copies the delivery for a retry, recording the retry
+// count and the original routing key in its headers
+func (d *Delivery) retryPublishing(retryCount int) amqp.Publishing {
+	headers := make(amqp.Table, len(d.Headers)+2)
+	for key, value := range d.Headers {
+		headers[key] = value
+	}
+	headers["x-retry-count"] = int32(retryCount)
+	headers[originalRoutingKeyHeader] = d.OriginalRoutingKey()
+
+	return amqp.Publishing{
+		Headers:         headers,
+		ContentType:     d.ContentType,
+		ContentEncoding: d.ContentEncoding,
+		DeliveryMode:    d.DeliveryMode,
+		Priority:        d.Priority,
+		CorrelationId:   d.CorrelationId,
+		ReplyTo:         d.ReplyTo,
+		MessageId:       d.MessageId,
+		Timestamp:       d.Timestamp,
+		Type:            d.Type,
+		UserId:          d.UserId,
+		AppId:           d.AppId,
+		Body:            d.Body,
+	}
+}
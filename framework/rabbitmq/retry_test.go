@@ -0,0 +1,79 @@
+package rabbitmq
+
+import (
+	"context"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestRetryPublishingKeepsOriginalRoutingKey(t *testing.T) {
+	d := &Delivery{Delivery: &amqp.Delivery{
+		RoutingKey: "order.created",
+		Headers:    amqp.Table{"tenant": "acme"},
+		MessageId:  "m-1",
+		Body:       []byte("payload"),
+	}}
+
+	first := d.retryPublishing(1)
+	if got := first.Headers[originalRoutingKeyHeader]; got != "order.created" {
+		t.Errorf("%s = %v, want order.created", originalRoutingKeyHeader, got)
+	}
+	if got := first.Headers["x-retry-count"]; got != int32(1) {
+		t.Errorf("x-retry-count = %v, want 1", got)
+	}
+	if first.Headers["tenant"] != "acme" || first.MessageId != "m-1" || string(first.Body) != "payload" {
+		t.Errorf("retry copy lost message fields: %+v", first)
+	}
+
+	// The retried copy arrives through the default exchange with the queue
+	// name as routing key; a second retry must keep the first key
+	redelivered := &Delivery{Delivery: &amqp.Delivery{RoutingKey: "orders", Headers: first.Headers}}
+	if got := redelivered.OriginalRoutingKey(); got != "order.created" {
+		t.Errorf("OriginalRoutingKey = %q, want order.created", got)
+	}
+	if got := redelivered.retryPublishing(2).Headers[originalRoutingKeyHeader]; got != "order.created" {
+		t.Errorf("second retry %s = %v, want order.created", originalRoutingKeyHeader, got)
+	}
+}
+
+func TestRetriedDeliveryDispatchesOnOriginalRoutingKey(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "orders", Concurrency: 1})
+
+	var handled int
+	consumer.Handle("order.created", func(d *Delivery) error {
+		handled++
+		return nil
+	})
+
+	ack := &fakeAcknowledger{}
+	delivery := testDelivery(ack, 7, "orders", amqp.Table{
+		originalRoutingKeyHeader: "order.created",
+		"x-retry-count":          int32(1),
+	})
+	consumer.process(context.Background(), delivery)
+
+	if handled != 1 {
+		t.Fatalf("handler ran %d times, want 1", handled)
+	}
+	if got := ack.all(); len(got) != 1 || got[0].method != "ack" || got[0].tag != 7 {
+		t.Errorf("settlements = %+v, want a single ack of tag 7", got)
+	}
+}
+
+func TestRetryRequeuesWhenRepublishFails(t *testing.T) {
+	consumer := newTestConsumer(t, &ConsumerConfig{Queue: "orders", Concurrency: 1})
+	consumer.Use(RetryMiddleware(3, 0))
+	consumer.Handle("order.created", func(d *Delivery) error {
+		return errTest
+	})
+
+	// The connection is down, so the retry cannot be republished and the
+	// consumer must requeue the message rather than ack it
+	ack := &fakeAcknowledger{}
+	consumer.process(context.Background(), testDelivery(ack, 1, "order.created", nil))
+
+	if got := ack.all(); len(got) != 1 || got[0].method != "nack" || !got[0].requeue {
+		t.Errorf("settlements = %+v, want a requeueing nack", got)
+	}
+}
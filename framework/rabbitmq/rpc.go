@@ -0,0 +1,157 @@
+package rabbitmq
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// rpcErrorHeader carries a responder's error message back to the requester
+const rpcErrorHeader = "x-rpc-error"
+
+// directReplyTo is RabbitMQ's pseudo-queue for replies sent straight back to
+// the consuming channel, without declaring a reply queue per request
+const directReplyTo = "amq.rabbitmq.reply-to"
+
+// Request publishes payload as JSON to a queue and waits for the correlated
+// response, which is decoded into dest (dest may be nil). Replies come back
+// through RabbitMQ's direct reply-to, so no reply queue is declared. The
+// request is published as mandatory: when no queue receives it Request fails
+// with ErrMessageReturned instead of waiting. It returns ctx.Err() when the
+// context ends first, so give ctx a deadline in case the responder never
+// answers, and an error wrapping ErrRequestFailed when the responder
+// reported one.
+func (m *Manager) Request(ctx context.Context, queue string, payload interface{}, dest interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to serialize request: %w", err)
+	}
+
+	ch, err := m.conn.NewChannel()
+	if err != nil {
+		return fmt.Errorf("failed to get channel: %w", err)
+	}
+	defer ch.Close()
+
+	// Direct reply-to requires consuming in no-ack mode before publishing
+	replies, err := ch.Consume(directReplyTo, "", true, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume replies: %w", err)
+	}
+	returns := ch.NotifyReturn(make(chan amqp.Return, 1))
+
+	correlationID := newCorrelationID()
+	err = ch.PublishWithContext(ctx, "", queue, true, false, amqp.Publishing{
+		ContentType:   "application/json",
+		CorrelationId: correlationID,
+		ReplyTo:       directReplyTo,
+		Body:          body,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+
+	return awaitReply(ctx, correlationID, replies, returns, dest)
+}
+
+// awaitReply waits for the reply with the given correlation ID, or for the
+// request to be returned as unroutable
+func awaitReply(ctx context.Context, correlationID string, replies <-chan amqp.Delivery, returns <-chan amqp.Return, dest interface{}) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case returned, ok := <-returns:
+			if !ok {
+				returns = nil
+				continue
+			}
+			if returned.CorrelationId != correlationID {
+				continue
+			}
+			return fmt.Errorf("%w: %d %s (routing key '%s')", ErrMessageReturned, returned.ReplyCode, returned.ReplyText, returned.RoutingKey)
+		case reply, ok := <-replies:
+			if !ok {
+				return ErrChannelClosed
+			}
+			if reply.CorrelationId != correlationID {
+				continue
+			}
+			if message, failed := reply.Headers[rpcErrorHeader].(string); failed {
+				return fmt.Errorf("%w: %s", ErrRequestFailed, message)
+			}
+			if dest == nil {
+				return nil
+			}
+			return json.Unmarshal(reply.Body, dest)
+		}
+	}
+}
+
+// Reply publishes payload as JSON to the delivery's reply-to queue with its
+// correlation ID
+func (d *Delivery) Reply(payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to serialize reply: %w", err)
+	}
+	return d.reply(amqp.Publishing{ContentType: "application/json", Body: body})
+}
+
+// reply publishes a response to the requester
+func (d *Delivery) reply(publishing amqp.Publishing) error {
+	if d.ReplyTo == "" {
+		return fmt.Errorf("%w: delivery has no reply-to queue", ErrInvalidMessage)
+	}
+	if d.conn == nil {
+		return fmt.Errorf("delivery has no connection to reply on")
+	}
+
+	ch, err := d.conn.NewChannel()
+	if err != nil {
+		return fmt.Errorf("failed to get channel: %w", err)
+	}
+	defer ch.Close()
+
+	publishing.CorrelationId = d.CorrelationId
+	return ch.PublishWithContext(context.Background(), "", d.ReplyTo, false, false, publishing)
+}
+
+// RespondWith adapts a request handler to a MessageHandler that replies with
+// its result. A returned error is sent back to the requester, where Request
+// fails with ErrRequestFailed, and the request is acknowledged rather than
+// requeued. Deliveries without a reply-to queue are handled fire-and-forget.
+//
+//	consumer.HandleAll(rabbitmq.RespondWith(func(d *rabbitmq.Delivery) (interface{}, error) {
+//	    var req PriceRequest
+//	    if err := d.JSON(&req); err != nil {
+//	        return nil, err
+//	    }
+//	    return prices.Quote(req)
+//	}))
+func RespondWith(handler func(*Delivery) (interface{}, error)) MessageHandler {
+	return func(delivery *Delivery) error {
+		result, err := handler(delivery)
+		if delivery.ReplyTo == "" {
+			return err
+		}
+
+		if err != nil {
+			return delivery.reply(amqp.Publishing{
+				Headers: amqp.Table{rpcErrorHeader: err.Error()},
+			})
+		}
+		return delivery.Reply(result)
+	}
+}
+
+// newCorrelationID returns a random correlation ID for a request
+func newCorrelationID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
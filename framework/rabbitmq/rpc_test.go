@@ -0,0 +1,75 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestAwaitReplyDecodesMatchingReply(t *testing.T) {
+	replies := make(chan amqp.Delivery, 2)
+	replies <- amqp.Delivery{CorrelationId: "other", Body: []byte(`{"price":1}`)}
+	replies <- amqp.Delivery{CorrelationId: "abc", Body: []byte(`{"price":42}`)}
+
+	var quote struct{ Price int }
+	if err := awaitReply(context.Background(), "abc", replies, nil, &quote); err != nil {
+		t.Fatal(err)
+	}
+	if quote.Price != 42 {
+		t.Errorf("price = %d, want 42", quote.Price)
+	}
+}
+
+func TestAwaitReplyResponderError(t *testing.T) {
+	replies := make(chan amqp.Delivery, 1)
+	replies <- amqp.Delivery{CorrelationId: "abc", Headers: amqp.Table{rpcErrorHeader: "out of stock"}}
+
+	err := awaitReply(context.Background(), "abc", replies, nil, nil)
+	if !errors.Is(err, ErrRequestFailed) {
+		t.Errorf("err = %v, want ErrRequestFailed", err)
+	}
+}
+
+func TestAwaitReplyFailsFastWhenUnroutable(t *testing.T) {
+	returns := make(chan amqp.Return, 1)
+	returns <- amqp.Return{CorrelationId: "abc", ReplyCode: 312, ReplyText: "NO_ROUTE", RoutingKey: "pricing.rpc"}
+
+	err := awaitReply(context.Background(), "abc", make(chan amqp.Delivery), returns, nil)
+	if !errors.Is(err, ErrMessageReturned) {
+		t.Errorf("err = %v, want ErrMessageReturned", err)
+	}
+}
+
+func TestAwaitReplyHonoursContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := awaitReply(ctx, "abc", make(chan amqp.Delivery), make(chan amqp.Return), nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestAwaitReplyChannelClosed(t *testing.T) {
+	replies := make(chan amqp.Delivery)
+	returns := make(chan amqp.Return)
+	close(replies)
+	close(returns)
+
+	err := awaitReply(context.Background(), "abc", replies, returns, nil)
+	if !errors.Is(err, ErrChannelClosed) {
+		t.Errorf("err = %v, want ErrChannelClosed", err)
+	}
+}
+
+func TestRespondWithoutReplyToIsFireAndForget(t *testing.T) {
+	handler := RespondWith(func(d *Delivery) (interface{}, error) {
+		return nil, errTest
+	})
+	if err := handler(&Delivery{Delivery: &amqp.Delivery{}}); !errors.Is(err, errTest) {
+		t.Errorf("err = %v, want the handler's error", err)
+	}
+}
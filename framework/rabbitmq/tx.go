@@ -0,0 +1,63 @@
+package rabbitmq
+
+import (
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Tx publishes messages inside an AMQP transaction
+type Tx struct {
+	publisher *Publisher
+	ch        *amqp.Channel
+}
+
+// Tx runs fn inside an AMQP transaction. Messages published through tx are
+// delivered only if fn returns nil; otherwise (or if fn panics) the
+// transaction is rolled back and nothing is published.
+//
+// Transactions are considerably slower than publisher confirms, but they are
+// simpler when a batch of messages must be published all-or-nothing.
+func (p *Publisher) Tx(fn func(tx *Tx) error) (err error) {
+	ch, err := p.conn.NewChannel()
+	if err != nil {
+		return fmt.Errorf("failed to get channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := ch.Tx(); err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			ch.TxRollback()
+		}
+	}()
+
+	if err := fn(&Tx{publisher: p, ch: ch}); err != nil {
+		return err
+	}
+
+	if err := ch.TxCommit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// Publish publishes a message as part of the transaction
+func (tx *Tx) Publish(message *Message) error {
+	return tx.publisher.publishOn(tx.ch, message)
+}
+
+// PublishJSON publishes a persistent JSON message as part of the transaction
+func (tx *Tx) PublishJSON(routingKey string, data interface{}) error {
+	return tx.Publish(&Message{
+		Body:        data,
+		RoutingKey:  routingKey,
+		ContentType: "application/json",
+		Persistent:  true,
+	})
+}
@@ -0,0 +1,86 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTxWithoutConnection(t *testing.T) {
+	publisher := &Publisher{conn: testConnection()}
+	called := false
+	err := publisher.Tx(func(*Tx) error {
+		called = true
+		return nil
+	})
+	if err == nil || called {
+		t.Errorf("Tx = %v, callback called = %v; want an error before the callback", err, called)
+	}
+}
+
+// TestTxCommitAndRollback needs a broker; set RABBITMQ_TEST_URL to run it
+func TestTxCommitAndRollback(t *testing.T) {
+	url := os.Getenv("RABBITMQ_TEST_URL")
+	if url == "" {
+		t.Skip("RABBITMQ_TEST_URL not set")
+	}
+
+	config := DefaultConfig()
+	config.AutoDeclareExchange = false
+	conn, err := NewConnection(url, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.NewChannel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue := fmt.Sprintf("golara_test_tx_%d", time.Now().UnixNano())
+	if _, err := ch.QueueDeclare(queue, false, true, false, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	count := func() int {
+		q, err := ch.QueueDeclarePassive(queue, false, true, false, false, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return q.Messages
+	}
+
+	// The default exchange routes by queue name
+	publisher, err := NewPublisher(conn, &PublisherConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = publisher.Tx(func(tx *Tx) error {
+		if err := tx.PublishJSON(queue, "first"); err != nil {
+			return err
+		}
+		return errTest
+	})
+	if err != errTest {
+		t.Fatalf("rolled back Tx = %v, want errTest", err)
+	}
+	if n := count(); n != 0 {
+		t.Fatalf("rolled back transaction published %d messages", n)
+	}
+
+	err = publisher.Tx(func(tx *Tx) error {
+		for i := 0; i < 3; i++ {
+			if err := tx.PublishJSON(queue, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("committed Tx = %v", err)
+	}
+	if n := count(); n != 3 {
+		t.Errorf("committed transaction published %d messages, want 3", n)
+	}
+}
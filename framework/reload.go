@@ -0,0 +1,69 @@
+package framework
+
+import (
+	"log"
+
+	"github.com/taeyelor/golara/framework/config"
+)
+
+// EnableConfigReload loads a config file and makes Run reload the
+// configuration on SIGHUP: defaults, loaded files and environment variables
+// are re-applied, cookie keys and CORS settings are refreshed, and
+// OnConfigReload callbacks are invoked. Server timeouts change only while
+// the server is not running.
+func (app *Application) EnableConfigReload(filename string) error {
+	if filename != "" {
+		if err := app.Config.LoadFromFile(filename); err != nil {
+			return err
+		}
+	}
+
+	app.reloadMux.Lock()
+	defer app.reloadMux.Unlock()
+
+	app.configReload = true
+	return nil
+}
+
+// OnConfigReload registers a callback invoked after the configuration is
+// reloaded, whether by SIGHUP, ReloadConfig, a file watched with
+// Config.WatchFile or a provider added with Config.AddProvider
+func (app *Application) OnConfigReload(callback func(*config.Config)) {
+	app.reloadMux.Lock()
+	defer app.reloadMux.Unlock()
+
+	app.reloadCallbacks = append(app.reloadCallbacks, callback)
+}
+
+// ReloadConfig reloads the configuration and invokes the OnConfigReload
+// callbacks. The current configuration is kept if reloading fails.
+func (app *Application) ReloadConfig() error {
+	return app.Config.Reload()
+}
+
+// configReloaded re-applies the settings read from the configuration (cookie
+// keys, CORS and server timeouts) and invokes the OnConfigReload callbacks;
+// NewApplication registers it with Config.OnReload
+func (app *Application) configReloaded() {
+	app.Router.SetCookieKeys(app.cookieKeys()...)
+	app.applyCORSConfig()
+	app.applyServerTimeouts()
+
+	app.reloadMux.Lock()
+	callbacks := append([]func(*config.Config){}, app.reloadCallbacks...)
+	app.reloadMux.Unlock()
+
+	for _, callback := range callbacks {
+		callback(app.Config)
+	}
+
+	log.Println("Configuration reloaded")
+}
+
+// configReloadEnabled reports whether EnableConfigReload was called
+func (app *Application) configReloadEnabled() bool {
+	app.reloadMux.Lock()
+	defer app.reloadMux.Unlock()
+
+	return app.configReload
+}
@@ -0,0 +1,181 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/taeyelor/golara/framework/config"
+	"github.com/taeyelor/golara/framework/routing"
+)
+
+func TestWatchedFileRunsOnConfigReload(t *testing.T) {
+	defer func(interval time.Duration) { config.WatchInterval = interval }(config.WatchInterval)
+	config.WatchInterval = 10 * time.Millisecond
+
+	filename := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(filename, []byte(`{"log": {"level": "info"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := NewApplication()
+	levels := make(chan string, 1)
+	app.OnConfigReload(func(cfg *config.Config) {
+		select {
+		case levels <- cfg.GetString("log.level"):
+		default:
+		}
+	})
+
+	if err := app.Config.WatchFile(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer app.Config.StopWatching()
+
+	if err := os.WriteFile(filename, []byte(`{"log": {"level": "debug"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case level := <-levels:
+		if level != "debug" {
+			t.Errorf("log.level = %q in OnConfigReload, want debug", level)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnConfigReload was not called after the watched file changed")
+	}
+}
+
+func TestReloadRefreshesCookieKeys(t *testing.T) {
+	t.Setenv("APP_KEY", "first-key")
+	app := NewApplication()
+	app.GET("/set", func(c *routing.Context) { c.SetSecureCookie("session", []byte("v")) })
+	app.GET("/get", func(c *routing.Context) {
+		if _, err := c.SecureCookie("session"); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	app.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/set", nil))
+	cookie := rec.Result().Cookies()[0]
+
+	read := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/get", nil)
+		req.AddCookie(cookie)
+		rec := httptest.NewRecorder()
+		app.Router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Setenv("APP_KEY", "second-key")
+	t.Setenv("APP_PREVIOUS_KEYS", "first-key")
+	if err := app.ReloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if code := read(); code != http.StatusOK {
+		t.Errorf("cookie under a previous key: status %d, want 200", code)
+	}
+
+	t.Setenv("APP_PREVIOUS_KEYS", "")
+	if err := app.ReloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if code := read(); code != http.StatusBadRequest {
+		t.Errorf("cookie under a retired key: status %d, want 400", code)
+	}
+}
+
+func TestReloadTogglesCORS(t *testing.T) {
+	app := NewApplication()
+	app.POST("/orders", func(w http.ResponseWriter, r *http.Request) {})
+
+	if got := preflight(app, "/orders", "https://app.example.com").Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("CORS active before it was enabled: %q", got)
+	}
+
+	t.Setenv("HTTP_CORS_ENABLED", "true")
+	t.Setenv("HTTP_CORS_ORIGINS", "https://app.example.com")
+	if err := app.ReloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if got := preflight(app, "/orders", "https://app.example.com").Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("after enabling: Access-Control-Allow-Origin = %q", got)
+	}
+
+	t.Setenv("HTTP_CORS_ORIGINS", "https://admin.example.com")
+	if err := app.ReloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if got := preflight(app, "/orders", "https://app.example.com").Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("removed origin still allowed: %q", got)
+	}
+}
+
+func TestReloadAppliesServerTimeoutsBeforeRun(t *testing.T) {
+	app := NewApplication()
+	server := app.Server()
+
+	t.Setenv("SERVER_READ_TIMEOUT", "5s")
+	if err := app.ReloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if server.ReadTimeout != 5*time.Second {
+		t.Errorf("ReadTimeout = %v, want 5s", server.ReadTimeout)
+	}
+}
+
+func TestSIGHUPReloadsUntilShutdown(t *testing.T) {
+	// Keep SIGHUP caught by the test so it never terminates the process
+	caught := make(chan os.Signal, 4)
+	signal.Notify(caught, syscall.SIGHUP)
+	defer signal.Stop(caught)
+
+	app := NewApplication()
+	if err := app.EnableConfigReload(""); err != nil {
+		t.Fatal(err)
+	}
+	reloads := make(chan string, 4)
+	app.OnConfigReload(func(cfg *config.Config) {
+		reloads <- cfg.GetString("app.key")
+	})
+
+	addr := freeAddr(t)
+	runErr := make(chan error, 1)
+	go func() { runErr <- app.Run(addr) }()
+	waitListening(t, addr)
+
+	t.Setenv("APP_KEY", "reloaded")
+	syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	select {
+	case key := <-reloads:
+		if key != "reloaded" {
+			t.Errorf("app.key = %q after SIGHUP, want reloaded", key)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("SIGHUP did not reload the configuration")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := app.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-runErr; !errors.Is(err, http.ErrServerClosed) {
+		t.Fatalf("Run = %v", err)
+	}
+
+	syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	select {
+	case <-reloads:
+		t.Error("SIGHUP reloaded the configuration after shutdown")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,240 @@
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/taeyelor/golara/framework/validation"
+)
+
+// Limits applied by BindSlice
+var (
+	// MaxBindBodySize is the maximum request body size in bytes
+	MaxBindBodySize int64 = 10 << 20
+	// MaxBindSliceLength is the maximum number of array elements
+	MaxBindSliceLength = 1000
+)
+
+// BindQuery binds query string parameters to a struct using `query` tags.
+// Fields without a value in the query string fall back to their `default` tag.
+//
+//	type Filters struct {
+//	    Page   int      `query:"page" default:"1"`
+//	    Limit  int      `query:"limit" default:"10"`
+//	    Active bool     `query:"active"`
+//	    Tags   []string `query:"tag"`
+//	}
+func (c *Context) BindQuery(obj interface{}) error {
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("BindQuery requires a non-nil pointer to a struct")
+	}
+
+	query := c.Request.URL.Query()
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := field.Tag.Get("query")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		values := query[name]
+		if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+			def, ok := field.Tag.Lookup("default")
+			if !ok {
+				continue
+			}
+			values = []string{def}
+		}
+
+		if err := setFieldValue(rv.Field(i), values); err != nil {
+			return fmt.Errorf("invalid value for query parameter '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// BindWithDefaults binds a JSON request body to a struct, using `default`
+// tags for fields the client omitted. Defaults are applied before decoding,
+// so values the client sends explicitly, including zero values, are kept.
+// Defaults of nested structs are applied too, pointer fields receive a pointer
+// to their default, and slice defaults are comma-separated. An empty body
+// leaves every field at its default.
+//
+//	type CreatePost struct {
+//	    Title    string   `json:"title"`
+//	    Status   string   `json:"status" default:"draft"`
+//	    Priority *int     `json:"priority" default:"3"`
+//	    Tags     []string `json:"tags" default:"general,news"`
+//	}
+func (c *Context) BindWithDefaults(obj interface{}) error {
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("BindWithDefaults requires a non-nil pointer to a struct")
+	}
+
+	if err := applyDefaults(rv.Elem()); err != nil {
+		return err
+	}
+
+	if err := c.Bind(obj); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// applyDefaults sets every field with a `default` tag, descending into nested structs
+func applyDefaults(rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		field := rv.Field(i)
+		def, ok := sf.Tag.Lookup("default")
+		if !ok {
+			if field.Kind() == reflect.Struct {
+				if err := applyDefaults(field); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := setDefaultValue(field, def); err != nil {
+			return fmt.Errorf("invalid default for field '%s': %w", sf.Name, err)
+		}
+	}
+	return nil
+}
+
+// setDefaultValue assigns a default tag value, allocating pointer fields
+func setDefaultValue(field reflect.Value, def string) error {
+	switch field.Kind() {
+	case reflect.Ptr:
+		value := reflect.New(field.Type().Elem())
+		if err := setDefaultValue(value.Elem(), def); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	case reflect.Slice:
+		return setFieldValue(field, strings.Split(def, ","))
+	}
+	return setScalarValue(field, def)
+}
+
+// setFieldValue converts string values to the field's type and assigns them
+func setFieldValue(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setScalarValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	return setScalarValue(field, values[0])
+}
+
+// setScalarValue converts a single string value to the field's type and assigns it
+func setScalarValue(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid integer", value)
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid unsigned integer", value)
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid number", value)
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// Validate validates an already-populated struct using its `validate` tags.
+// Failed rules are returned as validation.ValidationErrors.
+func (c *Context) Validate(obj interface{}) error {
+	return validation.Struct(obj)
+}
+
+// ValidateVar validates a single value against a rule string, e.g. "required,email"
+func (c *Context) ValidateVar(value interface{}, rules string) error {
+	return validation.Var(value, rules)
+}
+
+// BindSlice binds a top-level JSON array request body into out, which must be
+// a pointer to a slice. The body is capped at MaxBindBodySize bytes and the
+// array at MaxBindSliceLength elements; decoding stops as soon as either cap is
+// exceeded.
+func (c *Context) BindSlice(out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("BindSlice requires a non-nil pointer to a slice")
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, MaxBindBodySize)
+	decoder := json.NewDecoder(body)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("request body must be a JSON array")
+	}
+
+	slice := reflect.MakeSlice(rv.Elem().Type(), 0, 0)
+	elemType := rv.Elem().Type().Elem()
+	for decoder.More() {
+		if slice.Len() >= MaxBindSliceLength {
+			return fmt.Errorf("request array exceeds the maximum of %d elements", MaxBindSliceLength)
+		}
+		elem := reflect.New(elemType)
+		if err := decoder.Decode(elem.Interface()); err != nil {
+			return err
+		}
+		slice = reflect.Append(slice, elem.Elem())
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+
+	rv.Elem().Set(slice)
+	return nil
+}
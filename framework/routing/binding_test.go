@@ -0,0 +1,126 @@
+package routing
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/taeyelor/golara/framework/validation"
+)
+
+func TestContextValidate(t *testing.T) {
+	type signup struct {
+		Name string `json:"name" validate:"required,min=3"`
+		Age  int    `json:"age" validate:"min=18"`
+	}
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+
+	if err := c.Validate(&signup{Name: "Ada", Age: 36}); err != nil {
+		t.Fatalf("valid struct: %v", err)
+	}
+
+	var verrs validation.ValidationErrors
+	err := c.Validate(&signup{Name: "Al", Age: 12})
+	if !errors.As(err, &verrs) || len(verrs) != 2 {
+		t.Fatalf("err = %v, want two ValidationErrors", err)
+	}
+
+	if err := c.ValidateVar("ada@example.com", "required,email,max=64"); err != nil {
+		t.Errorf("valid var: %v", err)
+	}
+	err = c.ValidateVar("abc", "required,numeric,len=4")
+	if !errors.As(err, &verrs) || len(verrs) != 2 || verrs[0].Rule != "numeric" || verrs[1].Rule != "len" {
+		t.Errorf("err = %v, want numeric and len failures", err)
+	}
+}
+
+func TestBindSlice(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	bind := func(body string, out interface{}) error {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		return NewContext(httptest.NewRecorder(), req, nil).BindSlice(out)
+	}
+
+	var items []item
+	if err := bind(`[{"sku":"a","qty":1},{"sku":"b","qty":2}]`, &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[1].SKU != "b" || items[1].Qty != 2 {
+		t.Errorf("items = %+v", items)
+	}
+
+	if err := bind(`{"sku":"a"}`, &items); err == nil {
+		t.Error("binding an object did not fail")
+	}
+	if err := bind(`[1,2]`, items); err == nil {
+		t.Error("binding into a non-pointer did not fail")
+	}
+
+	defer func(n int) { MaxBindSliceLength = n }(MaxBindSliceLength)
+	MaxBindSliceLength = 3
+	var ids []int
+	err := bind(`[1,2,3,4,5]`, &ids)
+	if err == nil || !strings.Contains(err.Error(), "maximum of 3 elements") {
+		t.Errorf("oversized array: err = %v", err)
+	}
+	if ids != nil {
+		t.Errorf("oversized array partially bound: %v", ids)
+	}
+}
+
+func TestBindWithDefaults(t *testing.T) {
+	type options struct {
+		Notify bool `json:"notify" default:"true"`
+	}
+	type createPost struct {
+		Title    string   `json:"title"`
+		Status   string   `json:"status" default:"draft"`
+		Priority *int     `json:"priority" default:"3"`
+		Limit    int      `json:"limit" default:"10"`
+		Tags     []string `json:"tags" default:"general,news"`
+		Options  options  `json:"options"`
+	}
+	bind := func(body string) (createPost, error) {
+		var post createPost
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		err := NewContext(httptest.NewRecorder(), req, nil).BindWithDefaults(&post)
+		return post, err
+	}
+
+	post, err := bind(`{"title": "Hello"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Status != "draft" || post.Priority == nil || *post.Priority != 3 || post.Limit != 10 || !post.Options.Notify {
+		t.Errorf("omitted fields = %+v", post)
+	}
+	if len(post.Tags) != 2 || post.Tags[1] != "news" {
+		t.Errorf("tags = %v", post.Tags)
+	}
+
+	// Explicit zero values win over defaults
+	post, err = bind(`{"status": "", "priority": 0, "limit": 0, "tags": [], "options": {"notify": false}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Status != "" || post.Priority == nil || *post.Priority != 0 || post.Limit != 0 || len(post.Tags) != 0 || post.Options.Notify {
+		t.Errorf("explicit zero values = %+v", post)
+	}
+
+	// An empty body keeps every default
+	if post, err = bind(``); err != nil || post.Status != "draft" {
+		t.Errorf("empty body = %+v, %v", post, err)
+	}
+
+	type invalid struct {
+		Count int `json:"count" default:"many"`
+	}
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{}`))
+	if err := NewContext(httptest.NewRecorder(), req, nil).BindWithDefaults(&invalid{}); err == nil {
+		t.Error("invalid default did not fail")
+	}
+}
@@ -1,9 +1,22 @@
 package routing
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taeyelor/golara/framework/container"
+	httpMW "github.com/taeyelor/golara/framework/http"
+	"github.com/taeyelor/golara/framework/view"
 )
 
 // Context provides request context and response helpers
@@ -11,6 +24,12 @@ type Context struct {
 	Writer  http.ResponseWriter
 	Request *http.Request
 	Params  map[string]string
+	views   *view.Engine
+
+	cookieKeys [][]byte
+
+	services *container.Container
+	scope    *container.Container
 }
 
 // NewContext creates a new context instance
@@ -22,6 +41,34 @@ func NewContext(w http.ResponseWriter, r *http.Request, params map[string]string
 	}
 }
 
+// Container returns the request's service scope, created on first use from
+// the router's container (see container.Scope). Singletons resolve to one
+// instance per request, and the scope is disposed when the request ends. It
+// returns nil when the router has no container.
+func (c *Context) Container() *container.Container {
+	if c.scope == nil && c.services != nil {
+		c.scope = c.services.Scope()
+	}
+	return c.scope
+}
+
+// disposeScope closes the request's service scope, if one was created
+func (c *Context) disposeScope() {
+	if c.scope == nil {
+		return
+	}
+	if err := c.scope.Dispose(); err != nil {
+		log.Printf("Failed to dispose request services: %v", err)
+	}
+}
+
+// Principal returns the principal stored by authentication middleware such
+// as httpMW.BearerAuth, or nil for unauthenticated requests
+func (c *Context) Principal() interface{} {
+	principal, _ := httpMW.Principal(c.Request)
+	return principal
+}
+
 // Param gets a URL parameter by name
 func (c *Context) Param(name string) string {
 	return c.Params[name]
@@ -46,6 +93,38 @@ func (c *Context) QueryDefault(name, defaultValue string) string {
 	return value
 }
 
+// QueryInt gets a query parameter as int, returning def if missing or invalid
+func (c *Context) QueryInt(name string, def int) int {
+	value, err := strconv.Atoi(c.Query(name))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// QueryInt64 gets a query parameter as int64, returning def if missing or invalid
+func (c *Context) QueryInt64(name string, def int64) int64 {
+	value, err := strconv.ParseInt(c.Query(name), 10, 64)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// QueryBool gets a query parameter as bool, returning def if missing or invalid
+func (c *Context) QueryBool(name string, def bool) bool {
+	value, err := strconv.ParseBool(c.Query(name))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// QueryArray gets all values of a repeated query parameter (e.g. ?tag=a&tag=b)
+func (c *Context) QueryArray(name string) []string {
+	return c.Request.URL.Query()[name]
+}
+
 // JSON sends a JSON response
 func (c *Context) JSON(statusCode int, data interface{}) error {
 	c.Writer.Header().Set("Content-Type", "application/json")
@@ -67,6 +146,156 @@ func (c *Context) HTML(statusCode int, html string) {
 	c.Writer.Write([]byte(html))
 }
 
+// View renders a template through the application's view engine.
+// Nothing is written to the response if rendering fails, so the caller
+// can decide how to respond to the returned error.
+func (c *Context) View(statusCode int, name string, data view.ViewData) error {
+	if c.views == nil {
+		return fmt.Errorf("no view engine configured")
+	}
+
+	var buf bytes.Buffer
+	if err := c.views.Render(&buf, name, data); err != nil {
+		return err
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	c.Writer.WriteHeader(statusCode)
+	_, err := buf.WriteTo(c.Writer)
+	return err
+}
+
+// Blob sends an in-memory payload with the given content type
+func (c *Context) Blob(statusCode int, contentType string, data []byte) {
+	c.Writer.Header().Set("Content-Type", contentType)
+	c.Writer.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	c.Writer.WriteHeader(statusCode)
+	c.Writer.Write(data)
+}
+
+// File streams a file from disk, detecting its content type.
+// Missing files and directories result in a 404 response.
+func (c *Context) File(path string) {
+	c.serveFile(path, "")
+}
+
+// Download streams a file from disk as an attachment with the given filename.
+// If filename is empty, the base name of path is used.
+func (c *Context) Download(path, filename string) {
+	if filename == "" {
+		filename = filepath.Base(path)
+	}
+	c.serveFile(path, filename)
+}
+
+// Attachment streams r to the client as a download named filename.
+// Content-Length is only sent when size is non-negative; pass -1 for
+// readers of unknown length and the response is sent chunked.
+func (c *Context) Attachment(r io.Reader, filename, contentType string, size int64) error {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": filename,
+	}))
+	if size >= 0 {
+		header.Set("Content-Length", strconv.FormatInt(size, 10))
+	}
+
+	c.Writer.WriteHeader(http.StatusOK)
+	_, err := io.Copy(c.Writer, r)
+	return err
+}
+
+// serveFile streams a file, optionally marking it as an attachment
+func (c *Context) serveFile(path, attachmentName string) {
+	file, err := os.Open(path)
+	if err != nil {
+		http.NotFound(c.Writer, c.Request)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(c.Writer, c.Request)
+		return
+	}
+
+	if attachmentName != "" {
+		c.Writer.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": attachmentName,
+		}))
+	}
+
+	// ServeContent streams the file and handles Range and conditional requests
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), file)
+}
+
+// Cacheable sets the ETag and Last-Modified headers for a dynamic resource and
+// checks them against the request's If-None-Match and If-Modified-Since
+// headers. When the client's copy is still fresh it writes 304 Not Modified
+// and returns false, so the handler should return without rendering:
+//
+//	if !c.Cacheable(post.UpdatedAt, post.Version) {
+//		return
+//	}
+//
+// Either validator may be omitted by passing a zero time or an empty etag.
+func (c *Context) Cacheable(lastModified time.Time, etag string) bool {
+	header := c.Writer.Header()
+	if etag != "" {
+		if !strings.HasSuffix(etag, `"`) {
+			etag = `"` + etag + `"`
+		}
+		header.Set("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return true
+	}
+
+	if c.notModified(lastModified, etag) {
+		c.Writer.WriteHeader(http.StatusNotModified)
+		return false
+	}
+	return true
+}
+
+// notModified evaluates the conditional request headers. If-None-Match takes
+// precedence over If-Modified-Since, as required by RFC 9110.
+func (c *Context) notModified(lastModified time.Time, etag string) bool {
+	if match := c.Request.Header.Get("If-None-Match"); match != "" {
+		if etag == "" {
+			return false
+		}
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := c.Request.Header.Get("If-Modified-Since"); since != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(since)
+		if err != nil {
+			return false
+		}
+		// HTTP dates have second precision
+		return !lastModified.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
+
 // Status sets the HTTP status code
 func (c *Context) Status(statusCode int) {
 	c.Writer.WriteHeader(statusCode)
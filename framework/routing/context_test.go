@@ -0,0 +1,131 @@
+package routing
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taeyelor/golara/framework/container"
+)
+
+type requestCart struct{ closed bool }
+
+func (c *requestCart) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestRequestScopePerRequest(t *testing.T) {
+	services := container.NewContainer()
+	services.Singleton("cart", func() interface{} { return &requestCart{} })
+
+	var carts []*requestCart
+	r := NewRouter()
+	r.SetContainer(services)
+	r.GET("/", func(c *Context) {
+		cart := c.Container().Resolve("cart").(*requestCart)
+		if c.Container().Resolve("cart") != cart {
+			t.Error("cart resolved twice within one request")
+		}
+		carts = append(carts, cart)
+	})
+
+	for i := 0; i < 2; i++ {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	if len(carts) != 2 || carts[0] == carts[1] {
+		t.Fatal("requests shared a scoped singleton")
+	}
+	for i, cart := range carts {
+		if !cart.closed {
+			t.Errorf("cart of request %d not closed after the request", i)
+		}
+	}
+}
+
+func TestAttachmentStreamsReader(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := NewContext(rec, httptest.NewRequest(http.MethodGet, "/export", nil), nil)
+
+	data := "id,name\n1,Ada\n"
+	if err := c.Attachment(bytes.NewBufferString(data), "report 2026.csv", "text/csv", int64(len(data))); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="report 2026.csv"` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/csv" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rec.Header().Get("Content-Length"); got != "14" {
+		t.Errorf("Content-Length = %q, want 14", got)
+	}
+	if rec.Body.String() != data {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+}
+
+func TestAttachmentUnknownSize(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := NewContext(rec, httptest.NewRequest(http.MethodGet, "/export", nil), nil)
+
+	if err := c.Attachment(io.MultiReader(strings.NewReader("a"), strings.NewReader("b")), "data.bin", "", -1); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.Header().Get("Content-Length"); got != "" {
+		t.Errorf("Content-Length = %q, want none", got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if rec.Body.String() != "ab" {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	modified := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	check := func(method string, headers map[string]string, lastModified time.Time, etag string) (bool, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, "/report", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		return NewContext(rec, req, nil).Cacheable(lastModified, etag), rec
+	}
+
+	fresh, rec := check(http.MethodGet, nil, modified, "v1")
+	if !fresh || rec.Header().Get("ETag") != `"v1"` || rec.Header().Get("Last-Modified") != modified.Format(http.TimeFormat) {
+		t.Errorf("first request: fresh = %v, headers = %v", fresh, rec.Header())
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    bool
+	}{
+		{"matching etag", http.MethodGet, map[string]string{"If-None-Match": `"v0", "v1"`}, false},
+		{"weak etag", http.MethodHead, map[string]string{"If-None-Match": `W/"v1"`}, false},
+		{"changed etag", http.MethodGet, map[string]string{"If-None-Match": `"v0"`}, true},
+		{"etag wins over date", http.MethodGet, map[string]string{"If-None-Match": `"v0"`, "If-Modified-Since": modified.Format(http.TimeFormat)}, true},
+		{"not modified since", http.MethodGet, map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, false},
+		{"modified since", http.MethodGet, map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)}, true},
+		{"unsafe method", http.MethodPost, map[string]string{"If-None-Match": `"v1"`}, true},
+	}
+	for _, tt := range tests {
+		got, rec := check(tt.method, tt.headers, modified, "v1")
+		if got != tt.want {
+			t.Errorf("%s: Cacheable = %v, want %v", tt.name, got, tt.want)
+		}
+		if !tt.want && rec.Code != http.StatusNotModified {
+			t.Errorf("%s: status = %d, want 304", tt.name, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package routing
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// cookieRouter returns a router with /set storing "secret" in a secure
+// cookie and /get echoing it back
+func cookieRouter(keys ...string) *Router {
+	r := NewRouter()
+	r.SetCookieKeys(keys...)
+	r.GET("/set", func(c *Context) {
+		if err := c.SetSecureCookie("session", []byte("secret")); err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+		}
+	})
+	r.GET("/get", func(c *Context) {
+		value, err := c.SecureCookie("session")
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, string(value))
+	})
+	return r
+}
+
+func issueCookie(t *testing.T, r *Router) *http.Cookie {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/set", nil))
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("got %d cookies, want 1 (status %d: %s)", len(cookies), rec.Code, rec.Body.String())
+	}
+	return cookies[0]
+}
+
+func readCookie(r *Router, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/get", nil)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSecureCookieRoundTrip(t *testing.T) {
+	r := cookieRouter("key-1")
+	cookie := issueCookie(t, r)
+
+	if cookie.Value == "secret" || !cookie.HttpOnly {
+		t.Errorf("cookie not encrypted or not HttpOnly: %+v", cookie)
+	}
+	rec := readCookie(r, cookie)
+	if rec.Code != http.StatusOK || rec.Body.String() != "secret" {
+		t.Errorf("GET /get = %d %q, want 200 secret", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSecureCookieDetectsTampering(t *testing.T) {
+	r := cookieRouter("key-1")
+	cookie := issueCookie(t, r)
+
+	sealed, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed[len(sealed)/2] ^= 0xff
+	cookie.Value = base64.RawURLEncoding.EncodeToString(sealed)
+
+	rec := readCookie(r, cookie)
+	if rec.Code != http.StatusBadRequest || rec.Body.String() != ErrInvalidCookie.Error() {
+		t.Errorf("tampered cookie: %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSecureCookieKeyRotation(t *testing.T) {
+	cookie := issueCookie(t, cookieRouter("old"))
+
+	if rec := readCookie(cookieRouter("new", "old"), cookie); rec.Body.String() != "secret" {
+		t.Errorf("cookie from a previous key not accepted: %q", rec.Body.String())
+	}
+	if rec := readCookie(cookieRouter("new"), cookie); rec.Code != http.StatusBadRequest {
+		t.Errorf("cookie from a dropped key accepted")
+	}
+}
+
+func TestSecureCookieWithoutKeys(t *testing.T) {
+	r := NewRouter()
+	var err error
+	r.GET("/", func(c *Context) { err = c.SetSecureCookie("session", []byte("x")) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !errors.Is(err, ErrNoCookieKeys) {
+		t.Errorf("err = %v, want ErrNoCookieKeys", err)
+	}
+}
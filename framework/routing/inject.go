@@ -0,0 +1,102 @@
+package routing
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+)
+
+// contextType is the reflect.Type of *Context
+var contextType = reflect.TypeOf((*Context)(nil))
+
+// validateHandler panics when handler can never be called by the router, so
+// a mistyped handler fails at registration rather than on every request.
+// Whether a service is registered for each injected type is checked per
+// request, since services may be registered after the route or on the
+// request's scope.
+func validateHandler(method, pattern string, handler interface{}) {
+	switch handler.(type) {
+	case func(*Context), func(http.ResponseWriter, *http.Request), http.Handler:
+		return
+	}
+	if err := injectableSignatureError(handler); err != nil {
+		panic(fmt.Sprintf("Invalid handler for %s %s: %v", method, pattern, err))
+	}
+}
+
+// injectableSignatureError explains why handler cannot be an injected
+// handler, a function without results such as func(c *Context, db *database.DB)
+func injectableSignatureError(handler interface{}) error {
+	t := reflect.TypeOf(handler)
+	if t == nil || t.Kind() != reflect.Func {
+		return fmt.Errorf("%T is not a handler function", handler)
+	}
+	if t.NumOut() != 0 {
+		return fmt.Errorf("%s must not return values", t)
+	}
+	if t.IsVariadic() {
+		return fmt.Errorf("%s must not be variadic", t)
+	}
+	for i := 0; i < t.NumIn(); i++ {
+		if !injectableKind(t.In(i).Kind()) {
+			return fmt.Errorf("parameter %d of %s has type %s, which cannot be injected; use a pointer, interface or struct type", i+1, t, t.In(i))
+		}
+	}
+	return nil
+}
+
+// injectableKind reports whether a parameter kind can hold a service.
+// Basic kinds such as strings and ints are ambiguous as service types.
+func injectableKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Pointer, reflect.Interface, reflect.Struct, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return true
+	}
+	return false
+}
+
+// injectHandler calls a handler with *Context parameters set to the request
+// Context and every other parameter resolved by type from the request's
+// service scope. A parameter that cannot be resolved is logged and answered
+// with 500 Internal Server Error without calling the handler.
+func injectHandler(handler interface{}, ctx *Context) http.Handler {
+	fv := reflect.ValueOf(handler)
+	ft := fv.Type()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Pick up writers and requests replaced by middleware
+		ctx.Writer, ctx.Request = w, req
+
+		args := make([]reflect.Value, ft.NumIn())
+		for i := range args {
+			value, err := ctx.injectable(ft.In(i))
+			if err != nil {
+				log.Printf("Handler injection failed for %s %s: %v", req.Method, req.URL.Path, err)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			args[i] = value
+		}
+
+		fv.Call(args)
+	})
+}
+
+// injectable returns the value injected for a handler parameter of type t
+func (c *Context) injectable(t reflect.Type) (reflect.Value, error) {
+	if t == contextType {
+		return reflect.ValueOf(c), nil
+	}
+
+	scope := c.Container()
+	if scope == nil {
+		return reflect.Value{}, fmt.Errorf("cannot inject %s: router has no container", t)
+	}
+
+	instance, err := scope.ResolveType(t)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(instance), nil
+}
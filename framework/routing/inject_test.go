@@ -0,0 +1,78 @@
+package routing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/taeyelor/golara/framework/container"
+)
+
+type greeter struct{ greeting string }
+
+func TestHandlerInjection(t *testing.T) {
+	services := container.NewContainer()
+	services.Instance("greeter", &greeter{"hello"})
+
+	r := NewRouter()
+	r.SetContainer(services)
+	r.GET("/greet/{name}", func(c *Context, g *greeter) {
+		c.String(http.StatusOK, g.greeting+" "+c.Param("name"))
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/greet/ada", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "hello ada" {
+		t.Errorf("GET /greet/ada = %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandlerInjectionUnresolvable(t *testing.T) {
+	r := NewRouter()
+	r.SetContainer(container.NewContainer())
+	called := false
+	r.GET("/", func(c *Context, g *greeter) { called = true })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError || called {
+		t.Errorf("status = %d, called = %v; want 500 without calling the handler", rec.Code, called)
+	}
+}
+
+func TestInvalidHandlersPanicAtRegistration(t *testing.T) {
+	tests := map[string]interface{}{
+		"not a function":  "index.html",
+		"returns a value": func(c *Context) error { return nil },
+		"variadic":        func(c *Context, extra ...*greeter) {},
+		"basic parameter": func(c *Context, id int) {},
+	}
+	for name, handler := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				message, _ := recover().(string)
+				if !strings.Contains(message, "Invalid handler for GET /items") {
+					t.Errorf("panic = %q, want an invalid handler panic", message)
+				}
+			}()
+			NewRouter().GET("/items", handler)
+		})
+	}
+}
+
+func TestGroupAndFallbackValidateHandlers(t *testing.T) {
+	for name, register := range map[string]func(r *Router){
+		"group":    func(r *Router) { r.Group("/api").GET("/items", 42) },
+		"fallback": func(r *Router) { r.Fallback(func() int { return 0 }) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("invalid handler accepted")
+				}
+			}()
+			register(NewRouter())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ContextMiddleware is middleware working on the request Context. Call next
+// to continue the chain, or respond and return without calling it to stop:
+//
+//	func RequireUser(next func(*routing.Context)) func(*routing.Context) {
+//	    return func(c *routing.Context) {
+//	        if c.GetHeader("X-User") == "" {
+//	            c.JSON(401, map[string]string{"error": "unauthorized"})
+//	            return
+//	        }
+//	        next(c)
+//	    }
+//	}
+type ContextMiddleware func(next func(*Context)) func(*Context)
+
+// contextKey stores the route Context in the request's context
+type contextKey struct{}
+
+// withContext attaches the route Context to its request
+func withContext(c *Context) {
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), contextKey{}, c))
+}
+
+// contextFromRequest returns the route Context attached to a request
+func contextFromRequest(req *http.Request) (*Context, bool) {
+	c, ok := req.Context().Value(contextKey{}).(*Context)
+	return c, ok
+}
+
+// adaptMiddleware converts a supported middleware style to http middleware:
+// func(http.Handler) http.Handler, ContextMiddleware or its underlying
+// func(func(*Context)) func(*Context). It panics for other types, since
+// that is a programming error in route registration.
+func adaptMiddleware(middleware interface{}) func(http.Handler) http.Handler {
+	switch mw := middleware.(type) {
+	case func(http.Handler) http.Handler:
+		return mw
+	case ContextMiddleware:
+		return contextMiddlewareAdapter(mw)
+	case func(func(*Context)) func(*Context):
+		return contextMiddlewareAdapter(mw)
+	default:
+		panic(fmt.Sprintf("unsupported middleware type %T", middleware))
+	}
+}
+
+// contextMiddlewareAdapter runs Context middleware inside an http middleware
+// chain. Writer and request changes made by outer http middleware are
+// visible on the Context, and changes made through the Context are passed on.
+func contextMiddlewareAdapter(mw ContextMiddleware) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			c, ok := contextFromRequest(req)
+			if !ok {
+				c = NewContext(w, req, map[string]string{})
+			}
+			c.Writer, c.Request = w, req
+
+			mw(func(c *Context) {
+				next.ServeHTTP(c.Writer, c.Request)
+			})(c)
+		})
+	}
+}
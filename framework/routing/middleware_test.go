@@ -0,0 +1,123 @@
+package routing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpMW "github.com/taeyelor/golara/framework/http"
+)
+
+// recordHTTP is http style middleware appending name to the X-Trace header
+func recordHTTP(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			req.Header.Add("X-Trace", name)
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// recordContext is Context style middleware appending name to the X-Trace header
+func recordContext(name string) ContextMiddleware {
+	return func(next func(*Context)) func(*Context) {
+		return func(c *Context) {
+			c.Request.Header.Add("X-Trace", name)
+			next(c)
+		}
+	}
+}
+
+func TestGroupMiddlewareStylesAndOrder(t *testing.T) {
+	r := NewRouter()
+	r.Use(recordHTTP("global"))
+	trace := func(c *Context) {
+		c.String(http.StatusOK, strings.Join(c.Request.Header.Values("X-Trace"), ","))
+	}
+
+	api := r.Group("/api", recordHTTP("group-http"), recordContext("group-context"))
+	api.Use(func(next func(*Context)) func(*Context) {
+		return func(c *Context) {
+			c.Request.Header.Add("X-Trace", "group-func")
+			next(c)
+		}
+	})
+	api.GET("/users", trace).Use(recordHTTP("route"))
+	api.POST("/users", trace)
+	r.GET("/health", trace)
+
+	tests := map[string]string{
+		"GET /api/users":  "global,group-http,group-context,group-func,route",
+		"POST /api/users": "global,group-http,group-context,group-func",
+		"GET /health":     "global",
+	}
+	for request, want := range tests {
+		method, target, _ := strings.Cut(request, " ")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		if rec.Body.String() != want {
+			t.Errorf("%s ran %q, want %q", request, rec.Body.String(), want)
+		}
+	}
+}
+
+func TestGroupContextMiddlewareCanStop(t *testing.T) {
+	r := NewRouter()
+	admin := r.Group("/admin", ContextMiddleware(func(next func(*Context)) func(*Context) {
+		return func(c *Context) {
+			if c.GetHeader("X-User") == "" {
+				c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			next(c)
+		}
+	}))
+	admin.GET("/stats", func(c *Context) { c.String(http.StatusOK, "stats") })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status without user = %d, want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/stats", nil)
+	req.Header.Set("X-User", "ada")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "stats" {
+		t.Errorf("with user = %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestUnsupportedGroupMiddlewarePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Group accepted an unsupported middleware type")
+		}
+	}()
+	NewRouter().Group("/api", func() {})
+}
+
+func TestGroupAuthSetsPrincipal(t *testing.T) {
+	r := NewRouter()
+	api := r.Group("/api", httpMW.APIKeyAuth("X-API-Key", []string{"secret"}))
+	api.GET("/me", func(c *Context) {
+		key, _ := c.Principal().(string)
+		c.String(http.StatusOK, key)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/me", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	req.Header.Set("X-API-Key", "secret")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "secret" {
+		t.Errorf("authenticated response = %d %q, want the key as principal", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,89 @@
+package routing
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// OpenAPIInfo holds the info section of a generated OpenAPI document
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// nonAlphanumeric matches characters that can't appear in an operation ID
+var nonAlphanumeric = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// OpenAPISpec generates a minimal OpenAPI 3 JSON document from the registered
+// routes. It lists paths, methods and path parameters; request and response
+// schemas are not inferred and can be added by enriching the document.
+func (r *Router) OpenAPISpec(info OpenAPIInfo) ([]byte, error) {
+	if info.Title == "" {
+		info.Title = "API"
+	}
+	if info.Version == "" {
+		info.Version = "1.0.0"
+	}
+
+	paths := make(map[string]map[string]interface{})
+	for _, route := range r.routes {
+		path := openAPIPath(route.Pattern)
+		operations, exists := paths[path]
+		if !exists {
+			operations = make(map[string]interface{})
+			paths[path] = operations
+		}
+
+		operation := map[string]interface{}{
+			"operationId": operationID(route),
+			"responses": map[string]interface{}{
+				"200": map[string]string{"description": "OK"},
+			},
+		}
+
+		if len(route.paramNames) > 0 {
+			parameters := make([]map[string]interface{}, 0, len(route.paramNames))
+			for _, name := range route.paramNames {
+				parameters = append(parameters, map[string]interface{}{
+					"name":     name,
+					"in":       "path",
+					"required": true,
+					"schema":   map[string]string{"type": "string"},
+				})
+			}
+			operation["parameters"] = parameters
+		}
+
+		operations[strings.ToLower(route.Method)] = operation
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    info,
+		"paths":   paths,
+	}, "", "  ")
+}
+
+// openAPIPath converts a route pattern to an OpenAPI path template. OpenAPI
+// has no wildcard syntax, so {path...} is listed as {path}.
+func openAPIPath(pattern string) string {
+	return routeParam.ReplaceAllStringFunc(pattern, func(param string) string {
+		name, _ := strings.CutSuffix(param[1:len(param)-1], "...")
+		return "{" + name + "}"
+	})
+}
+
+// operationID returns the route's name, or derives an operation ID from the
+// route method and pattern for unnamed routes
+func operationID(route *Route) string {
+	if route.name != "" {
+		return route.name
+	}
+	path := strings.Trim(nonAlphanumeric.ReplaceAllString(route.Pattern, "_"), "_")
+	if path == "" {
+		path = "root"
+	}
+	return strings.ToLower(route.Method) + "_" + path
+}
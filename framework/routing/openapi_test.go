@@ -0,0 +1,82 @@
+package routing
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type openAPIDoc struct {
+	OpenAPI string `json:"openapi"`
+	Info    OpenAPIInfo
+	Paths   map[string]map[string]struct {
+		OperationID string `json:"operationId"`
+		Parameters  []struct {
+			Name     string `json:"name"`
+			In       string `json:"in"`
+			Required bool   `json:"required"`
+		} `json:"parameters"`
+	} `json:"paths"`
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	r := NewRouter()
+	r.GET("/users", func(c *Context) {}).Name("users.index")
+	r.POST("/users", func(c *Context) {})
+	r.GET("/users/{id}/posts/{post}", func(c *Context) {})
+	r.Group("/api").GET("/files/{path...}", func(c *Context) {})
+	r.Static("/assets", t.TempDir())
+
+	data, err := r.OpenAPISpec(OpenAPIInfo{Title: "Test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc openAPIDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.OpenAPI != "3.0.3" || doc.Info.Title != "Test" || doc.Info.Version != "1.0.0" {
+		t.Errorf("header = %q %+v", doc.OpenAPI, doc.Info)
+	}
+
+	users := doc.Paths["/users"]
+	if users["get"].OperationID != "users.index" {
+		t.Errorf("GET /users operationId = %q, want users.index", users["get"].OperationID)
+	}
+	if users["post"].OperationID != "post_users" {
+		t.Errorf("POST /users operationId = %q, want post_users", users["post"].OperationID)
+	}
+
+	params := doc.Paths["/users/{id}/posts/{post}"]["get"].Parameters
+	if len(params) != 2 || params[0].Name != "id" || params[1].Name != "post" || params[0].In != "path" || !params[0].Required {
+		t.Errorf("parameters = %+v", params)
+	}
+
+	for _, path := range []string{"/api/files/{path}", "/assets/{path}"} {
+		op, ok := doc.Paths[path]["get"]
+		if !ok {
+			t.Errorf("%s missing from spec; paths = %v", path, keys(doc.Paths))
+			continue
+		}
+		if len(op.Parameters) != 1 || op.Parameters[0].Name != "path" {
+			t.Errorf("%s parameters = %+v", path, op.Parameters)
+		}
+	}
+	if _, ok := doc.Paths["/assets/{path}"]["head"]; !ok {
+		t.Error("HEAD /assets/{path} missing from spec")
+	}
+	for path := range doc.Paths {
+		if strings.Contains(path, "...") {
+			t.Errorf("path %q still has wildcard syntax", path)
+		}
+	}
+}
+
+func keys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -4,13 +4,24 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/taeyelor/golara/framework/container"
+	httpMW "github.com/taeyelor/golara/framework/http"
+	"github.com/taeyelor/golara/framework/view"
 )
 
 // Router handles HTTP routing
 type Router struct {
 	routes      []*Route
 	middlewares []func(http.Handler) http.Handler
+	views       *view.Engine
+	cookieKeys  atomic.Pointer[[][]byte]
+	fallback    *Route
+	container   *container.Container
 }
 
 // Route represents a single route
@@ -21,6 +32,7 @@ type Route struct {
 	Middlewares []func(http.Handler) http.Handler
 	regex       *regexp.Regexp
 	paramNames  []string
+	name        string
 }
 
 // Group represents a route group
@@ -43,12 +55,26 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// Find matching route
 	route, params := r.findRoute(req.Method, req.URL.Path)
 	if route == nil {
-		http.NotFound(w, req)
-		return
+		if r.fallback == nil || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+			// Unmatched requests still pass through global middleware so
+			// CORS can answer preflights and logging and metrics see 404s
+			r.applyGlobal(r.unmatchedHandler(req)).ServeHTTP(w, req)
+			return
+		}
+		route, params = r.fallback, make(map[string]string)
 	}
 
 	// Create context with parameters
 	ctx := NewContext(w, req, params)
+	ctx.views = r.views
+	if keys := r.cookieKeys.Load(); keys != nil {
+		ctx.cookieKeys = *keys
+	}
+	ctx.services = r.container
+	defer ctx.disposeScope()
+	withContext(ctx)
+	ctx.Request.Pattern = route.Pattern
+	req = ctx.Request
 
 	// Build middleware chain
 	handler := r.buildHandler(route.Handler, ctx)
@@ -58,12 +84,45 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		handler = route.Middlewares[i](handler)
 	}
 
-	// Apply global middleware
+	r.applyGlobal(handler).ServeHTTP(w, req)
+}
+
+// applyGlobal wraps handler in the global middleware
+func (r *Router) applyGlobal(handler http.Handler) http.Handler {
 	for i := len(r.middlewares) - 1; i >= 0; i-- {
 		handler = r.middlewares[i](handler)
 	}
+	return handler
+}
 
-	handler.ServeHTTP(w, req)
+// unmatchedHandler answers a request that matched no route. OPTIONS
+// requests for a path served by other methods get 204 No Content with an
+// Allow header; everything else gets 404.
+func (r *Router) unmatchedHandler(req *http.Request) http.Handler {
+	if req.Method == http.MethodOptions {
+		if allowed := r.allowedMethods(req.URL.Path); len(allowed) > 0 {
+			allow := strings.Join(append(allowed, http.MethodOptions), ", ")
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Allow", allow)
+				w.WriteHeader(http.StatusNoContent)
+			})
+		}
+	}
+	return http.HandlerFunc(http.NotFound)
+}
+
+// allowedMethods returns the methods of the routes matching path
+func (r *Router) allowedMethods(path string) []string {
+	var methods []string
+	for _, route := range r.routes {
+		if slices.Contains(methods, route.Method) {
+			continue
+		}
+		if route.regex != nil && route.regex.MatchString(path) || route.regex == nil && route.Pattern == path {
+			methods = append(methods, route.Method)
+		}
+	}
+	return methods
 }
 
 // findRoute finds a matching route for the given method and path
@@ -96,6 +155,8 @@ func (r *Router) buildHandler(handler interface{}, ctx *Context) http.Handler {
 	switch h := handler.(type) {
 	case func(*Context):
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			// Pick up writers and requests replaced by middleware
+			ctx.Writer, ctx.Request = w, req
 			h(ctx)
 		})
 	case func(http.ResponseWriter, *http.Request):
@@ -105,14 +166,16 @@ func (r *Router) buildHandler(handler interface{}, ctx *Context) http.Handler {
 	case http.HandlerFunc:
 		return h
 	default:
-		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-			http.Error(w, "Invalid handler type", http.StatusInternalServerError)
-		})
+		// Other handler types are rejected by validateHandler at registration
+		return injectHandler(handler, ctx)
 	}
 }
 
-// addRoute adds a new route to the router
-func (r *Router) addRoute(method, pattern string, handler interface{}) {
+// addRoute adds a new route to the router. It panics if the handler type
+// is not supported (see validateHandler).
+func (r *Router) addRoute(method, pattern string, handler interface{}) *Route {
+	validateHandler(method, pattern, handler)
+
 	route := &Route{
 		Method:      method,
 		Pattern:     pattern,
@@ -126,23 +189,32 @@ func (r *Router) addRoute(method, pattern string, handler interface{}) {
 	}
 
 	r.routes = append(r.routes, route)
+	return route
 }
 
-// compilePattern compiles a route pattern with parameters into a regex
+// compilePattern compiles a route pattern with parameters into a regex.
+// {name} matches a single path segment; a trailing {name...} matches the
+// rest of the path, slashes included.
 func (r *Router) compilePattern(pattern string) (*regexp.Regexp, []string) {
 	var paramNames []string
 	regexPattern := pattern
 
 	// Find all parameters in the pattern
-	paramRegex := regexp.MustCompile(`\{([^}]+)\}`)
-	matches := paramRegex.FindAllStringSubmatch(pattern, -1)
+	matches := routeParam.FindAllStringSubmatch(pattern, -1)
 
 	for _, match := range matches {
-		paramName := match[1]
+		paramName, wildcard := strings.CutSuffix(match[1], "...")
 		paramNames = append(paramNames, paramName)
 
 		// Replace {param} with capturing group
-		regexPattern = strings.Replace(regexPattern, match[0], `([^/]+)`, 1)
+		group := `([^/]+)`
+		if wildcard {
+			if !strings.HasSuffix(pattern, match[0]) {
+				panic(fmt.Sprintf("Invalid route pattern: %s: %s must be the last segment", pattern, match[0]))
+			}
+			group = `(.*)`
+		}
+		regexPattern = strings.Replace(regexPattern, match[0], group, 1)
 	}
 
 	regex, err := regexp.Compile("^" + regexPattern + "$")
@@ -154,24 +226,68 @@ func (r *Router) compilePattern(pattern string) (*regexp.Regexp, []string) {
 }
 
 // HTTP method methods
-func (r *Router) GET(path string, handler interface{}) {
-	r.addRoute("GET", path, handler)
+func (r *Router) GET(path string, handler interface{}) *Route {
+	return r.addRoute("GET", path, handler)
+}
+
+func (r *Router) POST(path string, handler interface{}) *Route {
+	return r.addRoute("POST", path, handler)
+}
+
+func (r *Router) PUT(path string, handler interface{}) *Route {
+	return r.addRoute("PUT", path, handler)
 }
 
-func (r *Router) POST(path string, handler interface{}) {
-	r.addRoute("POST", path, handler)
+func (r *Router) DELETE(path string, handler interface{}) *Route {
+	return r.addRoute("DELETE", path, handler)
 }
 
-func (r *Router) PUT(path string, handler interface{}) {
-	r.addRoute("PUT", path, handler)
+func (r *Router) PATCH(path string, handler interface{}) *Route {
+	return r.addRoute("PATCH", path, handler)
 }
 
-func (r *Router) DELETE(path string, handler interface{}) {
-	r.addRoute("DELETE", path, handler)
+// Use adds middleware to the route
+func (rt *Route) Use(middlewares ...func(http.Handler) http.Handler) *Route {
+	rt.Middlewares = append(rt.Middlewares, middlewares...)
+	return rt
+}
+
+// RateLimit limits each client IP to limit requests per window on this route
+func (rt *Route) RateLimit(limit int, window time.Duration) *Route {
+	return rt.Use(httpMW.RateLimitPer(limit, window))
+}
+
+// Fallback registers a handler for GET and HEAD requests that match no route,
+// e.g. to serve a single-page application's index.html. Registered routes
+// always take precedence; other methods still receive 404.
+func (r *Router) Fallback(handler interface{}) *Route {
+	validateHandler(http.MethodGet, "fallback", handler)
+
+	r.fallback = &Route{
+		Method:      http.MethodGet,
+		Handler:     handler,
+		Middlewares: make([]func(http.Handler) http.Handler, 0),
+	}
+	return r.fallback
 }
 
-func (r *Router) PATCH(path string, handler interface{}) {
-	r.addRoute("PATCH", path, handler)
+// SetViewEngine sets the view engine used by Context.View
+func (r *Router) SetViewEngine(engine *view.Engine) {
+	r.views = engine
+	engine.SetRouteResolver(r.URL)
+}
+
+// SetContainer sets the container from which each request's service scope
+// is created (see Context.Container)
+func (r *Router) SetContainer(c *container.Container) {
+	r.container = c
+}
+
+// Routes returns all registered routes in registration order
+func (r *Router) Routes() []*Route {
+	routes := make([]*Route, len(r.routes))
+	copy(routes, r.routes)
+	return routes
 }
 
 // Use adds global middleware
@@ -179,43 +295,61 @@ func (r *Router) Use(middleware func(http.Handler) http.Handler) {
 	r.middlewares = append(r.middlewares, middleware)
 }
 
-// Group creates a new route group
-func (r *Router) Group(prefix string, middlewares ...func(http.Handler) http.Handler) *Group {
-	return &Group{
-		router:      r,
-		prefix:      strings.TrimSuffix(prefix, "/"),
-		middlewares: middlewares,
+// Group creates a new route group. Middleware may be http style
+// (func(http.Handler) http.Handler) or Context style (ContextMiddleware) and
+// runs after global middleware and before route middleware.
+func (r *Router) Group(prefix string, middlewares ...interface{}) *Group {
+	group := &Group{
+		router: r,
+		prefix: strings.TrimSuffix(prefix, "/"),
+	}
+	return group.Use(middlewares...)
+}
+
+// Use adds http or Context style middleware to routes registered on the
+// group afterwards
+func (g *Group) Use(middlewares ...interface{}) *Group {
+	for _, middleware := range middlewares {
+		g.middlewares = append(g.middlewares, adaptMiddleware(middleware))
 	}
+	return g
 }
 
 // Group methods
-func (g *Group) GET(path string, handler interface{}) {
-	g.addRoute("GET", path, handler)
+func (g *Group) GET(path string, handler interface{}) *Route {
+	return g.addRoute("GET", path, handler)
 }
 
-func (g *Group) POST(path string, handler interface{}) {
-	g.addRoute("POST", path, handler)
+func (g *Group) POST(path string, handler interface{}) *Route {
+	return g.addRoute("POST", path, handler)
 }
 
-func (g *Group) PUT(path string, handler interface{}) {
-	g.addRoute("PUT", path, handler)
+func (g *Group) PUT(path string, handler interface{}) *Route {
+	return g.addRoute("PUT", path, handler)
 }
 
-func (g *Group) DELETE(path string, handler interface{}) {
-	g.addRoute("DELETE", path, handler)
+func (g *Group) DELETE(path string, handler interface{}) *Route {
+	return g.addRoute("DELETE", path, handler)
 }
 
-func (g *Group) PATCH(path string, handler interface{}) {
-	g.addRoute("PATCH", path, handler)
+func (g *Group) PATCH(path string, handler interface{}) *Route {
+	return g.addRoute("PATCH", path, handler)
 }
 
-func (g *Group) addRoute(method, path string, handler interface{}) {
+func (g *Group) addRoute(method, path string, handler interface{}) *Route {
 	fullPath := g.prefix + path
+
+	// Copy group middleware so per-route additions don't leak into other routes
+	middlewares := make([]func(http.Handler) http.Handler, len(g.middlewares))
+	copy(middlewares, g.middlewares)
+
+	validateHandler(method, fullPath, handler)
+
 	route := &Route{
 		Method:      method,
 		Pattern:     fullPath,
 		Handler:     handler,
-		Middlewares: g.middlewares,
+		Middlewares: middlewares,
 	}
 
 	// Compile regex for parameterized routes
@@ -224,4 +358,5 @@ func (g *Group) addRoute(method, path string, handler interface{}) {
 	}
 
 	g.router.routes = append(g.router.routes, route)
+	return route
 }
@@ -0,0 +1,154 @@
+package routing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpMW "github.com/taeyelor/golara/framework/http"
+)
+
+func TestRouterCORSPreflight(t *testing.T) {
+	r := NewRouter()
+	r.Use(httpMW.CORS(httpMW.CORSConfig{AllowOrigins: []string{"https://app.example.com"}}))
+	r.POST("/users/{id}", func(c *Context) { c.String(http.StatusOK, "ok") })
+
+	req := httptest.NewRequest(http.MethodOptions, "/users/42", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got == "" {
+		t.Error("Access-Control-Allow-Methods not set")
+	}
+}
+
+func TestRouterOptionsWithoutCORS(t *testing.T) {
+	r := NewRouter()
+	r.GET("/items", func(c *Context) {})
+	r.POST("/items", func(c *Context) {})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/items", nil))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if got, want := rec.Header().Get("Allow"), "GET, POST, OPTIONS"; got != want {
+		t.Errorf("Allow = %q, want %q", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status for unknown path = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestRouterNotFoundRunsGlobalMiddleware(t *testing.T) {
+	r := NewRouter()
+	var seen int
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			seen++
+			next.ServeHTTP(w, req)
+		})
+	})
+	r.GET("/", func(c *Context) {})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if seen != 1 {
+		t.Errorf("global middleware ran %d times, want 1", seen)
+	}
+}
+
+func TestRouterParamsAndWildcard(t *testing.T) {
+	r := NewRouter()
+	r.GET("/users/{id}", func(c *Context) { c.String(http.StatusOK, c.Param("id")) })
+	r.GET("/files/{path...}", func(c *Context) { c.String(http.StatusOK, c.Param("path")) })
+
+	tests := map[string]string{
+		"/users/7":         "7",
+		"/files/a/b/c.txt": "a/b/c.txt",
+		"/files/":          "",
+	}
+	for target, want := range tests {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("GET %s = %d %q, want 200 %q", target, rec.Code, rec.Body.String(), want)
+		}
+	}
+}
+
+func TestRouteRateLimit(t *testing.T) {
+	r := NewRouter()
+	r.POST("/login", func(c *Context) { c.String(http.StatusOK, "ok") }).RateLimit(3, time.Minute)
+	r.GET("/", func(c *Context) { c.String(http.StatusOK, "ok") })
+
+	send := func(method, target, remoteAddr string) int {
+		req := httptest.NewRequest(method, target, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 1; i <= 3; i++ {
+		if code := send(http.MethodPost, "/login", "10.0.0.1:1234"); code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i, code)
+		}
+	}
+	if code := send(http.MethodPost, "/login", "10.0.0.1:5678"); code != http.StatusTooManyRequests {
+		t.Errorf("request over the limit: status = %d, want 429", code)
+	}
+	if code := send(http.MethodPost, "/login", "10.0.0.2:1234"); code != http.StatusOK {
+		t.Errorf("another client: status = %d, want 200", code)
+	}
+	for i := 0; i < 5; i++ {
+		if code := send(http.MethodGet, "/", "10.0.0.1:1234"); code != http.StatusOK {
+			t.Fatalf("unlimited route: status = %d, want 200", code)
+		}
+	}
+}
+
+func TestRouterFallback(t *testing.T) {
+	r := NewRouter()
+	r.GET("/api/users", func(c *Context) { c.String(http.StatusOK, "users") })
+	r.Fallback(func(c *Context) { c.String(http.StatusOK, "index.html") })
+
+	tests := []struct {
+		method string
+		target string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/api/users", http.StatusOK, "users"},
+		{http.MethodGet, "/dashboard/settings", http.StatusOK, "index.html"},
+		{http.MethodHead, "/dashboard", http.StatusOK, ""},
+		{http.MethodPost, "/dashboard", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+		if rec.Code != tt.code {
+			t.Errorf("%s %s: status = %d, want %d", tt.method, tt.target, rec.Code, tt.code)
+		}
+		if tt.body != "" && rec.Body.String() != tt.body {
+			t.Errorf("%s %s: body = %q, want %q", tt.method, tt.target, rec.Body.String(), tt.body)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package routing
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SSEStream emits Server-Sent Events to the client
+type SSEStream struct {
+	writer     http.ResponseWriter
+	controller *http.ResponseController
+	request    *http.Request
+}
+
+// SSE starts a Server-Sent Events stream. It returns an error if the
+// response writer doesn't support flushing. Middleware writers are
+// unwrapped through http.ResponseController.
+func (c *Context) SSE() (*SSEStream, error) {
+	if !canFlush(c.Writer) {
+		return nil, fmt.Errorf("response writer does not support flushing")
+	}
+	controller := http.NewResponseController(c.Writer)
+
+	// Streams outlive the server's WriteTimeout; lift it for this response
+	controller.SetWriteDeadline(time.Time{})
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.WriteHeader(http.StatusOK)
+	if err := controller.Flush(); err != nil {
+		return nil, err
+	}
+
+	return &SSEStream{
+		writer:     c.Writer,
+		controller: controller,
+		request:    c.Request,
+	}, nil
+}
+
+// canFlush reports whether w, or a writer it wraps, implements http.Flusher
+func canFlush(w http.ResponseWriter) bool {
+	for {
+		switch t := w.(type) {
+		case http.Flusher:
+			return true
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return false
+		}
+	}
+}
+
+// Send emits an event and flushes it to the client. An empty event name
+// sends an unnamed message. It returns the request context's error once
+// the client has disconnected.
+func (s *SSEStream) Send(event, data string) error {
+	if err := s.request.Context().Err(); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if event != "" {
+		fmt.Fprintf(&b, "event: %s\n", event)
+	}
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+
+	if _, err := s.writer.Write([]byte(b.String())); err != nil {
+		return err
+	}
+	return s.controller.Flush()
+}
+
+// Done returns a channel that is closed when the client disconnects
+func (s *SSEStream) Done() <-chan struct{} {
+	return s.request.Context().Done()
+}
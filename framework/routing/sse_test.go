@@ -0,0 +1,53 @@
+package routing
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpMW "github.com/taeyelor/golara/framework/http"
+)
+
+func TestSSEThroughLoggingMiddleware(t *testing.T) {
+	r := NewRouter()
+	r.Use(httpMW.StructuredLogging(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	r.GET("/events", func(c *Context) {
+		stream, err := c.SSE()
+		if err != nil {
+			t.Errorf("SSE: %v", err)
+			return
+		}
+		if err := stream.Send("tick", "one\ntwo"); err != nil {
+			t.Errorf("Send: %v", err)
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if want := "event: tick\ndata: one\ndata: two\n\n"; rec.Body.String() != want {
+		t.Errorf("body = %q, want %q", rec.Body.String(), want)
+	}
+	if !rec.Flushed {
+		t.Error("stream was not flushed")
+	}
+}
+
+type plainWriter struct{ http.ResponseWriter }
+
+func TestSSEWithoutFlusher(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := NewContext(plainWriter{rec}, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+
+	if _, err := c.SSE(); err == nil {
+		t.Fatal("SSE succeeded on a writer that cannot flush")
+	}
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 || len(rec.Header()) != 0 {
+		t.Error("SSE wrote a response before failing")
+	}
+}
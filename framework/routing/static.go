@@ -0,0 +1,94 @@
+package routing
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// StaticConfig configures a static file mount
+type StaticConfig struct {
+	// Browse enables directory listings for directories without an
+	// index.html; listings are disabled by default
+	Browse bool
+
+	// CacheControl is sent as the Cache-Control header of every file,
+	// e.g. "public, max-age=86400"
+	CacheControl string
+}
+
+// Static serves the files under dir for GET and HEAD requests below
+// urlPrefix, so "/assets/css/app.css" serves dir/css/app.css:
+//
+//	router.Static("/assets", "public")
+//	router.Static("/downloads", "files", routing.StaticConfig{CacheControl: "no-cache"})
+//
+// Requests that match no file receive 404.
+func (r *Router) Static(urlPrefix, dir string, config ...StaticConfig) *Route {
+	var cfg StaticConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	prefix := strings.TrimSuffix(urlPrefix, "/")
+	handler := staticHandler(http.Dir(dir), cfg)
+
+	r.addRoute(http.MethodHead, prefix+"/{path...}", handler)
+	return r.addRoute(http.MethodGet, prefix+"/{path...}", handler)
+}
+
+// staticHandler serves files from root using the "path" route parameter
+func staticHandler(root http.FileSystem, cfg StaticConfig) func(*Context) {
+	if !cfg.Browse {
+		root = noListingFileSystem{root}
+	}
+	fileServer := http.FileServer(root)
+
+	return func(c *Context) {
+		if cfg.CacheControl != "" {
+			c.Writer.Header().Set("Cache-Control", cfg.CacheControl)
+		}
+
+		// Keep the trailing slash so directories serve their index.html
+		// instead of redirecting
+		name := path.Clean("/" + c.Param("path"))
+		if strings.HasSuffix(c.Param("path"), "/") && name != "/" {
+			name += "/"
+		}
+
+		req := c.Request.Clone(c.Request.Context())
+		req.URL.Path = name
+		req.URL.RawPath = ""
+		fileServer.ServeHTTP(c.Writer, req)
+	}
+}
+
+// noListingFileSystem hides directories that have no index.html, so the
+// file server answers 404 instead of listing their contents
+type noListingFileSystem struct {
+	fs http.FileSystem
+}
+
+func (nfs noListingFileSystem) Open(name string) (http.File, error) {
+	file, err := nfs.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		index, err := nfs.fs.Open(path.Join(name, "index.html"))
+		if err != nil {
+			file.Close()
+			return nil, os.ErrNotExist
+		}
+		index.Close()
+	}
+
+	return file, nil
+}
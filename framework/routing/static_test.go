@@ -0,0 +1,93 @@
+package routing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func staticRouter(t *testing.T, config ...StaticConfig) *Router {
+	t.Helper()
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		full := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("index.html", "root index")
+	write("css/app.css", "body{}")
+	write("docs/index.html", "docs index")
+	write("raw/data.txt", "data")
+
+	r := NewRouter()
+	r.Static("/assets", dir, config...)
+	return r
+}
+
+func serve(r *Router, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestStaticServesFilesAndDirectoryIndexes(t *testing.T) {
+	r := staticRouter(t, StaticConfig{CacheControl: "no-cache"})
+
+	tests := []struct {
+		target string
+		body   string
+	}{
+		{"/assets/css/app.css", "body{}"},
+		{"/assets/docs/", "docs index"},
+		{"/assets/", "root index"},
+	}
+	for _, tt := range tests {
+		rec := serve(r, tt.target)
+		if rec.Code != http.StatusOK || rec.Body.String() != tt.body {
+			t.Errorf("GET %s = %d %q, want 200 %q", tt.target, rec.Code, rec.Body.String(), tt.body)
+		}
+		if got := rec.Header().Get("Cache-Control"); got != "no-cache" {
+			t.Errorf("GET %s Cache-Control = %q", tt.target, got)
+		}
+	}
+}
+
+func TestStaticRedirectsToCanonicalDirectory(t *testing.T) {
+	r := staticRouter(t)
+
+	for _, target := range []string{"/assets/docs", "/assets/docs/index.html"} {
+		rec := serve(r, target)
+		if rec.Code != http.StatusMovedPermanently {
+			t.Errorf("GET %s = %d, want 301", target, rec.Code)
+			continue
+		}
+		base, _ := url.Parse(target)
+		location, _ := url.Parse(rec.Header().Get("Location"))
+		if got := base.ResolveReference(location).Path; got != "/assets/docs/" {
+			t.Errorf("GET %s redirects to %s, want /assets/docs/", target, got)
+		}
+	}
+}
+
+func TestStaticHidesListingsAndTraversal(t *testing.T) {
+	r := staticRouter(t)
+
+	for _, target := range []string{"/assets/raw/", "/assets/missing.txt", "/assets/../static_test.go"} {
+		if rec := serve(r, target); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", target, rec.Code)
+		}
+	}
+
+	r = staticRouter(t, StaticConfig{Browse: true})
+	if rec := serve(r, "/assets/raw/"); rec.Code != http.StatusOK {
+		t.Errorf("GET /assets/raw/ with Browse = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,74 @@
+package routing
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// routeParam matches {name} and {name...} in route patterns
+var routeParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Name names the route so its URL can be generated with Router.URL
+func (rt *Route) Name(name string) *Route {
+	rt.name = name
+	return rt
+}
+
+// RouteName returns the route's name, or "" if it has none
+func (rt *Route) RouteName() string {
+	return rt.name
+}
+
+// URL builds the path of a named route, substituting params into its
+// pattern:
+//
+//	router.GET("/users/{id}", showUser).Name("users.show")
+//	router.URL("users.show", map[string]string{"id": "42"}) // "/users/42"
+//
+// Values are path-escaped; slashes in a {name...} wildcard are kept. It
+// returns an error if no route has the name or a parameter is missing.
+func (r *Router) URL(name string, params map[string]string) (string, error) {
+	route := r.namedRoute(name)
+	if route == nil {
+		return "", fmt.Errorf("route '%s' not found", name)
+	}
+
+	var missing []string
+	path := routeParam.ReplaceAllStringFunc(route.Pattern, func(match string) string {
+		paramName, wildcard := strings.CutSuffix(match[1:len(match)-1], "...")
+		value, ok := params[paramName]
+		if !ok {
+			missing = append(missing, paramName)
+			return match
+		}
+		if !wildcard {
+			return url.PathEscape(value)
+		}
+
+		segments := strings.Split(value, "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		return strings.Join(segments, "/")
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("route '%s' is missing parameters: %s", name, strings.Join(missing, ", "))
+	}
+	return path, nil
+}
+
+// namedRoute returns the first route registered with name
+func (r *Router) namedRoute(name string) *Route {
+	if name == "" {
+		return nil
+	}
+	for _, route := range r.routes {
+		if route.name == name {
+			return route
+		}
+	}
+	return nil
+}
@@ -0,0 +1,419 @@
+// Package validation provides struct and value validation driven by rule strings
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Field describes the value a rule is applied to
+type Field struct {
+	Name   string              // field name as reported in errors
+	Value  reflect.Value       // field value
+	Param  string              // rule parameter, e.g. "3" for min=3
+	Parent reflect.Value       // struct containing the field (invalid for single values)
+	Struct reflect.StructField // struct field metadata (zero for single values)
+}
+
+// Rule validates a field and returns an error describing the failure
+type Rule func(field Field) error
+
+// FieldError describes a single failed rule
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is the list of failed rules returned by Struct and Var
+type ValidationErrors []FieldError
+
+// Error implements the error interface
+func (ve ValidationErrors) Error() string {
+	messages := make([]string, len(ve))
+	for i, fe := range ve {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Fields groups the error messages by field name
+func (ve ValidationErrors) Fields() map[string][]string {
+	fields := make(map[string][]string)
+	for _, fe := range ve {
+		fields[fe.Field] = append(fields[fe.Field], fe.Message)
+	}
+	return fields
+}
+
+// Validator validates structs using `validate` tags and single values using rule strings
+type Validator struct {
+	rules map[string]Rule
+	mutex sync.RWMutex
+}
+
+// defaultValidator backs the package-level functions
+var defaultValidator = New()
+
+// New creates a validator with the built-in rules registered
+func New() *Validator {
+	v := &Validator{rules: make(map[string]Rule)}
+
+	v.RegisterRule("required", ruleRequired)
+	v.RegisterRule("min", ruleMin)
+	v.RegisterRule("max", ruleMax)
+	v.RegisterRule("len", ruleLen)
+	v.RegisterRule("email", ruleEmail)
+	v.RegisterRule("url", ruleURL)
+	v.RegisterRule("numeric", ruleNumeric)
+	v.RegisterRule("alpha", ruleAlpha)
+	v.RegisterRule("alphanum", ruleAlphaNum)
+	v.RegisterRule("in", ruleIn)
+	v.RegisterRule("regex", ruleRegex)
+
+	return v
+}
+
+// RegisterRule registers a custom rule, replacing any rule with the same name
+func (v *Validator) RegisterRule(name string, rule Rule) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.rules[name] = rule
+}
+
+// Struct validates a struct using its `validate` tags, e.g. `validate:"required,min=3"`.
+// It returns ValidationErrors when any rule fails.
+func (v *Validator) Struct(obj interface{}) error {
+	rv := reflect.ValueOf(obj)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return fmt.Errorf("validation requires a non-nil struct")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validation requires a struct, got %s", rv.Kind())
+	}
+
+	var errs ValidationErrors
+	if err := v.validateStruct(rv, &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Var validates a single value against a rule string, e.g. "required,email"
+func (v *Validator) Var(value interface{}, rules string) error {
+	var errs ValidationErrors
+	field := Field{Name: "value", Value: reflect.ValueOf(value)}
+	if err := v.apply(field, rules, &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateStruct applies tag rules to every field, descending into embedded structs
+func (v *Validator) validateStruct(rv reflect.Value, errs *ValidationErrors) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if err := v.validateStruct(rv.Field(i), errs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rules := sf.Tag.Get("validate")
+		if rules == "" || rules == "-" {
+			continue
+		}
+
+		field := Field{Name: fieldName(sf), Value: rv.Field(i), Parent: rv, Struct: sf}
+		if err := v.apply(field, rules, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply runs each rule of a rule string against a field. Rules are skipped
+// for empty optional fields (fields without "required").
+func (v *Validator) apply(field Field, rules string, errs *ValidationErrors) error {
+	parts := splitRules(rules)
+	required := false
+	for _, part := range parts {
+		if strings.TrimSpace(part) == "required" {
+			required = true
+		}
+	}
+	if !required && isEmpty(field.Value) {
+		return nil
+	}
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, param, _ := strings.Cut(part, "=")
+
+		v.mutex.RLock()
+		rule, exists := v.rules[name]
+		v.mutex.RUnlock()
+		if !exists {
+			return fmt.Errorf("unknown validation rule '%s'", name)
+		}
+
+		field.Param = param
+		if err := rule(field); err != nil {
+			*errs = append(*errs, FieldError{
+				Field:   field.Name,
+				Rule:    name,
+				Param:   param,
+				Message: err.Error(),
+			})
+			if name == "required" {
+				// Other rules are meaningless for a missing value
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// fieldName returns the JSON name of a struct field, falling back to its Go name
+func fieldName(sf reflect.StructField) string {
+	if tag := sf.Tag.Get("json"); tag != "" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+// Package-level helpers using the default validator
+
+// Struct validates a struct with the default validator
+func Struct(obj interface{}) error {
+	return defaultValidator.Struct(obj)
+}
+
+// Var validates a single value with the default validator
+func Var(value interface{}, rules string) error {
+	return defaultValidator.Var(value, rules)
+}
+
+// RegisterRule registers a custom rule on the default validator
+func RegisterRule(name string, rule Rule) {
+	defaultValidator.RegisterRule(name, rule)
+}
+
+// Built-in rules
+
+func ruleRequired(f Field) error {
+	if isEmpty(f.Value) {
+		return fmt.Errorf("%s is required", f.Name)
+	}
+	return nil
+}
+
+func ruleMin(f Field) error {
+	limit, err := strconv.ParseFloat(f.Param, 64)
+	if err != nil {
+		return fmt.Errorf("%s has an invalid min rule", f.Name)
+	}
+	size, isLength := measure(f.Value)
+	if size < limit {
+		if isLength {
+			return fmt.Errorf("%s must be at least %s characters or items", f.Name, f.Param)
+		}
+		return fmt.Errorf("%s must be at least %s", f.Name, f.Param)
+	}
+	return nil
+}
+
+func ruleMax(f Field) error {
+	limit, err := strconv.ParseFloat(f.Param, 64)
+	if err != nil {
+		return fmt.Errorf("%s has an invalid max rule", f.Name)
+	}
+	size, isLength := measure(f.Value)
+	if size > limit {
+		if isLength {
+			return fmt.Errorf("%s must be at most %s characters or items", f.Name, f.Param)
+		}
+		return fmt.Errorf("%s must be at most %s", f.Name, f.Param)
+	}
+	return nil
+}
+
+func ruleLen(f Field) error {
+	limit, err := strconv.ParseFloat(f.Param, 64)
+	if err != nil {
+		return fmt.Errorf("%s has an invalid len rule", f.Name)
+	}
+	if size, _ := measure(f.Value); size != limit {
+		return fmt.Errorf("%s must have a length of %s", f.Name, f.Param)
+	}
+	return nil
+}
+
+func ruleEmail(f Field) error {
+	addr, err := mail.ParseAddress(stringOf(f.Value))
+	if err != nil || addr.Address != stringOf(f.Value) {
+		return fmt.Errorf("%s must be a valid email address", f.Name)
+	}
+	return nil
+}
+
+func ruleURL(f Field) error {
+	u, err := url.ParseRequestURI(stringOf(f.Value))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%s must be a valid URL", f.Name)
+	}
+	return nil
+}
+
+func ruleNumeric(f Field) error {
+	if _, err := strconv.ParseFloat(stringOf(f.Value), 64); err != nil {
+		return fmt.Errorf("%s must be numeric", f.Name)
+	}
+	return nil
+}
+
+func ruleAlpha(f Field) error {
+	for _, r := range stringOf(f.Value) {
+		if !unicode.IsLetter(r) {
+			return fmt.Errorf("%s may only contain letters", f.Name)
+		}
+	}
+	return nil
+}
+
+func ruleAlphaNum(f Field) error {
+	for _, r := range stringOf(f.Value) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return fmt.Errorf("%s may only contain letters and numbers", f.Name)
+		}
+	}
+	return nil
+}
+
+func ruleIn(f Field) error {
+	value := stringOf(f.Value)
+	for _, option := range strings.Split(f.Param, "|") {
+		if value == option {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must be one of: %s", f.Name, strings.ReplaceAll(f.Param, "|", ", "))
+}
+
+func ruleRegex(f Field) error {
+	re, err := regexp.Compile(f.Param)
+	if err != nil {
+		return fmt.Errorf("%s has an invalid regex rule", f.Name)
+	}
+	if !re.MatchString(stringOf(f.Value)) {
+		return fmt.Errorf("%s has an invalid format", f.Name)
+	}
+	return nil
+}
+
+// Helpers
+
+// splitRules splits a rule string on commas. A comma inside a rule parameter
+// is written as \, e.g. `regex=^[a-z]{2\,5}$`.
+func splitRules(rules string) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(rules); i++ {
+		switch {
+		case rules[i] == '\\' && i+1 < len(rules) && rules[i+1] == ',':
+			part.WriteByte(',')
+			i++
+		case rules[i] == ',':
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(rules[i])
+		}
+	}
+	return append(parts, part.String())
+}
+
+// isEmpty checks if a value is missing or the zero value of its type
+func isEmpty(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// measure returns the numeric value of a number, or the length of a string or
+// collection (reported by the second return value)
+func measure(v reflect.Value) (float64, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return 0, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return float64(len([]rune(v.String()))), true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), false
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false
+	}
+	return 0, false
+}
+
+// stringOf returns the string form of a value
+func stringOf(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	if !v.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
@@ -0,0 +1,87 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitRules(t *testing.T) {
+	tests := map[string][]string{
+		"required,email":              {"required", "email"},
+		`regex=^[a-z]{2\,5}$`:         {"regex=^[a-z]{2,5}$"},
+		`required,regex=^\d{1\,3}$,x`: {"required", `regex=^\d{1,3}$`, "x"},
+		`regex=a\b`:                   {`regex=a\b`},
+	}
+	for rules, want := range tests {
+		if got := splitRules(rules); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitRules(%q) = %q, want %q", rules, got, want)
+		}
+	}
+}
+
+func TestRegexRuleWithComma(t *testing.T) {
+	type input struct {
+		Code string `validate:"required,regex=^[a-z]{2\\,5}$"`
+	}
+
+	if err := Struct(&input{Code: "abc"}); err != nil {
+		t.Errorf("valid code: %v", err)
+	}
+	err := Struct(&input{Code: "abcdefg"})
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Rule != "regex" || verrs[0].Param != "^[a-z]{2,5}$" {
+		t.Errorf("invalid code: %v", err)
+	}
+
+	if err := Var("x1", `regex=^[a-z]\d{1\,2}$`); err != nil {
+		t.Errorf("Var with escaped comma: %v", err)
+	}
+}
+
+func TestStruct(t *testing.T) {
+	type user struct {
+		Name  string `json:"name" validate:"required,min=3"`
+		Email string `json:"email" validate:"required,email"`
+		Role  string `json:"role" validate:"in=admin|user"`
+		Note  string `json:"note" validate:"max=5"`
+	}
+
+	if err := Struct(&user{Name: "Ada", Email: "ada@example.com", Role: "admin"}); err != nil {
+		t.Fatalf("valid struct: %v", err)
+	}
+
+	err := Struct(user{Name: "Al", Email: "nope", Role: "root"})
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("err = %v, want ValidationErrors", err)
+	}
+	fields := verrs.Fields()
+	for _, name := range []string{"name", "email", "role"} {
+		if len(fields[name]) != 1 {
+			t.Errorf("%s errors = %v, want one", name, fields[name])
+		}
+	}
+	if _, ok := fields["note"]; ok {
+		t.Error("empty optional field was validated")
+	}
+
+	if err := Struct(nil); err == nil || errors.As(err, &verrs) {
+		t.Errorf("Struct(nil) = %v, want a plain error", err)
+	}
+}
+
+func TestVar(t *testing.T) {
+	if err := Var("ada@example.com", "required,email"); err != nil {
+		t.Errorf("valid email: %v", err)
+	}
+	if err := Var("", "required,email"); err == nil {
+		t.Error("empty required value passed")
+	}
+	if err := Var("", "email"); err != nil {
+		t.Errorf("empty optional value: %v", err)
+	}
+	if err := Var("x", "nosuchrule"); err == nil {
+		t.Error("unknown rule did not fail")
+	}
+}
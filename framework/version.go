@@ -0,0 +1,70 @@
+package framework
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/taeyelor/golara/framework/routing"
+)
+
+// VersionInfo describes the running build
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// SetVersion records the build metadata, typically injected with ldflags:
+//
+//	go build -ldflags "-X main.version=1.2.0 -X main.commit=$(git rev-parse HEAD)"
+func (app *Application) SetVersion(version, commit, buildTime string) {
+	app.version = VersionInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+	}
+}
+
+// VersionInfo returns the build metadata. Values not set with SetVersion fall
+// back to the VCS information embedded by the Go toolchain, then to "dev"
+// and "unknown".
+func (app *Application) VersionInfo() VersionInfo {
+	info := app.version
+	info.GoVersion = runtime.Version()
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+
+	return info
+}
+
+// EnableVersionEndpoint registers a GET endpoint serving VersionInfo (default "/version")
+func (app *Application) EnableVersionEndpoint(path string) {
+	if path == "" {
+		path = "/version"
+	}
+
+	app.GET(path, func(c *routing.Context) {
+		c.JSON(http.StatusOK, app.VersionInfo())
+	})
+}
@@ -0,0 +1,41 @@
+package framework
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestVersionInfoDefaults(t *testing.T) {
+	info := NewApplication().VersionInfo()
+	// Test binaries carry no VCS stamp
+	if info.Version != "dev" || info.Commit != "unknown" || info.BuildTime != "unknown" {
+		t.Errorf("defaults = %+v", info)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion = %q, want %q", info.GoVersion, runtime.Version())
+	}
+}
+
+func TestVersionEndpoint(t *testing.T) {
+	app := NewApplication()
+	app.SetVersion("1.4.2", "abc123", "2026-10-01T12:00:00Z")
+	app.EnableVersionEndpoint("")
+
+	rec := httptest.NewRecorder()
+	app.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var info VersionInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+	want := VersionInfo{Version: "1.4.2", Commit: "abc123", BuildTime: "2026-10-01T12:00:00Z", GoVersion: runtime.Version()}
+	if info != want {
+		t.Errorf("version info = %+v, want %+v", info, want)
+	}
+}
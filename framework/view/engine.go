@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"io/fs"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -11,12 +13,17 @@ import (
 
 // Engine represents the view engine
 type Engine struct {
-	templates map[string]*template.Template
-	viewsDir  string
-	extension string
-	funcMap   template.FuncMap
-	mutex     sync.RWMutex
-	debug     bool
+	templates  map[string]*template.Template
+	paths      map[string]string
+	viewsDir   string
+	layoutsDir string
+	extension  string
+	funcMap    template.FuncMap
+	mutex      sync.RWMutex
+	debug      bool
+	files      map[string]string
+	filesMux   sync.RWMutex
+	routeURL   func(name string, params map[string]string) (string, error)
 }
 
 // ViewData represents data passed to views
@@ -25,11 +32,14 @@ type ViewData map[string]interface{}
 // NewEngine creates a new view engine
 func NewEngine(viewsDir string) *Engine {
 	return &Engine{
-		templates: make(map[string]*template.Template),
-		viewsDir:  viewsDir,
-		extension: ".html",
-		funcMap:   make(template.FuncMap),
-		debug:     false,
+		templates:  make(map[string]*template.Template),
+		paths:      make(map[string]string),
+		viewsDir:   viewsDir,
+		layoutsDir: "layouts",
+		extension:  ".html",
+		funcMap:    make(template.FuncMap),
+		debug:      false,
+		files:      make(map[string]string),
 	}
 }
 
@@ -38,11 +48,25 @@ func (e *Engine) SetExtension(ext string) {
 	e.extension = ext
 }
 
+// SetLayoutsDir sets the layouts directory, relative to the views directory.
+// Every layout is parsed together with each page, so a page can call
+// {{template "layouts/app" .}} and override the layout's blocks with
+// {{define "content"}}...{{end}}.
+func (e *Engine) SetLayoutsDir(dir string) {
+	e.layoutsDir = dir
+}
+
 // SetDebug enables/disables debug mode (recompiles templates on each render)
 func (e *Engine) SetDebug(debug bool) {
 	e.debug = debug
 }
 
+// SetRouteResolver sets the function behind the route template helper,
+// normally the router's URL method
+func (e *Engine) SetRouteResolver(resolver func(name string, params map[string]string) (string, error)) {
+	e.routeURL = resolver
+}
+
 // AddFunc adds a template function
 func (e *Engine) AddFunc(name string, fn interface{}) {
 	e.funcMap[name] = fn
@@ -50,8 +74,17 @@ func (e *Engine) AddFunc(name string, fn interface{}) {
 
 // LoadTemplates loads all templates from the views directory
 func (e *Engine) LoadTemplates() error {
-	pattern := filepath.Join(e.viewsDir, "**/*"+e.extension)
-	files, err := filepath.Glob(pattern)
+	// Collect templates at any depth (filepath.Glob doesn't support **)
+	var files []string
+	err := filepath.WalkDir(e.viewsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, e.extension) {
+			files = append(files, path)
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
@@ -60,6 +93,9 @@ func (e *Engine) LoadTemplates() error {
 	e.addDefaultFunctions()
 
 	for _, file := range files {
+		if e.isLayout(file) {
+			continue
+		}
 		if err := e.loadTemplate(file); err != nil {
 			return err
 		}
@@ -68,38 +104,89 @@ func (e *Engine) LoadTemplates() error {
 	return nil
 }
 
-// loadTemplate loads a single template file
+// loadTemplate loads a single template file together with all layouts
 func (e *Engine) loadTemplate(file string) error {
-	// Get template name relative to views directory
-	relPath, err := filepath.Rel(e.viewsDir, file)
+	name, err := e.templateName(file)
 	if err != nil {
 		return err
 	}
 
-	// Remove extension and normalize path separators
-	name := strings.TrimSuffix(relPath, e.extension)
-	name = filepath.ToSlash(name)
+	tmpl := template.New(name).Funcs(e.funcMap)
+
+	// Parse layouts first so blocks defined by the page override them
+	layouts, err := e.layoutFiles()
+	if err != nil {
+		return err
+	}
+	for _, layout := range layouts {
+		layoutName, err := e.templateName(layout)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(layout)
+		if err != nil {
+			return err
+		}
+		if _, err := tmpl.New(layoutName).Parse(string(content)); err != nil {
+			return err
+		}
+	}
 
-	tmpl, err := template.New(name).Funcs(e.funcMap).ParseFiles(file)
+	content, err := os.ReadFile(file)
 	if err != nil {
 		return err
 	}
+	if _, err := tmpl.Parse(string(content)); err != nil {
+		return err
+	}
 
 	e.mutex.Lock()
 	e.templates[name] = tmpl
+	e.paths[name] = file
 	e.mutex.Unlock()
 
 	return nil
 }
 
+// templateName returns the template name of a file: its path relative to the
+// views directory, without extension and with forward slashes
+func (e *Engine) templateName(file string) (string, error) {
+	relPath, err := filepath.Rel(e.viewsDir, file)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(strings.TrimSuffix(relPath, e.extension)), nil
+}
+
+// layoutFiles returns all layout template files
+func (e *Engine) layoutFiles() ([]string, error) {
+	if e.layoutsDir == "" {
+		return nil, nil
+	}
+	return filepath.Glob(filepath.Join(e.viewsDir, e.layoutsDir, "*"+e.extension))
+}
+
+// isLayout checks if a file lives in the layouts directory
+func (e *Engine) isLayout(file string) bool {
+	if e.layoutsDir == "" {
+		return false
+	}
+	return filepath.Dir(file) == filepath.Join(e.viewsDir, e.layoutsDir)
+}
+
 // Render renders a template to the given writer
 func (e *Engine) Render(w io.Writer, name string, data ViewData) error {
 	var tmpl *template.Template
 	var exists bool
 
 	if e.debug {
-		// In debug mode, reload template
-		file := filepath.Join(e.viewsDir, name+e.extension)
+		// In debug mode, reload template from the file it was originally loaded from
+		e.mutex.RLock()
+		file, known := e.paths[name]
+		e.mutex.RUnlock()
+		if !known {
+			file = filepath.Join(e.viewsDir, filepath.FromSlash(name)+e.extension)
+		}
 		if err := e.loadTemplate(file); err != nil {
 			return err
 		}
@@ -147,6 +234,21 @@ func (e *Engine) addDefaultFunctions() {
 		return "/" + path
 	}
 
+	// Named route helper: {{route "users.show" "id" .User.ID}}
+	e.funcMap["route"] = func(name string, pairs ...interface{}) (string, error) {
+		if e.routeURL == nil {
+			return "", fmt.Errorf("route helper used without a router")
+		}
+		if len(pairs)%2 != 0 {
+			return "", fmt.Errorf("route %s: parameters must be name/value pairs", name)
+		}
+		params := make(map[string]string, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			params[fmt.Sprint(pairs[i])] = fmt.Sprint(pairs[i+1])
+		}
+		return e.routeURL(name, params)
+	}
+
 	// Asset helper
 	e.funcMap["asset"] = func(path string) string {
 		return "/assets/" + strings.TrimPrefix(path, "/")
@@ -166,6 +268,34 @@ func (e *Engine) addDefaultFunctions() {
 		return result
 	}
 
+	// Raw file includes (relative to the views directory)
+	e.funcMap["embed"] = func(path string, escape ...bool) (interface{}, error) {
+		content, err := e.readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(escape) > 0 && escape[0] {
+			return content, nil
+		}
+		return template.HTML(content), nil
+	}
+
+	// Inline assets, useful for emails that can't reference external files
+	e.funcMap["css"] = func(path string) (template.HTML, error) {
+		content, err := e.readFile(path)
+		if err != nil {
+			return "", err
+		}
+		return template.HTML("<style>" + content + "</style>"), nil
+	}
+	e.funcMap["js"] = func(path string) (template.HTML, error) {
+		content, err := e.readFile(path)
+		if err != nil {
+			return "", err
+		}
+		return template.HTML("<script>" + content + "</script>"), nil
+	}
+
 	// Default value
 	e.funcMap["default"] = func(defaultVal, val interface{}) interface{} {
 		if val == nil || val == "" {
@@ -175,6 +305,40 @@ func (e *Engine) addDefaultFunctions() {
 	}
 }
 
+// readFile reads a file relative to the views directory, caching its content
+// unless debug mode is enabled. Paths escaping the views directory are rejected.
+func (e *Engine) readFile(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("invalid file path '%s'", path)
+	}
+	for _, segment := range strings.Split(filepath.ToSlash(path), "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("invalid file path '%s'", path)
+		}
+	}
+
+	if !e.debug {
+		e.filesMux.RLock()
+		content, exists := e.files[path]
+		e.filesMux.RUnlock()
+		if exists {
+			return content, nil
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(e.viewsDir, filepath.FromSlash(path)))
+	if err != nil {
+		return "", err
+	}
+
+	content := string(data)
+	e.filesMux.Lock()
+	e.files[path] = content
+	e.filesMux.Unlock()
+
+	return content, nil
+}
+
 // ParseString parses a template string and returns a template
 func (e *Engine) ParseString(name, content string) (*template.Template, error) {
 	return template.New(name).Funcs(e.funcMap).Parse(content)
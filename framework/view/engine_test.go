@@ -0,0 +1,103 @@
+package view
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeViews creates files under a temporary views directory
+func writeViews(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestEmbedIncludesFragments(t *testing.T) {
+	dir := writeViews(t, map[string]string{
+		"email.html":           `{{embed "fragments/footer.txt"}}|{{embed "fragments/footer.txt" true}}|{{css "assets/mail.css"}}|{{js "assets/mail.js"}}`,
+		"fragments/footer.txt": `<b>Thanks</b>`,
+		"assets/mail.css":      `p{color:red}`,
+		"assets/mail.js":       `init()`,
+	})
+	engine := NewEngine(dir)
+	if err := engine.LoadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := engine.RenderString("email", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<b>Thanks</b>|&lt;b&gt;Thanks&lt;/b&gt;|<style>p{color:red}</style>|<script>init()</script>`
+	if got != want {
+		t.Errorf("rendered %q\nwant     %q", got, want)
+	}
+}
+
+func TestEmbedRejectsTraversal(t *testing.T) {
+	dir := writeViews(t, map[string]string{"page.html": `ok`})
+	if err := os.WriteFile(filepath.Join(filepath.Dir(dir), "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngine(dir)
+	if err := engine.LoadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"../secret.txt", "fragments/../../secret.txt", filepath.Join(filepath.Dir(dir), "secret.txt")} {
+		got, err := engine.RenderStringTemplate(`{{embed "`+filepath.ToSlash(path)+`"}}`, nil)
+		if err == nil || strings.Contains(got, "secret") {
+			t.Errorf("embed %q = %q, %v; want an error", path, got, err)
+		}
+	}
+}
+
+func TestDebugReloadsNestedTemplate(t *testing.T) {
+	dir := writeViews(t, map[string]string{
+		"admin/users/index.html": `v1`,
+	})
+	engine := NewEngine(dir)
+	engine.SetDebug(true)
+	if err := engine.LoadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := engine.RenderString("admin/users/index", nil); err != nil || got != "v1" {
+		t.Fatalf("first render = %q, %v", got, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "admin", "users", "index.html"), []byte(`v2`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := engine.RenderString("admin/users/index", nil); err != nil || got != "v2" {
+		t.Errorf("render after edit = %q, %v; want v2", got, err)
+	}
+}
+
+func TestCachedTemplateWithoutDebug(t *testing.T) {
+	dir := writeViews(t, map[string]string{"nested/page.html": `v1`})
+	engine := NewEngine(dir)
+	if err := engine.LoadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "nested", "page.html"), []byte(`v2`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := engine.RenderString("nested/page", nil); got != "v1" {
+		t.Errorf("render = %q, want the cached v1", got)
+	}
+	if _, err := engine.RenderString("missing", nil); err == nil {
+		t.Error("rendering a missing template did not fail")
+	}
+}